@@ -9,8 +9,10 @@
 package config
 
 import (
+	"github.com/inhies/go-bytesize"
 	"github.com/spf13/viper"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -33,8 +35,113 @@ type appConfig struct {
 
 	// slackChatCnl represent slack channel ID to send chat
 	slackChatCnl *string
+
+	// adminAPIToken represent token required in Authorization header of admin http API
+	adminAPIToken *string
+
+	// slackSigningSecret represent secret to verify request of slack command is sent from slack
+	slackSigningSecret *string
+
+	// slackAPIURL represent url of slack API, used for replacing slack API with local stand-in (optional)
+	slackAPIURL *string
+
+	// version represent version of health checker recorded in every history (required)
+	version *string
+
+	// timezone represent name of timezone used for storing & displaying time (Ex, Asia/Seoul)
+	timezone *string
+
+	// kibanaURL represent url of kibana, used for linking history of alert in slack message (optional)
+	kibanaURL *string
+
+	// kibanaIndexPattern represent id of kibana index pattern covering history index, used in link of history (optional)
+	kibanaIndexPattern *string
+
+	// statusStoreType represent type of store keeping status of check process across restart (file or elasticsearch)
+	statusStoreType *string
+
+	// statusFilePath represent path of file storing status of check process if status store type is file
+	statusFilePath *string
+
+	// alertQueueFilePath represent path of file storing alert queue waiting retry
+	alertQueueFilePath *string
+
+	// historySpoolFilePath represent path of file spooling history documents failed to index in elasticsearch
+	historySpoolFilePath *string
+
+	// maintenanceWindowFilePath represent path of file storing maintenance windows added in admin API
+	maintenanceWindowFilePath *string
+
+	// bulkFlushSize represent number of buffered history documents which makes bulk indexer flush before interval
+	bulkFlushSize *int
+
+	// bulkFlushInterval represent interval to flush buffered history documents with bulk API
+	bulkFlushInterval *time.Duration
+
+	// bulkSpoolMaxSize represent max size of history spool file, the oldest documents are dropped when exceeding that size
+	bulkSpoolMaxSize *bytesize.ByteSize
+
+	// alertWebhookURLs represent urls of webhook receiver to post alert in addition to slack (optional)
+	alertWebhookURLs []string
+
+	// alertCooldown represent duration after last occurrence until same failure alert is regarded as new one
+	alertCooldown *time.Duration
+
+	// alertReminderInterval represent interval to send reminder of failure alert which keeps occurring
+	alertReminderInterval *time.Duration
+
+	// maintenanceWindows represent maintenance windows defined in config file
+	maintenanceWindows []MaintenanceWindow
+
+	// escalationRules represent escalation rules defined in config file
+	escalationRules []EscalationRule
+
+	// digestTime represent time of day to post daily & weekly digest as duration since midnight (Ex, 9h for 09:00)
+	digestTime *time.Duration
+
+	// digestWeekday represent weekday to post weekly digest
+	digestWeekday *time.Weekday
+}
+
+// MaintenanceWindow is maintenance window defined in config file, added in maintenance agent in main package
+type MaintenanceWindow struct {
+	Checks     []string  `mapstructure:"checks"`
+	Containers []string  `mapstructure:"containers"`
+	StartAt    time.Time `mapstructure:"-"`
+	EndAt      time.Time `mapstructure:"-"`
+	Reason     string    `mapstructure:"reason"`
+}
+
+// EscalationRule is escalation rule defined in config file, added in escalation agent in main package
+// if channel or webhook url is set, escalated alert is also delivered to that slack channel or webhook
+type EscalationRule struct {
+	Checks        []string      `mapstructure:"checks"`
+	AfterCycles   int           `mapstructure:"after_cycles"`
+	AfterDuration time.Duration `mapstructure:"after_duration"`
+	Mentions      []string      `mapstructure:"mentions"`
+	Channel       string        `mapstructure:"channel"`
+	WebhookURL    string        `mapstructure:"webhook_url"`
 }
 
+// default const variable about alert config used if not set in config file
+const (
+	defaultAlertCooldown         = time.Minute * 30 // default const Duration for alertCooldown
+	defaultAlertReminderInterval = time.Hour * 1    // default const Duration for alertReminderInterval
+)
+
+// default const variable about bulk indexing config used if not set in config file
+const (
+	defaultBulkFlushSize     = 100               // default const int for bulkFlushSize
+	defaultBulkFlushInterval = time.Second * 5   // default const Duration for bulkFlushInterval
+	defaultBulkSpoolMaxSize  = bytesize.MB * 100 // default const ByteSize for bulkSpoolMaxSize
+)
+
+// default const variable about digest config used if not set in config file
+const (
+	defaultDigestTime    = time.Hour * 9 // default const Duration for digestTime (09:00)
+	defaultDigestWeekday = time.Monday   // default const Weekday for digestWeekday
+)
+
 // return elasticsearch address get from environment variable
 func (ac *appConfig) ESAddress() string {
 	if ac.esAddress != nil {
@@ -105,6 +212,335 @@ func (ac *appConfig) SlackChatChannel() string {
 	return *ac.slackChatCnl
 }
 
+// return slack signing secret get from environment variable, empty string if not set (slack command & event is disabled)
+func (ac *appConfig) SlackSigningSecret() string {
+	if ac.slackSigningSecret != nil {
+		return *ac.slackSigningSecret
+	}
+
+	ac.slackSigningSecret = _string(viper.GetString("SLACK_SIGNING_SECRET"))
+	return *ac.slackSigningSecret
+}
+
+// return slack api url get from environment variable, empty string if not set (use slack API url in slack package)
+// url should end with slash, Ex) http://localhost:8080/api/
+func (ac *appConfig) SlackAPIURL() string {
+	if ac.slackAPIURL != nil {
+		return *ac.slackAPIURL
+	}
+
+	ac.slackAPIURL = _string(viper.GetString("SLACK_API_URL"))
+	return *ac.slackAPIURL
+}
+
+// return version of health checker get from environment variable, empty string if not set
+func (ac *appConfig) Version() string {
+	if ac.version != nil {
+		return *ac.version
+	}
+
+	ac.version = _string(viper.GetString("VERSION"))
+	return *ac.version
+}
+
+// return timezone name get from environment variable, Asia/Seoul if not set
+func (ac *appConfig) Timezone() string {
+	if ac.timezone != nil {
+		return *ac.timezone
+	}
+
+	if viper.GetString("TIMEZONE") != "" {
+		ac.timezone = _string(viper.GetString("TIMEZONE"))
+	} else {
+		ac.timezone = _string("Asia/Seoul")
+	}
+	return *ac.timezone
+}
+
+// return kibana url get from environment variable, empty string if not set (no link in slack message)
+func (ac *appConfig) KibanaURL() string {
+	if ac.kibanaURL != nil {
+		return *ac.kibanaURL
+	}
+
+	ac.kibanaURL = _string(strings.TrimSuffix(viper.GetString("KIBANA_URL"), "/"))
+	return *ac.kibanaURL
+}
+
+// return id of kibana index pattern get from environment variable, empty string if not set (default index pattern)
+func (ac *appConfig) KibanaIndexPattern() string {
+	if ac.kibanaIndexPattern != nil {
+		return *ac.kibanaIndexPattern
+	}
+
+	ac.kibanaIndexPattern = _string(viper.GetString("KIBANA_INDEX_PATTERN"))
+	return *ac.kibanaIndexPattern
+}
+
+// return admin api token get from environment variable, empty string if not set (every admin request is denied)
+func (ac *appConfig) AdminAPIToken() string {
+	if ac.adminAPIToken != nil {
+		return *ac.adminAPIToken
+	}
+
+	ac.adminAPIToken = _string(viper.GetString("ADMIN_API_TOKEN"))
+	if *ac.adminAPIToken == "" {
+		log.Println("ADMIN_API_TOKEN is not set in environment variable, admin API is disabled")
+	}
+	return *ac.adminAPIToken
+}
+
+// return status store type get from environment variable, "file" if not set or invalid (file or elasticsearch)
+func (ac *appConfig) StatusStoreType() string {
+	if ac.statusStoreType != nil {
+		return *ac.statusStoreType
+	}
+
+	switch storeType := viper.GetString("STATUS_STORE_TYPE"); storeType {
+	case "file", "elasticsearch":
+		ac.statusStoreType = _string(storeType)
+	default:
+		ac.statusStoreType = _string("file")
+	}
+	return *ac.statusStoreType
+}
+
+// return status file path get from environment variable, default path in container if not set
+func (ac *appConfig) StatusFilePath() string {
+	if ac.statusFilePath != nil {
+		return *ac.statusFilePath
+	}
+
+	if viper.GetString("STATUS_FILE_PATH") != "" {
+		ac.statusFilePath = _string(viper.GetString("STATUS_FILE_PATH"))
+	} else {
+		ac.statusFilePath = _string("/usr/share/health-check/status/status.json")
+	}
+	return *ac.statusFilePath
+}
+
+// return alert queue file path get from environment variable, default path in container if not set
+func (ac *appConfig) AlertQueueFilePath() string {
+	if ac.alertQueueFilePath != nil {
+		return *ac.alertQueueFilePath
+	}
+
+	if viper.GetString("ALERT_QUEUE_FILE_PATH") != "" {
+		ac.alertQueueFilePath = _string(viper.GetString("ALERT_QUEUE_FILE_PATH"))
+	} else {
+		ac.alertQueueFilePath = _string("/usr/share/health-check/status/alert_queue.json")
+	}
+	return *ac.alertQueueFilePath
+}
+
+// return history spool file path get from environment variable, default path in container if not set
+func (ac *appConfig) HistorySpoolFilePath() string {
+	if ac.historySpoolFilePath != nil {
+		return *ac.historySpoolFilePath
+	}
+
+	if viper.GetString("HISTORY_SPOOL_FILE_PATH") != "" {
+		ac.historySpoolFilePath = _string(viper.GetString("HISTORY_SPOOL_FILE_PATH"))
+	} else {
+		ac.historySpoolFilePath = _string("/usr/share/health-check/status/history_spool.jsonl")
+	}
+	return *ac.historySpoolFilePath
+}
+
+// return maintenance window file path get from environment variable, default path in container if not set
+func (ac *appConfig) MaintenanceWindowFilePath() string {
+	if ac.maintenanceWindowFilePath != nil {
+		return *ac.maintenanceWindowFilePath
+	}
+
+	if viper.GetString("MAINTENANCE_WINDOW_FILE_PATH") != "" {
+		ac.maintenanceWindowFilePath = _string(viper.GetString("MAINTENANCE_WINDOW_FILE_PATH"))
+	} else {
+		ac.maintenanceWindowFilePath = _string("/usr/share/health-check/status/maintenance_windows.json")
+	}
+	return *ac.maintenanceWindowFilePath
+}
+
+// return alert webhook urls get from environment variable separated with comma, empty if not set
+func (ac *appConfig) AlertWebhookURLs() []string {
+	if ac.alertWebhookURLs != nil {
+		return ac.alertWebhookURLs
+	}
+
+	ac.alertWebhookURLs = []string{}
+	for _, url := range strings.Split(viper.GetString("ALERT_WEBHOOK_URLS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			ac.alertWebhookURLs = append(ac.alertWebhookURLs, url)
+		}
+	}
+	return ac.alertWebhookURLs
+}
+
+// return alert cooldown get from config file, default value if not set or invalid
+func (ac *appConfig) AlertCooldown() time.Duration {
+	var key = "alert.cooldown"
+	if ac.alertCooldown != nil {
+		return *ac.alertCooldown
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultAlertCooldown.String())
+		d = defaultAlertCooldown
+	}
+
+	ac.alertCooldown = &d
+	return *ac.alertCooldown
+}
+
+// return alert reminder interval get from config file, default value if not set or invalid
+func (ac *appConfig) AlertReminderInterval() time.Duration {
+	var key = "alert.reminderInterval"
+	if ac.alertReminderInterval != nil {
+		return *ac.alertReminderInterval
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultAlertReminderInterval.String())
+		d = defaultAlertReminderInterval
+	}
+
+	ac.alertReminderInterval = &d
+	return *ac.alertReminderInterval
+}
+
+// return bulk flush size get from config file, default value if not set or invalid
+func (ac *appConfig) BulkFlushSize() int {
+	var key = "bulk.flushSize"
+	if ac.bulkFlushSize != nil {
+		return *ac.bulkFlushSize
+	}
+
+	size := viper.GetInt(key)
+	if size <= 0 {
+		viper.Set(key, defaultBulkFlushSize)
+		size = defaultBulkFlushSize
+	}
+
+	ac.bulkFlushSize = &size
+	return *ac.bulkFlushSize
+}
+
+// return bulk flush interval get from config file, default value if not set or invalid
+func (ac *appConfig) BulkFlushInterval() time.Duration {
+	var key = "bulk.flushInterval"
+	if ac.bulkFlushInterval != nil {
+		return *ac.bulkFlushInterval
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultBulkFlushInterval.String())
+		d = defaultBulkFlushInterval
+	}
+
+	ac.bulkFlushInterval = &d
+	return *ac.bulkFlushInterval
+}
+
+// return max size of history spool get from config file, default value if not set or invalid
+func (ac *appConfig) BulkSpoolMaxSize() int64 {
+	var key = "bulk.spoolMaxSize"
+	if ac.bulkSpoolMaxSize != nil {
+		return int64(*ac.bulkSpoolMaxSize)
+	}
+
+	size, err := bytesize.Parse(viper.GetString(key))
+	if err != nil || size == 0 {
+		viper.Set(key, defaultBulkSpoolMaxSize.String())
+		size = defaultBulkSpoolMaxSize
+	}
+
+	ac.bulkSpoolMaxSize = &size
+	return int64(*ac.bulkSpoolMaxSize)
+}
+
+// return maintenance windows get from config file, start_at & end_at should be formatted in RFC3339
+func (ac *appConfig) MaintenanceWindows() []MaintenanceWindow {
+	var key = "maintenance.windows"
+	if ac.maintenanceWindows != nil {
+		return ac.maintenanceWindows
+	}
+
+	var raws []struct {
+		MaintenanceWindow `mapstructure:",squash"`
+		StartAt           string `mapstructure:"start_at"`
+		EndAt             string `mapstructure:"end_at"`
+	}
+	if err := viper.UnmarshalKey(key, &raws); err != nil {
+		log.Fatalf("please set valid %s in config file, err: %v", key, err)
+	}
+
+	ac.maintenanceWindows = []MaintenanceWindow{}
+	for _, raw := range raws {
+		window := raw.MaintenanceWindow
+		var startErr, endErr error
+		window.StartAt, startErr = time.Parse(time.RFC3339, raw.StartAt)
+		window.EndAt, endErr = time.Parse(time.RFC3339, raw.EndAt)
+		if startErr != nil || endErr != nil {
+			log.Fatalf("please set start_at & end_at of %s in RFC3339 format", key)
+		}
+		ac.maintenanceWindows = append(ac.maintenanceWindows, window)
+	}
+	return ac.maintenanceWindows
+}
+
+// return escalation rules get from config file, after_duration should be formatted in duration string (Ex, 30m)
+func (ac *appConfig) EscalationRules() []EscalationRule {
+	var key = "escalation.rules"
+	if ac.escalationRules != nil {
+		return ac.escalationRules
+	}
+
+	ac.escalationRules = []EscalationRule{}
+	if err := viper.UnmarshalKey(key, &ac.escalationRules); err != nil {
+		log.Fatalf("please set valid %s in config file, err: %v", key, err)
+	}
+	return ac.escalationRules
+}
+
+// return time of day to post digest get from config file in HH:MM format, default value if not set or invalid
+func (ac *appConfig) DigestTime() time.Duration {
+	var key = "digest.time"
+	if ac.digestTime != nil {
+		return *ac.digestTime
+	}
+
+	t, err := time.Parse("15:04", viper.GetString(key))
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if err != nil {
+		viper.Set(key, "09:00")
+		d = defaultDigestTime
+	}
+
+	ac.digestTime = &d
+	return *ac.digestTime
+}
+
+// return weekday to post weekly digest get from config file (Ex, Monday), default value if not set or invalid
+func (ac *appConfig) DigestWeekday() time.Weekday {
+	var key = "digest.weekday"
+	if ac.digestWeekday != nil {
+		return *ac.digestWeekday
+	}
+
+	w := defaultDigestWeekday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), viper.GetString(key)) {
+			w = day
+		}
+	}
+
+	ac.digestWeekday = &w
+	return *ac.digestWeekday
+}
+
 // return docker client version as literal
 func (ac *appConfig) DockerCliVer() string {
 	return "1.40"
@@ -115,6 +551,16 @@ func (ac *appConfig) DockerCliTimeout() time.Duration {
 	return time.Second * 5
 }
 
+// return http server listen address as literal (port published in docker-compose.yml)
+func (ac *appConfig) HTTPListenAddress() string {
+	return ":8888"
+}
+
+// return deadline to wait in-flight check process while shutting down as literal (less than stop_grace_period)
+func (ac *appConfig) ShutdownTimeout() time.Duration {
+	return time.Second * 50
+}
+
 func init() {
 	App = &appConfig{}
 }
@@ -5,8 +5,15 @@ package main
 
 import (
 	// import Go SDK package
+	"context"
+	"crypto/sha256"
+	"fmt"
 	"log"
-	"runtime"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	// import external package
@@ -20,22 +27,35 @@ import (
 	"github.com/DMS-SMS/v1-health-check/app/config"
 	"github.com/DMS-SMS/v1-health-check/consul"
 	"github.com/DMS-SMS/v1-health-check/docker"
+	"github.com/DMS-SMS/v1-health-check/domain"
 	"github.com/DMS-SMS/v1-health-check/elasticsearch"
+	"github.com/DMS-SMS/v1-health-check/escalation"
+	"github.com/DMS-SMS/v1-health-check/file"
 	"github.com/DMS-SMS/v1-health-check/grpc"
 	"github.com/DMS-SMS/v1-health-check/json"
+	"github.com/DMS-SMS/v1-health-check/maintenance"
+	"github.com/DMS-SMS/v1-health-check/notifier"
+	"github.com/DMS-SMS/v1-health-check/prometheus"
 	"github.com/DMS-SMS/v1-health-check/slack"
 	"github.com/DMS-SMS/v1-health-check/system"
+	"github.com/DMS-SMS/v1-health-check/webhook"
 
 	// import system check domain package
 	_syscheckConfig "github.com/DMS-SMS/v1-health-check/syscheck/config"
 	_syscheckChanDelivery "github.com/DMS-SMS/v1-health-check/syscheck/delivery/channel"
+	_syscheckHttpDelivery "github.com/DMS-SMS/v1-health-check/syscheck/delivery/http"
+	_syscheckSlackDelivery "github.com/DMS-SMS/v1-health-check/syscheck/delivery/slack"
 	_syscheckRepo "github.com/DMS-SMS/v1-health-check/syscheck/repository/elasticsearch"
+	_syscheckFileRepo "github.com/DMS-SMS/v1-health-check/syscheck/repository/file"
 	_syscheckUcase "github.com/DMS-SMS/v1-health-check/syscheck/usecase"
 
 	// import service check domain package
 	_srvcheckConfig "github.com/DMS-SMS/v1-health-check/srvcheck/config"
 	_srvcheckChanDelivery "github.com/DMS-SMS/v1-health-check/srvcheck/delivery/channel"
+	_srvcheckHttpDelivery "github.com/DMS-SMS/v1-health-check/srvcheck/delivery/http"
+	_srvcheckSlackDelivery "github.com/DMS-SMS/v1-health-check/srvcheck/delivery/slack"
 	_srvcheckRepo "github.com/DMS-SMS/v1-health-check/srvcheck/repository/elasticsearch"
+	_srvcheckFileRepo "github.com/DMS-SMS/v1-health-check/srvcheck/repository/file"
 	_srvcheckUcase "github.com/DMS-SMS/v1-health-check/srvcheck/usecase"
 )
 
@@ -49,6 +69,19 @@ func init() {
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error reading config file, %s", err)
 	}
+
+	// version is recorded in every history, so health checker can't start without it
+	if config.App.Version() == "" {
+		log.Fatal("please set VERSION in environment variable")
+	}
+	domain.SetVersion(config.App.Version())
+
+	// set timezone used for storing & displaying time in domain package
+	location, err := time.LoadLocation(config.App.Timezone())
+	if err != nil {
+		log.Fatalf("please set valid TIMEZONE in environment variable, err: %v", err)
+	}
+	domain.SetLocation(location)
 }
 
 func main() {
@@ -74,46 +107,307 @@ func main() {
 		log.Fatal(errors.Wrap(err, "failed to create consul client"))
 	}
 
-	// add docker, system, slack, elasticsearch agent
+	// add docker, system, slack, elasticsearch, prometheus agent
 	_dkr := docker.NewAgent(dkrCli)
 	_sys := system.NewAgent(dkrCli)
-	_slk := slack.NewAgent(config.App.SlackAPIToken(), config.App.SlackChatChannel())
+	_slk := slack.NewAgent(config.App.SlackAPIToken(), config.App.SlackChatChannel(), config.App.SlackAPIURL(), config.App.KibanaURL(), config.App.KibanaIndexPattern())
 	_es := elasticsearch.NewAgent(esCli)
 	_csl := consul.NewAgent(cslCli)
 	_rpc := grpc.NewGRPCAgent()
+	_prm := prometheus.NewAgent()
+
+	// file agent keeping status of check process, alert queue waiting retry & maintenance window across restart
+	// status store agent is selected with STATUS_STORE_TYPE between file & elasticsearch agent
+	_fil := file.NewAgent(
+		config.App.StatusFilePath(),
+		config.App.AlertQueueFilePath(),
+		config.App.HistorySpoolFilePath(),
+		config.App.MaintenanceWindowFilePath(),
+		config.App.BulkSpoolMaxSize(),
+	)
+	var _sts statusStore = _fil
+	if config.App.StatusStoreType() == "elasticsearch" {
+		_sts = _es
+	}
+
+	// bulk agent indexing history documents of elasticsearch repository with bulk API
+	// documents failed to index are spooled in file agent & replayed in order once elasticsearch recovers
+	_blk := elasticsearch.NewBulkAgent(esCli, _fil, config.App.BulkFlushSize(), config.App.BulkFlushInterval())
+
+	// notifier agent delivering alert to slack & webhook receivers set in ALERT_WEBHOOK_URLS
+	// repeated failure alert is deduplicated in dedup agent shared by all usecases
+	// alert failed to send in slack or webhook is kept in file queue & retried in retry agent, per notifier failed
+	_rty := notifier.NewRetryAgent(_fil)
+	_mnt := notifier.NewMultiAgent(_rty.Decorate("slack", _slk))
+	for _, url := range config.App.AlertWebhookURLs() {
+		_mnt.Append(_rty.Decorate(webhookTargetName(url), webhook.NewAgent(url)))
+	}
+	_ntf := notifier.NewDedupAgent(_mnt, config.App.AlertCooldown(), config.App.AlertReminderInterval())
+
+	// escalation agent re-alerting incident staying unhealthy long with mentions, according to rules in config file
+	// escalated alert is also delivered to second slack channel or webhook set in rule
+	// escalation state is kept in status store agent, so that incident isn't escalated again after restart
+	_esc := escalation.NewAgent(_ntf, _sts)
+	for _, rule := range config.App.EscalationRules() {
+		targets := notifier.NewMultiAgent()
+		if rule.Channel != "" {
+			targets.Append(slack.NewAgent(config.App.SlackAPIToken(), rule.Channel, config.App.SlackAPIURL(), config.App.KibanaURL(), config.App.KibanaIndexPattern()))
+		}
+		if rule.WebhookURL != "" {
+			targets.Append(webhook.NewAgent(rule.WebhookURL))
+		}
+		if err := _esc.AddRule(escalation.Rule{
+			Checks:        rule.Checks,
+			AfterCycles:   rule.AfterCycles,
+			AfterDuration: rule.AfterDuration,
+			Mentions:      rule.Mentions,
+		}, targets); err != nil {
+			log.Fatal(errors.Wrap(err, "failed to add escalation rule in config file"))
+		}
+	}
+
+	// http server router used in http delivery of each domain
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", _prm)
+
+	// root context & wait group used in channel delivery to stop listening & wait in-flight check process
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	var tickers []*time.Ticker
+	tick := func(d time.Duration) <-chan time.Time {
+		ticker := time.NewTicker(d)
+		tickers = append(tickers, ticker)
+		return ticker.C
+	}
+
+	// schedule return channel receiving time at next time returned from function, used in digest delivery
+	schedule := func(next func(t time.Time) time.Time) <-chan time.Time {
+		c := make(chan time.Time)
+		go func() {
+			for {
+				now := domain.Now()
+				timer := time.NewTimer(next(now).Sub(now))
+				select {
+				case t := <-timer.C:
+					select {
+					case c <- t.In(domain.Location()):
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+		}()
+		return c
+	}
+	daily := dailyAt(config.App.DigestTime())
+	weekly := weeklyAt(config.App.DigestWeekday(), config.App.DigestTime())
+
+	// maintenance agent suppressing remediation & alarm of check or container in window defined in config or admin API
+	// window added in admin API is kept in file agent, so that it survives restart
+	_mtn := maintenance.NewAgent(_fil)
+	for _, window := range config.App.MaintenanceWindows() {
+		if _, err := _mtn.AddWindow(domain.MaintenanceWindow{
+			Checks:     window.Checks,
+			Containers: window.Containers,
+			StartAt:    window.StartAt,
+			EndAt:      window.EndAt,
+			Reason:     window.Reason,
+			CreatedBy:  maintenance.ConfigCreator,
+		}); err != nil {
+			log.Fatal(errors.Wrap(err, "failed to add maintenance window in config file"))
+		}
+	}
+
+	// slack command router used in slack delivery of each domain, replying in thread with slack agent
+	// slack command & event are not handled if SLACK_SIGNING_SECRET is not set, as request can't be verified
+	_cmd := slack.NewCommandRouter(_slk, config.App.SlackSigningSecret(), wg)
+	if config.App.SlackSigningSecret() != "" {
+		mux.HandleFunc("/slack/commands", _cmd.ServeSlashCommand)
+		mux.HandleFunc("/slack/events", _cmd.ServeEvent)
+	} else {
+		log.Println("SLACK_SIGNING_SECRET is not set, slack command & event are disabled")
+	}
 
 	// syscheck domain repository
 	// the reason separate Repository, Usecase interface in same domain
-	sdr := _syscheckRepo.NewESDiskCheckHistoryRepository(_syscheckConfig.App, esCli, json.MapWriter())
-	scr := _syscheckRepo.NewESCPUCheckHistoryRepository(_syscheckConfig.App, esCli, json.MapWriter())
-	smr := _syscheckRepo.NewESMemoryCheckHistoryRepository(_syscheckConfig.App, esCli, json.MapWriter())
+	// repository is selected with repository type between elasticsearch & file (JSON-lines file in local)
+	var sdr domain.DiskCheckHistoryRepository
+	var scr domain.CPUCheckHistoryRepository
+	var smr domain.MemoryCheckHistoryRepository
+	switch _syscheckConfig.App.RepositoryType() {
+	case "file":
+		sdr = _syscheckFileRepo.NewFileDiskCheckHistoryRepository(_syscheckConfig.App, json.DottedMapEncoder())
+		scr = _syscheckFileRepo.NewFileCPUCheckHistoryRepository(_syscheckConfig.App, json.DottedMapEncoder())
+		smr = _syscheckFileRepo.NewFileMemoryCheckHistoryRepository(_syscheckConfig.App, json.DottedMapEncoder())
+	default:
+		sdr = _syscheckRepo.NewESDiskCheckHistoryRepository(_syscheckConfig.App, esCli, _blk, json.DottedMapEncoder())
+		scr = _syscheckRepo.NewESCPUCheckHistoryRepository(_syscheckConfig.App, esCli, _blk, json.DottedMapEncoder())
+		smr = _syscheckRepo.NewESMemoryCheckHistoryRepository(_syscheckConfig.App, esCli, _blk, json.DottedMapEncoder())
+
+		// history indices written through alias are rolled over & deleted after retention period in index manager
+		_syscheckRepo.NewESIndexManager(_syscheckConfig.App, esCli).Run(ctx, wg)
+	}
 
 	// syscheck domain usecase
-	sdu := _syscheckUcase.NewDiskCheckUsecase(_syscheckConfig.App, sdr, _slk, _sys)
-	scu := _syscheckUcase.NewCPUCheckUsecase(_syscheckConfig.App, scr, _slk, _sys, _dkr)
-	smu := _syscheckUcase.NewMemoryCheckUsecase(_syscheckConfig.App, smr, _slk, _sys, _dkr)
+	sdu := _syscheckUcase.NewDiskCheckUsecase(_syscheckConfig.App, sdr, _ntf, _prm, _sts, _mtn, _esc, _sys)
+	scu := _syscheckUcase.NewCPUCheckUsecase(_syscheckConfig.App, scr, _ntf, _prm, _sts, _mtn, _esc, _sys, _dkr)
+	smu := _syscheckUcase.NewMemoryCheckUsecase(_syscheckConfig.App, smr, _ntf, _prm, _sts, _mtn, _esc, _sys, _dkr)
+	sdgu := _syscheckUcase.NewDigestUsecase(sdr, scr, smr, _slk)
 
 	// syscheck domain delivery
-	_syscheckChanDelivery.NewDiskCheckHandler(time.Tick(_syscheckConfig.App.DiskCheckDeliveryPingCycle()), sdu)
-	_syscheckChanDelivery.NewCPUCheckHandler(time.Tick(_syscheckConfig.App.CPUCheckDeliveryPingCycle()), scu)
-	_syscheckChanDelivery.NewMemoryCheckHandler(time.Tick(_syscheckConfig.App.MemoryCheckDeliveryPingCycle()), smu)
+	_syscheckChanDelivery.NewDiskCheckHandler(ctx, tick(_syscheckConfig.App.DiskCheckDeliveryPingCycle()), wg, sdu)
+	_syscheckChanDelivery.NewCPUCheckHandler(ctx, tick(_syscheckConfig.App.CPUCheckDeliveryPingCycle()), wg, scu)
+	_syscheckChanDelivery.NewMemoryCheckHandler(ctx, tick(_syscheckConfig.App.MemoryCheckDeliveryPingCycle()), wg, smu)
+	_syscheckChanDelivery.NewDigestHandler(ctx, schedule(daily), schedule(weekly), wg, sdgu)
+	_syscheckHttpDelivery.NewDiskCheckHandler(mux, config.App.AdminAPIToken(), sdu)
+	_syscheckHttpDelivery.NewCPUCheckHandler(mux, config.App.AdminAPIToken(), scu)
+	_syscheckHttpDelivery.NewMemoryCheckHandler(mux, config.App.AdminAPIToken(), smu)
+	_syscheckHttpDelivery.NewAdminHandler(mux, config.App.AdminAPIToken(), sdu, scu, smu)
+	_syscheckHttpDelivery.NewMaintenanceHandler(mux, config.App.AdminAPIToken(), _mtn)
+	_syscheckSlackDelivery.NewDiskCheckHandler(_cmd, sdu)
+	_syscheckSlackDelivery.NewCPUCheckHandler(_cmd, scu)
+	_syscheckSlackDelivery.NewMemoryCheckHandler(_cmd, smu)
 
 	// ---
 
 	// srvcheck domain repository
-	ser := _srvcheckRepo.NewESElasticsearchCheckHistoryRepository(_srvcheckConfig.App, esCli, json.MapWriter())
-	ssr := _srvcheckRepo.NewESSwarmpitCheckHistoryRepository(_srvcheckConfig.App, esCli, json.MapWriter())
-	scsr := _srvcheckRepo.NewESConsulCheckHistoryRepository(_srvcheckConfig.App, esCli, json.MapWriter())
+	// repository is selected with repository type between elasticsearch & file (JSON-lines file in local)
+	var ser domain.ElasticsearchCheckHistoryRepository
+	var ssr domain.SwarmpitCheckHistoryRepository
+	var scsr domain.ConsulCheckHistoryRepository
+	switch _srvcheckConfig.App.RepositoryType() {
+	case "file":
+		ser = _srvcheckFileRepo.NewFileElasticsearchCheckHistoryRepository(_srvcheckConfig.App, json.DottedMapEncoder())
+		ssr = _srvcheckFileRepo.NewFileSwarmpitCheckHistoryRepository(_srvcheckConfig.App, json.DottedMapEncoder())
+		scsr = _srvcheckFileRepo.NewFileConsulCheckHistoryRepository(_srvcheckConfig.App, json.DottedMapEncoder())
+	default:
+		ser = _srvcheckRepo.NewESElasticsearchCheckHistoryRepository(_srvcheckConfig.App, esCli, _blk, json.DottedMapEncoder())
+		ssr = _srvcheckRepo.NewESSwarmpitCheckHistoryRepository(_srvcheckConfig.App, esCli, _blk, json.DottedMapEncoder())
+		scsr = _srvcheckRepo.NewESConsulCheckHistoryRepository(_srvcheckConfig.App, esCli, _blk, json.DottedMapEncoder())
+
+		// history indices written through alias are rolled over & deleted after retention period in index manager
+		_srvcheckRepo.NewESIndexManager(_srvcheckConfig.App, esCli).Run(ctx, wg)
+	}
 
 	// srvcheck domain usecase
-	seu := _srvcheckUcase.NewElasticsearchCheckUsecase(_srvcheckConfig.App, ser, _slk, _es)
-	ssu := _srvcheckUcase.NewSwarmpitCheckUsecase(_srvcheckConfig.App, ssr, _slk, _dkr)
-	scsu := _srvcheckUcase.NewConsulCheckUsecase(_srvcheckConfig.App, scsr, _slk, _csl, _rpc, _dkr)
+	seu := _srvcheckUcase.NewElasticsearchCheckUsecase(_srvcheckConfig.App, ser, _ntf, _prm, _sts, _mtn, _esc, _es)
+	ssu := _srvcheckUcase.NewSwarmpitCheckUsecase(_srvcheckConfig.App, ssr, _ntf, _prm, _sts, _mtn, _esc, _dkr)
+	scsu := _srvcheckUcase.NewConsulCheckUsecase(_srvcheckConfig.App, scsr, _ntf, _prm, _sts, _mtn, _esc, _csl, _rpc, _dkr)
+	srdgu := _srvcheckUcase.NewDigestUsecase(ser, ssr, scsr, _slk)
 
 	// srvcheck domain delivery
-	_srvcheckChanDelivery.NewElasticsearchCheckHandler(time.Tick(_srvcheckConfig.App.ESCheckDeliveryPingCycle()), seu)
-	_srvcheckChanDelivery.NewSwarmpitCheckHandler(time.Tick(_srvcheckConfig.App.SwarmpitCheckDeliveryPingCycle()), ssu)
-	_srvcheckChanDelivery.NewConsulCheckHandler(time.Tick(_srvcheckConfig.App.ConsulCheckDeliveryPingCycle()), scsu)
+	_srvcheckChanDelivery.NewElasticsearchCheckHandler(ctx, tick(_srvcheckConfig.App.ESCheckDeliveryPingCycle()), wg, seu)
+	_srvcheckChanDelivery.NewSwarmpitCheckHandler(ctx, tick(_srvcheckConfig.App.SwarmpitCheckDeliveryPingCycle()), wg, ssu)
+	_srvcheckChanDelivery.NewConsulCheckHandler(ctx, tick(_srvcheckConfig.App.ConsulCheckDeliveryPingCycle()), wg, scsu)
+	_srvcheckChanDelivery.NewDigestHandler(ctx, schedule(daily), schedule(weekly), wg, srdgu)
+	_srvcheckHttpDelivery.NewElasticsearchCheckHandler(mux, config.App.AdminAPIToken(), seu)
+	_srvcheckHttpDelivery.NewSwarmpitCheckHandler(mux, config.App.AdminAPIToken(), ssu)
+	_srvcheckHttpDelivery.NewConsulCheckHandler(mux, config.App.AdminAPIToken(), scsu)
+	_srvcheckHttpDelivery.NewAdminHandler(mux, config.App.AdminAPIToken(), seu, ssu, scsu)
+	_srvcheckSlackDelivery.NewElasticsearchCheckHandler(_cmd, seu)
+	_srvcheckSlackDelivery.NewSwarmpitCheckHandler(_cmd, ssu)
+	_srvcheckSlackDelivery.NewConsulCheckHandler(_cmd, scsu)
 
-	runtime.Goexit()
+	// ---
+
+	// update history alarm result with repository of each check when alert is sent by retry
+	_rty.RegisterUpdater("syscheck", "DiskCheck", sdr)
+	_rty.RegisterUpdater("syscheck", "CPUCheck", scr)
+	_rty.RegisterUpdater("syscheck", "MemoryCheck", smr)
+	_rty.RegisterUpdater("srvcheck", "ElasticsearchCheck", ser)
+	_rty.RegisterUpdater("srvcheck", "SwarmpitCheck", ssr)
+	_rty.RegisterUpdater("srvcheck", "ConsulCheck", scsr)
+	_rty.Run(ctx, wg)
+	_blk.Run(ctx, wg)
+
+	srv := &http.Server{Addr: config.App.HTTPListenAddress(), Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(errors.Wrap(err, "failed to listen and serve http server"))
+		}
+	}()
+	log.Printf("START TO LISTEN HTTP REQUEST ON %s", config.App.HTTPListenAddress())
+
+	// block until SIGTERM or SIGINT is received, and then shut down gracefully
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTERM, syscall.SIGINT)
+	log.Printf("RECEIVED %s SIGNAL, START TO SHUT DOWN GRACEFULLY", <-sig)
+
+	for _, ticker := range tickers {
+		ticker.Stop()
+	}
+	// canceling root context stops listening of channel delivery, but check process in progress is not canceled
+	// check process runs with its own deadline (check timeout), so that remediation isn't interrupted in the middle
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), config.App.ShutdownTimeout())
+	defer shutdownCancel()
+
+	// check process run on demand by http delivery is waited in Shutdown method, bounded with shutdown timeout
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to shut down http server gracefully, err: %v", err)
+	}
+
+	// history is stored before check process returns, so waiting in-flight check process flushes pending history
+	// and then, history buffered in bulk agent is indexed or spooled in disk to be replayed after restart
+	done := waitUntilDone(shutdownCtx, wg)
+	_blk.Flush()
+	if !done {
+		log.Println("shutdown deadline exceeded before in-flight check process is finished")
+		return
+	}
+	log.Println("FINISH TO SHUT DOWN GRACEFULLY")
+}
+
+// statusStore is interface implemented in file & elasticsearch agent, used for selecting status store agent
+type statusStore interface {
+	LoadStatus(ctx context.Context, key string) (status string, transitedAt time.Time, err error)
+	StoreStatus(ctx context.Context, key, status string, transitedAt time.Time) (err error)
+}
+
+// webhookTargetName return name of webhook used as retry target, hashed not to keep url having secret in alert queue file
+func webhookTargetName(url string) string {
+	return fmt.Sprintf("webhook.%x", sha256.Sum256([]byte(url)))[:len("webhook.")+12]
+}
+
+// dailyAt return function returning next time of day after t, time of day is received as duration since midnight
+func dailyAt(at time.Duration) func(t time.Time) time.Time {
+	return func(t time.Time) time.Time {
+		next := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(at)
+		if !next.After(t) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	}
+}
+
+// weeklyAt return function returning next time of day in weekday after t
+func weeklyAt(weekday time.Weekday, at time.Duration) func(t time.Time) time.Time {
+	daily := dailyAt(at)
+	return func(t time.Time) time.Time {
+		next := daily(t)
+		for next.Weekday() != weekday {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	}
+}
+
+// waitUntilDone wait until counter of wait group is zero or ctx is done, and return if wait group is done
+func waitUntilDone(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
@@ -8,6 +8,7 @@
 package consul
 
 import (
+	"context"
 	"github.com/hashicorp/consul/api"
 )
 
@@ -23,3 +24,17 @@ func NewAgent(cc *api.Client) *consulAgent {
 		cslCli: cc,
 	}
 }
+
+// doWithContext call f in new goroutine & return its error, or return ctx error if ctx is done before f returns
+// consul api(v1.8.1) doesn't receive context in agent API, so f may keep running in background after ctx is done
+func doWithContext(ctx context.Context, f func() error) error {
+	errCh := make(chan error, 1)
+	go func() { errCh <- f() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
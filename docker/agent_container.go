@@ -14,14 +14,10 @@ import (
 )
 
 // GetContainerWithServiceName return container which is instance of received service name
-func (da *dockerAgent) GetContainerWithServiceName(srv string) (interface {
+func (da *dockerAgent) GetContainerWithServiceName(ctx context.Context, srv string) (interface {
 	ID() string                     // get id of container
 	MemoryUsage() bytesize.ByteSize // get memory usage of container
 }, error) {
-	var (
-		ctx = context.Background()
-	)
-	
 	containers, err := da.dkrCli.ContainerList(ctx, types.ContainerListOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get container list from docker")
@@ -59,11 +55,7 @@ func (da *dockerAgent) GetContainerWithServiceName(srv string) (interface {
 }
 
 // RemoveContainer remove container with id & option (auto created from docker swarm if exists)
-func (da *dockerAgent) RemoveContainer(containerID string, options types.ContainerRemoveOptions) error {
-	var (
-		ctx = context.Background()
-	)
-
+func (da *dockerAgent) RemoveContainer(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
 	return errors.Wrap(da.dkrCli.ContainerRemove(ctx, containerID, options), "failed to call ContainerRemove")
 }
 
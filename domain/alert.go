@@ -0,0 +1,57 @@
+// alert.go is file that define model about alert delivered to notifier agency when check process has something to alarm
+// alert model doesn't depend on any transport (slack, webhook, etc ...), so each of notifier decide how to present it
+
+package domain
+
+import (
+	"time"
+)
+
+// AlertLevel is string custom type used for representing how serious alert produced in check process is
+type AlertLevel string
+
+// alert level used in usecase layer & notifier agent, same with process level of history if exists
+const (
+	AlertLevelWarning       AlertLevel = "WARNING"        // represent that check status is warning now
+	AlertLevelWeakDetected  AlertLevel = "WEAK_DETECTED"  // represent that weak is detected & recovering starts
+	AlertLevelRecovered     AlertLevel = "RECOVERED"      // represent that check status is recovered to be healthy
+	AlertLevelUnhealthy     AlertLevel = "UNHEALTHY"      // represent that check status is unhealthy (not recovered)
+	AlertLevelError         AlertLevel = "ERROR"          // represent that error occurs while checking status
+	AlertLevelRecoveryError AlertLevel = "RECOVERY_ERROR" // represent that error occurs while recovering status
+	AlertLevelReset         AlertLevel = "RESET"          // represent that check status is reset by administrator
+	AlertLevelEscalated     AlertLevel = "ESCALATED"      // represent that check status stays unhealthy long & is escalated
+)
+
+// Alert model is used for delivering structured data about alert produced in check process to notifier agency
+type Alert struct {
+	// Domain specifies domain of check process producing this alert (Ex, syscheck, srvcheck)
+	Domain string
+
+	// Type specifies detail check type in domain (Ex, CPUCheck, ConsulCheck)
+	Type string
+
+	// Level specifies how serious this alert is
+	Level AlertLevel
+
+	// UUID specifies uuid of history produced in check process producing this alert
+	UUID string
+
+	// Reason specifies stable code of why this alert is produced (Ex, container_remove_failed), unlike Text
+	// it doesn't contain value changing in every check process (Ex, container id, error), so used for deduplicating alert
+	Reason string
+
+	// Text specifies human readable text about this alert
+	Text string
+
+	// Measurements specifies values measured in check process until this alert is produced
+	Measurements map[string]interface{}
+
+	// Thresholds specifies configured values which measurements are compared with in check process
+	Thresholds map[string]interface{}
+
+	// Mentions specifies slack user group or user ID to mention in this alert (Ex, escalated alert)
+	Mentions []string
+
+	// Timestamp specifies the time when this alert was created
+	Timestamp time.Time
+}
@@ -0,0 +1,103 @@
+// dotted_map.go is file that define functions reading typed value from dotted map of history
+// these functions are used in FillFromDottedMap method of each history to restore history stored in repository
+
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/inhies/go-bytesize"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// stringFromMap return string value having key in map (empty if not exist or not string)
+func stringFromMap(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// stringsFromMap return string slice split from value joined with " | " having key in map
+func stringsFromMap(m map[string]interface{}, key string) []string {
+	if s := stringFromMap(m, key); s != "" {
+		return strings.Split(s, " | ")
+	}
+	return []string{}
+}
+
+// boolFromMap return bool value having key in map (false if not exist or not bool)
+func boolFromMap(m map[string]interface{}, key string) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+// float64FromMap return float64 value having key in map (0 if not exist or not number)
+func float64FromMap(m map[string]interface{}, key string) float64 {
+	switch v := m[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+// timeFromMap return time value formatted in RFC3339 having key in map, in timezone set with SetLocation (zero if not set)
+func timeFromMap(m map[string]interface{}, key string) time.Time {
+	var t time.Time
+	switch v := m[key].(type) {
+	case time.Time:
+		t = v
+	case string:
+		t, _ = time.Parse(time.RFC3339Nano, v)
+	}
+
+	if t.IsZero() {
+		return time.Time{}
+	}
+	return t.In(location)
+}
+
+// bytesizeFromMap return bytesize value having key in map, stored as number of bytes or as string formatted with unit (Ex, 2.00GB)
+// string value is stored in legacy history document, and bytesize.Parse doesn't accept decimal point, so value & unit are parsed separately
+func bytesizeFromMap(m map[string]interface{}, key string) bytesize.ByteSize {
+	switch v := m[key].(type) {
+	case float64:
+		return bytesize.ByteSize(v)
+	case int:
+		return bytesize.ByteSize(v)
+	case int64:
+		return bytesize.ByteSize(v)
+	case uint64:
+		return bytesize.ByteSize(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return bytesize.ByteSize(f)
+	}
+
+	s := strings.TrimSpace(stringFromMap(m, key))
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if i <= 0 {
+		return 0
+	}
+
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0
+	}
+	unit, err := bytesize.Parse("1" + s[i:])
+	if err != nil {
+		return 0
+	}
+	return bytesize.ByteSize(value * float64(unit))
+}
+
+// errorFromMap return error having message of string value having key in map (nil if empty)
+func errorFromMap(m map[string]interface{}, key string) error {
+	if s := stringFromMap(m, key); s != "" {
+		return errors.New(s)
+	}
+	return nil
+}
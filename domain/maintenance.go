@@ -0,0 +1,36 @@
+// maintenance.go is file that define model about maintenance window suppressing remediation & alarm of check process
+// maintenance window model is shared by maintenance agent managing windows & http delivery serving admin API about them
+
+package domain
+
+import (
+	"time"
+)
+
+// MaintenanceConfigCreator is creator of maintenance window defined in config file
+// it is reserved for config file, so maintenance window created in admin API can't have this creator
+const MaintenanceConfigCreator = "config"
+
+// MaintenanceWindow model is used for covering checks (Ex, srvcheck.consul, syscheck) & containers during period
+type MaintenanceWindow struct {
+	// ID specifies unique identifier of maintenance window
+	ID string `json:"id"`
+
+	// Checks specifies keys of check covered by window, domain (Ex, srvcheck) or "*" covers every check in it
+	Checks []string `json:"checks"`
+
+	// Containers specifies names of container (docker service name) covered by window
+	Containers []string `json:"containers"`
+
+	// StartAt specifies the time when maintenance window starts
+	StartAt time.Time `json:"start_at"`
+
+	// EndAt specifies the time when maintenance window ends
+	EndAt time.Time `json:"end_at"`
+
+	// Reason specifies why maintenance window is needed (Ex, planned work on the swarm)
+	Reason string `json:"reason"`
+
+	// CreatedBy specifies who created maintenance window (config if defined in config file)
+	CreatedBy string `json:"created_by"`
+}
@@ -62,12 +62,36 @@ type serviceCheckHistoryComponent struct {
 
 	// alarmErr specifies Error occurred when sending alarm.
 	alarmErr error
+
+	// ---
+
+	// field in below is about status reset by administrator, so call SetResetResult method to set this field value
+	// resetBy specifies who reset status of service check process (empty if not reset)
+	resetBy string
+
+	// resetReason specifies why administrator reset status of service check process
+	resetReason string
+	// ---
+
+	// field in below is about maintenance window, so call SetMaintenanceWindow method to set this field value
+	// maintenanceWindow specifies id of maintenance window suppressed remediation or alarm (empty if not suppressed)
+	maintenanceWindow string
 }
 
 // serviceCheckHistoryRepositoryComponent is basic interface using by embedded in every repository about service check history
 type serviceCheckHistoryRepositoryComponent interface {
 	// Migrate method build environment for storage in stores such as Mysql or Elasticsearch, etc.
 	Migrate() error
+
+	// UpdateAlarmResult method update alarm result of history having uuid, used when alarm is sent later by retry
+	// alarm error is replaced with err (cleared if nil), and alarm time & text are kept if text is empty
+	UpdateAlarmResult(uuid string, t time.Time, text string, err error) error
+}
+
+// serviceCheckUsecaseComponent is basic interface using by embedded in every usecase about service check
+type serviceCheckUsecaseComponent interface {
+	// GetStatus method return current status of check process state machine as string (Ex, HEALTHY, RECOVERING)
+	GetStatus() (status string)
 }
 
 // FillComponent fill field of serviceCheckHistoryComponent if is empty
@@ -76,7 +100,7 @@ func (sch *serviceCheckHistoryComponent) FillPrivateComponent() {
 	sch.agent = "sms-health-check"
 	sch.domain = "srvcheck"
 	sch._type = "None"
-	sch.timestamp = time.Now()
+	sch.timestamp = Now()
 }
 
 // DottedMapWithPrefix convert serviceCheckHistoryComponent to dotted map and return that
@@ -109,19 +133,94 @@ func (sch *serviceCheckHistoryComponent) DottedMapWithPrefix(prefix string) (m m
 	m[prefix + "alerted"] = sch.alerted
 	m[prefix + "alarm_text"] = sch.alarmText
 	m[prefix + "alarm_time"] = sch.alarmTime
-	m[prefix + "alarm_error"] = sch.alarmErr
+	if sch.alarmErr == nil {
+		m[prefix + "alarm_error"] = nil
+	} else {
+		m[prefix + "alarm_error"] = sch.alarmErr.Error()
+	}
+
+	// setting reset result field value in dotted map
+	m[prefix + "reset_by"] = sch.resetBy
+	m[prefix + "reset_reason"] = sch.resetReason
+
+	// setting maintenance window field value in dotted map
+	m[prefix + "maintenance_window"] = sch.maintenanceWindow
 
 	return
 }
 
+// FillFromDottedMap fill field of serviceCheckHistoryComponent from dotted map made in DottedMapWithPrefix method
+// it is used for restoring history stored in repository, so value not matched with type is ignored
+func (sch *serviceCheckHistoryComponent) FillFromDottedMap(m map[string]interface{}) {
+	// setting private field value from dotted map
+	sch.version = stringFromMap(m, "version")
+	sch.agent = stringFromMap(m, "agent")
+	sch.timestamp = timeFromMap(m, "@timestamp")
+	sch.domain = stringFromMap(m, "domain")
+	sch._type = stringFromMap(m, "type")
+
+	// setting public field value from dotted map
+	sch.UUID = stringFromMap(m, "uuid")
+	sch.ProcessLevel = srvcheckProcessLevel(stringsFromMap(m, "process_level"))
+	sch.Message = stringFromMap(m, "message")
+	sch.Error = errorFromMap(m, "error")
+
+	// setting alarm result field value from dotted map
+	sch.alerted = boolFromMap(m, "alerted")
+	sch.alarmText = stringFromMap(m, "alarm_text")
+	sch.alarmTime = timeFromMap(m, "alarm_time")
+	sch.alarmErr = errorFromMap(m, "alarm_error")
+
+	// setting reset result & maintenance window field value from dotted map
+	sch.resetBy = stringFromMap(m, "reset_by")
+	sch.resetReason = stringFromMap(m, "reset_reason")
+	sch.maintenanceWindow = stringFromMap(m, "maintenance_window")
+}
+
+// Timestamp return the time when this service check history was created
+func (sch *serviceCheckHistoryComponent) Timestamp() time.Time {
+	return sch.timestamp
+}
+
+// NewAlert return alert having level & text about service check history, measurements is set in overriding method & thresholds in usecase
+func (sch *serviceCheckHistoryComponent) NewAlert(level AlertLevel, text string) *Alert {
+	return &Alert{
+		Domain:       sch.domain,
+		Type:         sch._type,
+		Level:        level,
+		UUID:         sch.UUID,
+		Text:         text,
+		Measurements: map[string]interface{}{},
+		Thresholds:   map[string]interface{}{},
+		Timestamp:    Now(),
+	}
+}
+
 // SetAlarmResult set field value about alarm result with parameter
+// empty text with nil error means that alarm is suppressed in notifier (Ex, duplicated alert), so not regarded as alerted
 func (sch *serviceCheckHistoryComponent) SetAlarmResult(t time.Time, text string, err error) {
-	sch.alerted = true
+	sch.alerted = text != "" || err != nil
 	sch.alarmTime = t
 	sch.alarmText = text
 	sch.alarmErr = err
 }
 
+// SetResetResult set field value about who reset status of check process & why with parameter
+func (sch *serviceCheckHistoryComponent) SetResetResult(by, reason string) {
+	sch.resetBy = by
+	sch.resetReason = reason
+}
+
+// SetMaintenanceWindow set id of maintenance window which suppressed remediation or alarm in service check process
+func (sch *serviceCheckHistoryComponent) SetMaintenanceWindow(id string) {
+	sch.maintenanceWindow = id
+}
+
+// MaintenanceWindow return id of maintenance window which suppressed remediation or alarm (empty if not suppressed)
+func (sch *serviceCheckHistoryComponent) MaintenanceWindow() string {
+	return sch.maintenanceWindow
+}
+
 // SetError method set Message & Error field with err get from param
 func (sch *serviceCheckHistoryComponent) SetError(err error) {
 	sch.Message = err.Error()
@@ -146,6 +245,14 @@ func (pl *srvcheckProcessLevel) Append(level string) {
 	*pl = append(*pl, level)
 }
 
+// Last method return last level of srvcheckProcessLevel slice (empty if not set)
+func (pl *srvcheckProcessLevel) Last() string {
+	if len(*pl) == 0 {
+		return ""
+	}
+	return (*pl)[len(*pl)-1]
+}
+
 // String method return string which join srvcheckProcessLevel slice to string with " | "
 func (pl *srvcheckProcessLevel) String() string {
 	return strings.Join(*pl, " | ")
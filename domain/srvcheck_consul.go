@@ -7,6 +7,7 @@ package domain
 import (
 	"context"
 	"strings"
+	"time"
 )
 
 // ConsulCheckHistory model is used for record consul check history and result
@@ -42,12 +43,42 @@ type ConsulCheckHistoryRepository interface {
 	// Store method save ConsulCheckHistory model in repository
 	// b in return represents bytes of response body(map[string]interface{})
 	Store(*ConsulCheckHistory) (b []byte, err error)
+
+	// FindByTimeRange method return ConsulCheckHistory stored between start (inclusive) & end (exclusive) in order of time
+	FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*ConsulCheckHistory, err error)
+
+	// GetByUUID method return ConsulCheckHistory having uuid (nil if not exist)
+	GetByUUID(ctx context.Context, uuid string) (history *ConsulCheckHistory, err error)
+
+	// FindByProcessLevel method return ConsulCheckHistory having process level & stored between start (inclusive) & end (exclusive) in order of time
+	FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*ConsulCheckHistory, err error)
+
+	// FindLatest method return latest n ConsulCheckHistory in order of newest first
+	FindLatest(ctx context.Context, n int) (histories []*ConsulCheckHistory, err error)
 }
 
 // ConsulCheckUseCase is interface used as business process handler about consul check
 type ConsulCheckUseCase interface {
-	// CheckConsul method check consul status and store check history using repository
-	CheckConsul(ctx context.Context) error
+	// get required component by embedding serviceCheckUsecaseComponent
+	serviceCheckUsecaseComponent
+
+	// CheckConsul method check consul status and store check history using repository, then return that history
+	CheckConsul(ctx context.Context) (history *ConsulCheckHistory, err error)
+
+	// GetLastHistory method return consul check history produced in last check process (nil if not checked yet)
+	GetLastHistory() *ConsulCheckHistory
+
+	// ResetStatus method reset status of consul check process to healthy by administrator & store history about that reset
+	ResetStatus(ctx context.Context, by, reason string) (history *ConsulCheckHistory, err error)
+
+	// GetHistory method return consul check history having uuid in repository (nil if not exist)
+	GetHistory(ctx context.Context, uuid string) (history *ConsulCheckHistory, err error)
+
+	// FindHistories method return consul check histories stored between start & end in order of time, filtered by process level if not empty
+	FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*ConsulCheckHistory, err error)
+
+	// FindLatestHistories method return latest n consul check histories in repository in order of newest first
+	FindLatestHistories(ctx context.Context, n int) (histories []*ConsulCheckHistory, err error)
 }
 
 // FillPrivateComponent overriding FillPrivateComponent method of serviceCheckHistoryComponent
@@ -75,3 +106,40 @@ func (ch *ConsulCheckHistory) DottedMapWithPrefix(prefix string) (m map[string]i
 
 	return
 }
+
+// FillFromDottedMap overriding FillFromDottedMap method of serviceCheckHistoryComponent, filling ConsulCheckHistory field from dotted map
+func (ch *ConsulCheckHistory) FillFromDottedMap(m map[string]interface{}) {
+	ch.serviceCheckHistoryComponent.FillFromDottedMap(m)
+
+	// setting public field value from dotted map
+	ch.InstancesPerService = map[string][]string{}
+	instances, _ := m["instances_per_service"].(map[string]interface{})
+	for srv, ids := range instances {
+		list, _ := ids.([]interface{})
+		for _, id := range list {
+			if s, ok := id.(string); ok {
+				ch.InstancesPerService[srv] = append(ch.InstancesPerService[srv], s)
+			}
+		}
+	}
+	ch.IfInstanceDeregistered = boolFromMap(m, "if_instance_deregistered")
+	ch.DeregisteredInstances = stringsFromMap(m, "deregistered_instances")
+	ch.DeregisterFailedInstances = stringsFromMap(m, "deregister_failed_instances")
+	ch.IfContainerRestarted = boolFromMap(m, "if_container_restarted")
+	ch.RestartedContainers = stringsFromMap(m, "restarted_containers")
+}
+
+// NewAlert overriding NewAlert method of serviceCheckHistoryComponent, setting measurements with consul check history field
+func (ch *ConsulCheckHistory) NewAlert(level AlertLevel, text string) (alert *Alert) {
+	alert = ch.serviceCheckHistoryComponent.NewAlert(level, text)
+
+	// setting measured field value in measurements
+	alert.Measurements["instances_per_service"] = ch.InstancesPerService
+	alert.Measurements["if_instance_deregistered"] = ch.IfInstanceDeregistered
+	alert.Measurements["deregistered_instances"] = ch.DeregisteredInstances
+	alert.Measurements["deregister_failed_instances"] = ch.DeregisterFailedInstances
+	alert.Measurements["if_container_restarted"] = ch.IfContainerRestarted
+	alert.Measurements["restarted_containers"] = ch.RestartedContainers
+
+	return
+}
@@ -0,0 +1,39 @@
+// srvcheck_digest.go is file that declare model struct & usecase interface about digest of srvcheck domain.
+// digest summarize service check histories stored in period (Ex, daily, weekly)
+
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ServiceCheckDigest model is used for summarizing service check histories stored in period
+type ServiceCheckDigest struct {
+	// Period specifies name of period summarized in this digest (Ex, daily, weekly)
+	Period string
+
+	// Start specifies start time of period (inclusive)
+	Start time.Time
+
+	// End specifies end time of period (exclusive)
+	End time.Time
+
+	// LevelCounts specifies count of each process level per check type (Ex, consul -> HEALTHY -> 1440)
+	LevelCounts map[string]map[string]int
+
+	// DeletedJaegerIndices specifies number of jaeger index deleted in elasticsearch check to recover
+	DeletedJaegerIndices int
+
+	// DeregisteredInstances specifies number of instance deregistered in consul check to recover
+	DeregisteredInstances int
+
+	// RestartedContainers specifies number of container restarted in consul & swarmpit check to recover
+	RestartedContainers int
+}
+
+// ServiceCheckDigestUseCase is interface used as business process handler about digest of service check
+type ServiceCheckDigestUseCase interface {
+	// Digest method summarize service check histories stored between start & end, post it as report & return that digest
+	Digest(ctx context.Context, period string, start, end time.Time) (digest *ServiceCheckDigest, err error)
+}
@@ -7,6 +7,7 @@ package domain
 import (
 	"context"
 	"strings"
+	"time"
 )
 
 // ElasticsearchCheckHistory model is used for record elasticsearch check history and result
@@ -42,12 +43,42 @@ type ElasticsearchCheckHistoryRepository interface {
 	// Store method save ElasticsearchCheckHistory model in repository
 	// b in return represents bytes of response body(map[string]interface{})
 	Store(*ElasticsearchCheckHistory) (b []byte, err error)
+
+	// FindByTimeRange method return ElasticsearchCheckHistory stored between start (inclusive) & end (exclusive) in order of time
+	FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*ElasticsearchCheckHistory, err error)
+
+	// GetByUUID method return ElasticsearchCheckHistory having uuid (nil if not exist)
+	GetByUUID(ctx context.Context, uuid string) (history *ElasticsearchCheckHistory, err error)
+
+	// FindByProcessLevel method return ElasticsearchCheckHistory having process level & stored between start (inclusive) & end (exclusive) in order of time
+	FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*ElasticsearchCheckHistory, err error)
+
+	// FindLatest method return latest n ElasticsearchCheckHistory in order of newest first
+	FindLatest(ctx context.Context, n int) (histories []*ElasticsearchCheckHistory, err error)
 }
 
 // ElasticsearchCheckUseCase is interface used as business process handler about elasticsearch check
 type ElasticsearchCheckUseCase interface {
-	// CheckElasticsearch method check elasticsearch status and store check history using repository
-	CheckElasticsearch(ctx context.Context) error
+	// get required component by embedding serviceCheckUsecaseComponent
+	serviceCheckUsecaseComponent
+
+	// CheckElasticsearch method check elasticsearch status and store check history using repository, then return that history
+	CheckElasticsearch(ctx context.Context) (history *ElasticsearchCheckHistory, err error)
+
+	// GetLastHistory method return elasticsearch check history produced in last check process (nil if not checked yet)
+	GetLastHistory() *ElasticsearchCheckHistory
+
+	// ResetStatus method reset status of elasticsearch check process to healthy by administrator & store history about that reset
+	ResetStatus(ctx context.Context, by, reason string) (history *ElasticsearchCheckHistory, err error)
+
+	// GetHistory method return elasticsearch check history having uuid in repository (nil if not exist)
+	GetHistory(ctx context.Context, uuid string) (history *ElasticsearchCheckHistory, err error)
+
+	// FindHistories method return elasticsearch check histories stored between start & end in order of time, filtered by process level if not empty
+	FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*ElasticsearchCheckHistory, err error)
+
+	// FindLatestHistories method return latest n elasticsearch check histories in repository in order of newest first
+	FindLatestHistories(ctx context.Context, n int) (histories []*ElasticsearchCheckHistory, err error)
 }
 
 // FillPrivateComponent overriding FillPrivateComponent method of serviceCheckHistoryComponent
@@ -76,6 +107,19 @@ func (eh *ElasticsearchCheckHistory) DottedMapWithPrefix(prefix string) (m map[s
 	return
 }
 
+// FillFromDottedMap overriding FillFromDottedMap method of serviceCheckHistoryComponent, filling ElasticsearchCheckHistory field from dotted map
+func (eh *ElasticsearchCheckHistory) FillFromDottedMap(m map[string]interface{}) {
+	eh.serviceCheckHistoryComponent.FillFromDottedMap(m)
+
+	// setting public field value from dotted map
+	eh.ActivePrimaryShards = int(float64FromMap(m, "active_primary_shards"))
+	eh.ActiveShards = int(float64FromMap(m, "active_shards"))
+	eh.UnassignedShards = int(float64FromMap(m, "unassigned_shards"))
+	eh.ActiveShardsPercent = float64FromMap(m, "active_shards_percent")
+	eh.IfJaegerIndexDeleted = boolFromMap(m, "if_jaeger_index_deleted")
+	eh.DeletedJaegerIndices = stringsFromMap(m, "deleted_jaeger_indices")
+}
+
 // SetClusterHealth method set field about cluster health with received cluster
 func (eh *ElasticsearchCheckHistory) SetClusterHealth(cluster interface {
 	ActivePrimaryShards() int     // get active primary shards number in cluster health result
@@ -88,3 +132,18 @@ func (eh *ElasticsearchCheckHistory) SetClusterHealth(cluster interface {
 	eh.UnassignedShards = cluster.UnassignedShards()
 	eh.ActiveShardsPercent = cluster.ActiveShardsPercent()
 }
+
+// NewAlert overriding NewAlert method of serviceCheckHistoryComponent, setting measurements with elasticsearch check history field
+func (eh *ElasticsearchCheckHistory) NewAlert(level AlertLevel, text string) (alert *Alert) {
+	alert = eh.serviceCheckHistoryComponent.NewAlert(level, text)
+
+	// setting measured field value in measurements
+	alert.Measurements["active_primary_shards"] = eh.ActivePrimaryShards
+	alert.Measurements["active_shards"] = eh.ActiveShards
+	alert.Measurements["unassigned_shards"] = eh.UnassignedShards
+	alert.Measurements["active_shards_percent"] = eh.ActiveShardsPercent
+	alert.Measurements["if_jaeger_index_deleted"] = eh.IfJaegerIndexDeleted
+	alert.Measurements["deleted_jaeger_indices"] = eh.DeletedJaegerIndices
+
+	return
+}
@@ -7,6 +7,7 @@ package domain
 import (
 	"context"
 	"github.com/inhies/go-bytesize"
+	"time"
 )
 
 // SwarmpitCheckHistory model is used for record swarmpit check history and result
@@ -30,12 +31,42 @@ type SwarmpitCheckHistoryRepository interface {
 	// Store method save SwarmpitCheckHistory model in repository
 	// b in return represents bytes of response body(map[string]interface{})
 	Store(*SwarmpitCheckHistory) (b []byte, err error)
+
+	// FindByTimeRange method return SwarmpitCheckHistory stored between start (inclusive) & end (exclusive) in order of time
+	FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*SwarmpitCheckHistory, err error)
+
+	// GetByUUID method return SwarmpitCheckHistory having uuid (nil if not exist)
+	GetByUUID(ctx context.Context, uuid string) (history *SwarmpitCheckHistory, err error)
+
+	// FindByProcessLevel method return SwarmpitCheckHistory having process level & stored between start (inclusive) & end (exclusive) in order of time
+	FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*SwarmpitCheckHistory, err error)
+
+	// FindLatest method return latest n SwarmpitCheckHistory in order of newest first
+	FindLatest(ctx context.Context, n int) (histories []*SwarmpitCheckHistory, err error)
 }
 
 // SwarmpitCheckUseCase is interface used as business process handler about swarmpit check
 type SwarmpitCheckUseCase interface {
-	// CheckSwarmpit method check swarmpit status and store check history using repository
-	CheckSwarmpit(ctx context.Context) error
+	// get required component by embedding serviceCheckUsecaseComponent
+	serviceCheckUsecaseComponent
+
+	// CheckSwarmpit method check swarmpit status and store check history using repository, then return that history
+	CheckSwarmpit(ctx context.Context) (history *SwarmpitCheckHistory, err error)
+
+	// GetLastHistory method return swarmpit check history produced in last check process (nil if not checked yet)
+	GetLastHistory() *SwarmpitCheckHistory
+
+	// ResetStatus method reset status of swarmpit check process to healthy by administrator & store history about that reset
+	ResetStatus(ctx context.Context, by, reason string) (history *SwarmpitCheckHistory, err error)
+
+	// GetHistory method return swarmpit check history having uuid in repository (nil if not exist)
+	GetHistory(ctx context.Context, uuid string) (history *SwarmpitCheckHistory, err error)
+
+	// FindHistories method return swarmpit check histories stored between start & end in order of time, filtered by process level if not empty
+	FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*SwarmpitCheckHistory, err error)
+
+	// FindLatestHistories method return latest n swarmpit check histories in repository in order of newest first
+	FindLatestHistories(ctx context.Context, n int) (histories []*SwarmpitCheckHistory, err error)
 }
 
 // FillPrivateComponent overriding FillPrivateComponent method of serviceCheckHistoryComponent
@@ -53,9 +84,30 @@ func (sh *SwarmpitCheckHistory) DottedMapWithPrefix(prefix string) (m map[string
 		prefix += "."
 	}
 
-	// setting public field value in dotted map
-	m[prefix + "swarmpit_app_memory_usage"] = sh.SwarmpitAppMemoryUsage.String()
+	// setting public field value in dotted map, byte size is set as number of bytes with human readable companion
+	m[prefix + "swarmpit_app_memory_usage"] = uint64(sh.SwarmpitAppMemoryUsage)
+	m[prefix + "swarmpit_app_memory_usage_human"] = sh.SwarmpitAppMemoryUsage.String()
 	m[prefix + "if_swarmpit_app_restarted"] = sh.IfSwarmpitAppRestarted
 
 	return
 }
+
+// FillFromDottedMap overriding FillFromDottedMap method of serviceCheckHistoryComponent, filling SwarmpitCheckHistory field from dotted map
+func (sh *SwarmpitCheckHistory) FillFromDottedMap(m map[string]interface{}) {
+	sh.serviceCheckHistoryComponent.FillFromDottedMap(m)
+
+	// setting public field value from dotted map
+	sh.SwarmpitAppMemoryUsage = bytesizeFromMap(m, "swarmpit_app_memory_usage")
+	sh.IfSwarmpitAppRestarted = boolFromMap(m, "if_swarmpit_app_restarted")
+}
+
+// NewAlert overriding NewAlert method of serviceCheckHistoryComponent, setting measurements with swarmpit check history field
+func (sh *SwarmpitCheckHistory) NewAlert(level AlertLevel, text string) (alert *Alert) {
+	alert = sh.serviceCheckHistoryComponent.NewAlert(level, text)
+
+	// setting measured field value in measurements
+	alert.Measurements["swarmpit_app_memory_usage"] = sh.SwarmpitAppMemoryUsage
+	alert.Measurements["if_swarmpit_app_restarted"] = sh.IfSwarmpitAppRestarted
+
+	return
+}
@@ -10,8 +10,6 @@
 package domain
 
 import (
-	"log"
-	"os"
 	"strings"
 	"time"
 )
@@ -63,12 +61,36 @@ type systemCheckHistoryComponent struct {
 
 	// alarmErr specifies Error occurred when sending alarm.
 	alarmErr error
+
+	// ---
+
+	// field in below is about status reset by administrator, so call SetResetResult method to set this field value
+	// resetBy specifies who reset status of system check process (empty if not reset)
+	resetBy string
+
+	// resetReason specifies why administrator reset status of system check process
+	resetReason string
+	// ---
+
+	// field in below is about maintenance window, so call SetMaintenanceWindow method to set this field value
+	// maintenanceWindow specifies id of maintenance window suppressed remediation or alarm (empty if not suppressed)
+	maintenanceWindow string
 }
 
 // systemCheckHistoryRepositoryComponent is basic interface using by embedded in every repository about check history
 type systemCheckHistoryRepositoryComponent interface {
 	// Migrate method build environment for storage in stores such as Mysql or Elasticsearch, etc.
 	Migrate() error
+
+	// UpdateAlarmResult method update alarm result of history having uuid, used when alarm is sent later by retry
+	// alarm error is replaced with err (cleared if nil), and alarm time & text are kept if text is empty
+	UpdateAlarmResult(uuid string, t time.Time, text string, err error) error
+}
+
+// systemCheckUsecaseComponent is basic interface using by embedded in every usecase about system check
+type systemCheckUsecaseComponent interface {
+	// GetStatus method return current status of check process state machine as string (Ex, HEALTHY, RECOVERING)
+	GetStatus() (status string)
 }
 
 // FillComponent fill field of systemCheckHistoryComponent if is empty
@@ -77,7 +99,7 @@ func (sch *systemCheckHistoryComponent) FillPrivateComponent() {
 	sch.agent = "sms-health-check"
 	sch.domain = "syscheck"
 	sch._type = "None"
-	sch.timestamp = time.Now()
+	sch.timestamp = Now()
 }
 
 // DottedMapWithPrefix convert systemCheckHistoryComponent to dotted map and return that
@@ -110,19 +132,94 @@ func (sch *systemCheckHistoryComponent) DottedMapWithPrefix(prefix string) (m ma
 	m[prefix + "alerted"] = sch.alerted
 	m[prefix + "alarm_text"] = sch.alarmText
 	m[prefix + "alarm_time"] = sch.alarmTime
-	m[prefix + "alarm_error"] = sch.alarmErr
+	if sch.alarmErr == nil {
+		m[prefix + "alarm_error"] = nil
+	} else {
+		m[prefix + "alarm_error"] = sch.alarmErr.Error()
+	}
+
+	// setting reset result field value in dotted map
+	m[prefix + "reset_by"] = sch.resetBy
+	m[prefix + "reset_reason"] = sch.resetReason
+
+	// setting maintenance window field value in dotted map
+	m[prefix + "maintenance_window"] = sch.maintenanceWindow
 
 	return
 }
 
+// FillFromDottedMap fill field of systemCheckHistoryComponent from dotted map made in DottedMapWithPrefix method
+// it is used for restoring history stored in repository, so value not matched with type is ignored
+func (sch *systemCheckHistoryComponent) FillFromDottedMap(m map[string]interface{}) {
+	// setting private field value from dotted map
+	sch.version = stringFromMap(m, "version")
+	sch.agent = stringFromMap(m, "agent")
+	sch.timestamp = timeFromMap(m, "@timestamp")
+	sch.domain = stringFromMap(m, "domain")
+	sch._type = stringFromMap(m, "type")
+
+	// setting public field value from dotted map
+	sch.UUID = stringFromMap(m, "uuid")
+	sch.ProcessLevel = syscheckProcessLevel(stringsFromMap(m, "process_level"))
+	sch.Message = stringFromMap(m, "message")
+	sch.Error = errorFromMap(m, "error")
+
+	// setting alarm result field value from dotted map
+	sch.alerted = boolFromMap(m, "alerted")
+	sch.alarmText = stringFromMap(m, "alarm_text")
+	sch.alarmTime = timeFromMap(m, "alarm_time")
+	sch.alarmErr = errorFromMap(m, "alarm_error")
+
+	// setting reset result & maintenance window field value from dotted map
+	sch.resetBy = stringFromMap(m, "reset_by")
+	sch.resetReason = stringFromMap(m, "reset_reason")
+	sch.maintenanceWindow = stringFromMap(m, "maintenance_window")
+}
+
+// Timestamp return the time when this system check history was created
+func (sch *systemCheckHistoryComponent) Timestamp() time.Time {
+	return sch.timestamp
+}
+
+// NewAlert return alert having level & text about system check history, measurements is set in overriding method & thresholds in usecase
+func (sch *systemCheckHistoryComponent) NewAlert(level AlertLevel, text string) *Alert {
+	return &Alert{
+		Domain:       sch.domain,
+		Type:         sch._type,
+		Level:        level,
+		UUID:         sch.UUID,
+		Text:         text,
+		Measurements: map[string]interface{}{},
+		Thresholds:   map[string]interface{}{},
+		Timestamp:    Now(),
+	}
+}
+
 // SetAlarmResult set field value about alarm result with parameter
+// empty text with nil error means that alarm is suppressed in notifier (Ex, duplicated alert), so not regarded as alerted
 func (sch *systemCheckHistoryComponent) SetAlarmResult(t time.Time, text string, err error) {
-	sch.alerted = true
+	sch.alerted = text != "" || err != nil
 	sch.alarmTime = t
 	sch.alarmText = text
 	sch.alarmErr = err
 }
 
+// SetResetResult set field value about who reset status of check process & why with parameter
+func (sch *systemCheckHistoryComponent) SetResetResult(by, reason string) {
+	sch.resetBy = by
+	sch.resetReason = reason
+}
+
+// SetMaintenanceWindow set id of maintenance window which suppressed remediation or alarm in system check process
+func (sch *systemCheckHistoryComponent) SetMaintenanceWindow(id string) {
+	sch.maintenanceWindow = id
+}
+
+// MaintenanceWindow return id of maintenance window which suppressed remediation or alarm (empty if not suppressed)
+func (sch *systemCheckHistoryComponent) MaintenanceWindow() string {
+	return sch.maintenanceWindow
+}
+
 // SetError method set Message & Error field with err get from param
 func (sch *systemCheckHistoryComponent) SetError(err error) {
 	sch.Message = err.Error()
@@ -142,15 +239,41 @@ func (pl *syscheckProcessLevel) Append(level string) {
 	*pl = append(*pl, level)
 }
 
+// Last method return last level of processLevel slice (empty if not set)
+func (pl *syscheckProcessLevel) Last() string {
+	if len(*pl) == 0 {
+		return ""
+	}
+	return (*pl)[len(*pl)-1]
+}
+
 // String method return string which join processLevel slice to string with " | "
 func (pl *syscheckProcessLevel) String() string {
 	return strings.Join(*pl, " | ")
 }
 
-// get information from system environment variable
+// version is health checker version recorded in every history, set in app package at startup
 var version string
-func init() {
-	if version = os.Getenv("VERSION"); version == "" {
-		log.Fatal("please set VERSION in environment variable")
-	}
+
+// SetVersion set health checker version recorded in every history, must be called before check process is started
+func SetVersion(v string) {
+	version = v
+}
+
+// location is timezone used for storing & displaying time, set in app package at startup (local timezone until set)
+var location = time.Local
+
+// SetLocation set timezone used for storing & displaying time, must be called before check process is started
+func SetLocation(loc *time.Location) {
+	location = loc
+}
+
+// Location return timezone set with SetLocation, used for storing & displaying time
+func Location() *time.Location {
+	return location
+}
+
+// Now return current time in timezone set with SetLocation
+func Now() time.Time {
+	return time.Now().In(location)
 }
@@ -6,6 +6,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // CPUCheckHistory model is used for record cpu health check history and result
@@ -24,6 +25,9 @@ type CPUCheckHistory struct {
 
 	// MostCPUConsumeContainer specifies the container name which is consumed most CPU
 	MostCPUConsumeContainer string
+
+	// RemovedContainer specifies the container name which is removed to recover cpu health (empty if not removed)
+	RemovedContainer string
 }
 
 // CPUCheckHistoryRepository is interface for repository layer used in usecase layer
@@ -35,12 +39,42 @@ type CPUCheckHistoryRepository interface {
 	// Store method save CPUCheckHistory model in repository
 	// b in return represents bytes of response body(map[string]interface{})
 	Store(*CPUCheckHistory) (b []byte, err error)
+
+	// FindByTimeRange method return CPUCheckHistory stored between start (inclusive) & end (exclusive) in order of time
+	FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*CPUCheckHistory, err error)
+
+	// GetByUUID method return CPUCheckHistory having uuid (nil if not exist)
+	GetByUUID(ctx context.Context, uuid string) (history *CPUCheckHistory, err error)
+
+	// FindByProcessLevel method return CPUCheckHistory having process level & stored between start (inclusive) & end (exclusive) in order of time
+	FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*CPUCheckHistory, err error)
+
+	// FindLatest method return latest n CPUCheckHistory in order of newest first
+	FindLatest(ctx context.Context, n int) (histories []*CPUCheckHistory, err error)
 }
 
 // DiskCheckUseCase is interface used as business process handler about cpu check
 type CPUCheckUseCase interface {
-	// CheckCPU method check cpu usage status and store cpu check history using repository
-	CheckCPU(ctx context.Context) error
+	// get required component by embedding systemCheckUsecaseComponent
+	systemCheckUsecaseComponent
+
+	// CheckCPU method check cpu usage status and store cpu check history using repository, then return that history
+	CheckCPU(ctx context.Context) (history *CPUCheckHistory, err error)
+
+	// GetLastHistory method return cpu check history produced in last check process (nil if not checked yet)
+	GetLastHistory() *CPUCheckHistory
+
+	// ResetStatus method reset status of cpu check process to healthy by administrator & store history about that reset
+	ResetStatus(ctx context.Context, by, reason string) (history *CPUCheckHistory, err error)
+
+	// GetHistory method return cpu check history having uuid in repository (nil if not exist)
+	GetHistory(ctx context.Context, uuid string) (history *CPUCheckHistory, err error)
+
+	// FindHistories method return cpu check histories stored between start & end in order of time, filtered by process level if not empty
+	FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*CPUCheckHistory, err error)
+
+	// FindLatestHistories method return latest n cpu check histories in repository in order of newest first
+	FindLatestHistories(ctx context.Context, n int) (histories []*CPUCheckHistory, err error)
 }
 
 // FillPrivateComponent overriding FillPrivateComponent method of systemCheckHistoryComponent
@@ -63,6 +97,33 @@ func (ch *CPUCheckHistory) DottedMapWithPrefix(prefix string) (m map[string]inte
 	m[prefix + "docker_usage_core"] = ch.DockerUsageCore
 	m[prefix + "temporary_free_core"] = ch.TemporaryFreeCore
 	m[prefix + "most_cpu_consume_container"] = ch.MostCPUConsumeContainer
+	m[prefix + "removed_container"] = ch.RemovedContainer
+
+	return
+}
+
+// FillFromDottedMap overriding FillFromDottedMap method of systemCheckHistoryComponent, filling CPUCheckHistory field from dotted map
+func (ch *CPUCheckHistory) FillFromDottedMap(m map[string]interface{}) {
+	ch.systemCheckHistoryComponent.FillFromDottedMap(m)
+
+	// setting public field value from dotted map
+	ch.TotalUsageCore = float64FromMap(m, "total_usage_core")
+	ch.DockerUsageCore = float64FromMap(m, "docker_usage_core")
+	ch.TemporaryFreeCore = float64FromMap(m, "temporary_free_core")
+	ch.MostCPUConsumeContainer = stringFromMap(m, "most_cpu_consume_container")
+	ch.RemovedContainer = stringFromMap(m, "removed_container")
+}
+
+// NewAlert overriding NewAlert method of systemCheckHistoryComponent, setting measurements with cpu check history field
+func (ch *CPUCheckHistory) NewAlert(level AlertLevel, text string) (alert *Alert) {
+	alert = ch.systemCheckHistoryComponent.NewAlert(level, text)
+
+	// setting measured field value in measurements
+	alert.Measurements["total_usage_core"] = ch.TotalUsageCore
+	alert.Measurements["docker_usage_core"] = ch.DockerUsageCore
+	alert.Measurements["temporary_free_core"] = ch.TemporaryFreeCore
+	alert.Measurements["most_cpu_consume_container"] = ch.MostCPUConsumeContainer
+	alert.Measurements["removed_container"] = ch.RemovedContainer
 
 	return
 }
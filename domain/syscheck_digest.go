@@ -0,0 +1,46 @@
+// syscheck_digest.go is file that declare model struct & usecase interface about digest of syscheck domain.
+// digest summarize system check histories stored in period (Ex, daily, weekly)
+
+package domain
+
+import (
+	"context"
+	"github.com/inhies/go-bytesize"
+	"time"
+)
+
+// SystemCheckDigest model is used for summarizing system check histories stored in period
+type SystemCheckDigest struct {
+	// Period specifies name of period summarized in this digest (Ex, daily, weekly)
+	Period string
+
+	// Start specifies start time of period (inclusive)
+	Start time.Time
+
+	// End specifies end time of period (exclusive)
+	End time.Time
+
+	// LevelCounts specifies count of each process level per check type (Ex, cpu -> HEALTHY -> 1440)
+	LevelCounts map[string]map[string]int
+
+	// RemovedContainers specifies number of container removed in cpu & memory check to recover
+	RemovedContainers int
+
+	// ReclaimedCap specifies total disk capacity reclaimed by docker system prune in disk check
+	ReclaimedCap bytesize.ByteSize
+
+	// PeakCPUUsageCore specifies the highest total cpu usage looked in cpu check
+	PeakCPUUsageCore float64
+
+	// PeakMemoryUsage specifies the highest total memory usage looked in memory check
+	PeakMemoryUsage bytesize.ByteSize
+
+	// MinRemainingCap specifies the lowest remaining disk capacity looked in disk check
+	MinRemainingCap bytesize.ByteSize
+}
+
+// SystemCheckDigestUseCase is interface used as business process handler about digest of system check
+type SystemCheckDigestUseCase interface {
+	// Digest method summarize system check histories stored between start & end, post it as report & return that digest
+	Digest(ctx context.Context, period string, start, end time.Time) (digest *SystemCheckDigest, err error)
+}
@@ -7,6 +7,7 @@ package domain
 import (
 	"context"
 	"github.com/inhies/go-bytesize"
+	"time"
 )
 
 // DiskCheckHistory model is used for record disk health check history and result
@@ -30,12 +31,42 @@ type DiskCheckHistoryRepository interface {
 	// Store method save DiskCheckHistory model in repository
 	// b in return represents bytes of response body(map[string]interface{})
 	Store(*DiskCheckHistory) (b []byte, err error)
+
+	// FindByTimeRange method return DiskCheckHistory stored between start (inclusive) & end (exclusive) in order of time
+	FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*DiskCheckHistory, err error)
+
+	// GetByUUID method return DiskCheckHistory having uuid (nil if not exist)
+	GetByUUID(ctx context.Context, uuid string) (history *DiskCheckHistory, err error)
+
+	// FindByProcessLevel method return DiskCheckHistory having process level & stored between start (inclusive) & end (exclusive) in order of time
+	FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*DiskCheckHistory, err error)
+
+	// FindLatest method return latest n DiskCheckHistory in order of newest first
+	FindLatest(ctx context.Context, n int) (histories []*DiskCheckHistory, err error)
 }
 
 // DiskCheckUseCase is interface used as business process handler about disk check
 type DiskCheckUseCase interface {
-	// CheckDisk method check disk capacity status and store disk check history using repository
-	CheckDisk(ctx context.Context) error
+	// get required component by embedding systemCheckUsecaseComponent
+	systemCheckUsecaseComponent
+
+	// CheckDisk method check disk capacity status and store disk check history using repository, then return that history
+	CheckDisk(ctx context.Context) (history *DiskCheckHistory, err error)
+
+	// GetLastHistory method return disk check history produced in last check process (nil if not checked yet)
+	GetLastHistory() *DiskCheckHistory
+
+	// ResetStatus method reset status of disk check process to healthy by administrator & store history about that reset
+	ResetStatus(ctx context.Context, by, reason string) (history *DiskCheckHistory, err error)
+
+	// GetHistory method return disk check history having uuid in repository (nil if not exist)
+	GetHistory(ctx context.Context, uuid string) (history *DiskCheckHistory, err error)
+
+	// FindHistories method return disk check histories stored between start & end in order of time, filtered by process level if not empty
+	FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*DiskCheckHistory, err error)
+
+	// FindLatestHistories method return latest n disk check histories in repository in order of newest first
+	FindLatestHistories(ctx context.Context, n int) (histories []*DiskCheckHistory, err error)
 }
 
 // FillPrivateComponent overriding FillPrivateComponent method of systemCheckHistoryComponent
@@ -53,9 +84,31 @@ func (dh *DiskCheckHistory) DottedMapWithPrefix(prefix string) (m map[string]int
 		prefix += "."
 	}
 
-	// setting public field value in dotted map
-	m[prefix + "remaining_capacity"] = dh.RemainingCap.String()
-	m[prefix + "reclaimed_capacity"] = dh.ReclaimedCap.String()
+	// setting public field value in dotted map, byte size is set as number of bytes with human readable companion
+	m[prefix + "remaining_capacity"] = uint64(dh.RemainingCap)
+	m[prefix + "remaining_capacity_human"] = dh.RemainingCap.String()
+	m[prefix + "reclaimed_capacity"] = uint64(dh.ReclaimedCap)
+	m[prefix + "reclaimed_capacity_human"] = dh.ReclaimedCap.String()
+
+	return
+}
+
+// FillFromDottedMap overriding FillFromDottedMap method of systemCheckHistoryComponent, filling DiskCheckHistory field from dotted map
+func (dh *DiskCheckHistory) FillFromDottedMap(m map[string]interface{}) {
+	dh.systemCheckHistoryComponent.FillFromDottedMap(m)
+
+	// setting public field value from dotted map
+	dh.RemainingCap = bytesizeFromMap(m, "remaining_capacity")
+	dh.ReclaimedCap = bytesizeFromMap(m, "reclaimed_capacity")
+}
+
+// NewAlert overriding NewAlert method of systemCheckHistoryComponent, setting measurements with disk check history field
+func (dh *DiskCheckHistory) NewAlert(level AlertLevel, text string) (alert *Alert) {
+	alert = dh.systemCheckHistoryComponent.NewAlert(level, text)
+
+	// setting measured field value in measurements
+	alert.Measurements["remaining_capacity"] = dh.RemainingCap
+	alert.Measurements["reclaimed_capacity"] = dh.ReclaimedCap
 
 	return
 }
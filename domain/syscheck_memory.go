@@ -7,6 +7,7 @@ package domain
 import (
 	"context"
 	"github.com/inhies/go-bytesize"
+	"time"
 )
 
 // MemCheckHistory model is used for record memory health check history and result
@@ -25,6 +26,9 @@ type MemoryCheckHistory struct {
 
 	// MostMemoryConsumeContainer specifies the container name which is consumed most memory
 	MostMemoryConsumeContainer string
+
+	// RemovedContainer specifies the container name which is removed to recover memory health (empty if not removed)
+	RemovedContainer string
 }
 
 // MemoryCheckHistoryRepository is interface for repository layer used in usecase layer
@@ -36,12 +40,42 @@ type MemoryCheckHistoryRepository interface {
 	// Store method save MemoryCheckHistory model in repository
 	// b in return represents bytes of response body(map[string]interface{})
 	Store(*MemoryCheckHistory) (b []byte, err error)
+
+	// FindByTimeRange method return MemoryCheckHistory stored between start (inclusive) & end (exclusive) in order of time
+	FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*MemoryCheckHistory, err error)
+
+	// GetByUUID method return MemoryCheckHistory having uuid (nil if not exist)
+	GetByUUID(ctx context.Context, uuid string) (history *MemoryCheckHistory, err error)
+
+	// FindByProcessLevel method return MemoryCheckHistory having process level & stored between start (inclusive) & end (exclusive) in order of time
+	FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*MemoryCheckHistory, err error)
+
+	// FindLatest method return latest n MemoryCheckHistory in order of newest first
+	FindLatest(ctx context.Context, n int) (histories []*MemoryCheckHistory, err error)
 }
 
 // MemoryCheckUseCase is interface used as business process handler about memory check
 type MemoryCheckUseCase interface {
-	// CheckMemory method check memory usage status and store memory check history using repository
-	CheckMemory(ctx context.Context) error
+	// get required component by embedding systemCheckUsecaseComponent
+	systemCheckUsecaseComponent
+
+	// CheckMemory method check memory usage status and store memory check history using repository, then return that history
+	CheckMemory(ctx context.Context) (history *MemoryCheckHistory, err error)
+
+	// GetLastHistory method return memory check history produced in last check process (nil if not checked yet)
+	GetLastHistory() *MemoryCheckHistory
+
+	// ResetStatus method reset status of memory check process to healthy by administrator & store history about that reset
+	ResetStatus(ctx context.Context, by, reason string) (history *MemoryCheckHistory, err error)
+
+	// GetHistory method return memory check history having uuid in repository (nil if not exist)
+	GetHistory(ctx context.Context, uuid string) (history *MemoryCheckHistory, err error)
+
+	// FindHistories method return memory check histories stored between start & end in order of time, filtered by process level if not empty
+	FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*MemoryCheckHistory, err error)
+
+	// FindLatestHistories method return latest n memory check histories in repository in order of newest first
+	FindLatestHistories(ctx context.Context, n int) (histories []*MemoryCheckHistory, err error)
 }
 
 // FillPrivateComponent overriding FillPrivateComponent method of systemCheckHistoryComponent
@@ -59,11 +93,41 @@ func (mc *MemoryCheckHistory) DottedMapWithPrefix(prefix string) (m map[string]i
 		prefix += "."
 	}
 
-	// setting public field value in dotted map
-	m[prefix + "total_usage_memory"] = mc.TotalUsageMemory.String()
-	m[prefix + "docker_usage_memory"] = mc.DockerUsageMemory.String()
-	m[prefix + "temporary_free_memory"] = mc.TemporaryFreeMemory.String()
+	// setting public field value in dotted map, byte size is set as number of bytes with human readable companion
+	m[prefix + "total_usage_memory"] = uint64(mc.TotalUsageMemory)
+	m[prefix + "total_usage_memory_human"] = mc.TotalUsageMemory.String()
+	m[prefix + "docker_usage_memory"] = uint64(mc.DockerUsageMemory)
+	m[prefix + "docker_usage_memory_human"] = mc.DockerUsageMemory.String()
+	m[prefix + "temporary_free_memory"] = uint64(mc.TemporaryFreeMemory)
+	m[prefix + "temporary_free_memory_human"] = mc.TemporaryFreeMemory.String()
 	m[prefix + "most_memory_consume_container"] = mc.MostMemoryConsumeContainer
+	m[prefix + "removed_container"] = mc.RemovedContainer
+
+	return
+}
+
+// FillFromDottedMap overriding FillFromDottedMap method of systemCheckHistoryComponent, filling MemoryCheckHistory field from dotted map
+func (mc *MemoryCheckHistory) FillFromDottedMap(m map[string]interface{}) {
+	mc.systemCheckHistoryComponent.FillFromDottedMap(m)
+
+	// setting public field value from dotted map
+	mc.TotalUsageMemory = bytesizeFromMap(m, "total_usage_memory")
+	mc.DockerUsageMemory = bytesizeFromMap(m, "docker_usage_memory")
+	mc.TemporaryFreeMemory = bytesizeFromMap(m, "temporary_free_memory")
+	mc.MostMemoryConsumeContainer = stringFromMap(m, "most_memory_consume_container")
+	mc.RemovedContainer = stringFromMap(m, "removed_container")
+}
+
+// NewAlert overriding NewAlert method of systemCheckHistoryComponent, setting measurements with memory check history field
+func (mc *MemoryCheckHistory) NewAlert(level AlertLevel, text string) (alert *Alert) {
+	alert = mc.systemCheckHistoryComponent.NewAlert(level, text)
+
+	// setting measured field value in measurements
+	alert.Measurements["total_usage_memory"] = mc.TotalUsageMemory
+	alert.Measurements["docker_usage_memory"] = mc.DockerUsageMemory
+	alert.Measurements["temporary_free_memory"] = mc.TemporaryFreeMemory
+	alert.Measurements["most_memory_consume_container"] = mc.MostMemoryConsumeContainer
+	alert.Measurements["removed_container"] = mc.RemovedContainer
 
 	return
 }
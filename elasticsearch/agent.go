@@ -1,6 +1,7 @@
 // Create package in v.1.0.0
 // elasticsearch package define struct which is implement various interface about elasticsearch agency using in usecase each of domain
 // there are kind of elasticsearch agency function such as get or delete cluster, indices
+// bulk agent buffer history documents & index them with bulk API, spooling them in disk while elasticsearch is unavailable
 
 // in agent.go file, define struct type of elasticsearch agent & initializer that are not method.
 // Also if exist, custom type or variable used in common in each of method will declared in this file.
@@ -8,7 +9,10 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"github.com/elastic/go-elasticsearch/v7"
+	"sync"
+	"time"
 )
 
 // slackAgent agent various elasticsearch API(get or delete cluster, indices, etc ...) as implementation
@@ -23,3 +27,76 @@ func NewAgent(ec *elasticsearch.Client) *elasticsearchAgent {
 		esCli: ec,
 	}
 }
+
+// historySpool is agency that keep history documents failed to index in elasticsearch to survive restart
+// lines are appended & loaded in order, so documents are replayed in the same order as stored
+type historySpool interface {
+	// AppendHistorySpool append lines at the end of spool
+	AppendHistorySpool(lines [][]byte) (err error)
+
+	// LoadHistorySpool load all lines in spool in order of appended
+	LoadHistorySpool() (lines [][]byte, err error)
+
+	// StoreHistorySpool overwrite spool with lines, used for removing lines replayed successfully
+	StoreHistorySpool(lines [][]byte) (err error)
+}
+
+// bulkAgent buffer history documents & index them with bulk API in every flush interval or when buffer is full
+// documents failed to index are spooled in history spool, and replayed in order before buffered documents
+type bulkAgent struct {
+	// esCli is elasticsearch client connection injected from the outside package
+	esCli *elasticsearch.Client
+
+	// spool is used for keeping documents failed to index until elasticsearch recovers
+	spool historySpool
+
+	// flushSize is number of buffered documents which makes agent flush before flush interval
+	flushSize int
+
+	// flushInterval is interval to flush buffered documents
+	flushInterval time.Duration
+
+	// buffer is slice of bulk action waiting flush
+	buffer []bulkAction
+
+	// flushC is used for notifying that buffer is full to goroutine started in Run method
+	flushC chan struct{}
+
+	// mutex help to prevent race condition when access buffer field
+	mutex sync.Mutex
+
+	// flushMutex serialize flush so that documents are indexed or spooled in order
+	flushMutex sync.Mutex
+
+	// field in below is about replay of history spool, and accessed only in flush with flushMutex locked
+	// spooled is true if history spool may have documents, it is true at first as documents may be spooled before restart
+	spooled bool
+
+	// replayBackoff is backoff before next replay after replay failed, doubled at every failure
+	replayBackoff time.Duration
+
+	// nextReplay is time to replay history spool next, spool is not loaded before it while elasticsearch is unavailable
+	nextReplay time.Time
+}
+
+// bulkAction is struct having action of bulk API about one document, also used as line of history spool
+// document ID is history UUID & create action is used, so replaying same document more than once is ignored
+// spooled document is resolved through alias before replayed, so it isn't duplicated in new index after rollover
+type bulkAction struct {
+	Action string          `json:"action"` // create or update
+	Index  string          `json:"index"`
+	ID     string          `json:"id"`
+	Doc    json.RawMessage `json:"doc"`
+}
+
+// NewBulkAgent return new initialized instance of bulkAgent pointer type with elasticsearch client & history spool
+func NewBulkAgent(ec *elasticsearch.Client, spool historySpool, flushSize int, flushInterval time.Duration) *bulkAgent {
+	return &bulkAgent{
+		esCli:         ec,
+		spool:         spool,
+		flushSize:     flushSize,
+		flushInterval: flushInterval,
+		flushC:        make(chan struct{}, 1),
+		spooled:       true,
+	}
+}
@@ -0,0 +1,365 @@
+// agent_bulk.go file define method of bulkAgent about indexing history documents with bulk API
+// implement agency interface about bulk indexer defined in repository package of each domain
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// bulkTimeout is timeout of one bulk request
+const bulkTimeout = time.Second * 10
+
+// replayMaxBackoff is max backoff between replays of history spool while elasticsearch is unavailable
+const replayMaxBackoff = time.Minute * 5
+
+// Index buffer document to create in index with id, document already existing with same id is not overwritten
+func (ba *bulkAgent) Index(index, id string, doc []byte) error {
+	return ba.add("create", index, id, doc)
+}
+
+// Update buffer partial document to update document having id in index, applied after document is created
+func (ba *bulkAgent) Update(index, id string, doc []byte) error {
+	return ba.add("update", index, id, doc)
+}
+
+// add append bulk action to buffer, and notify that buffer is full if number of buffered action reached flush size
+func (ba *bulkAgent) add(action, index, id string, doc []byte) error {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, doc); err != nil {
+		return errors.Wrap(err, "failed to compact document")
+	}
+
+	ba.mutex.Lock()
+	ba.buffer = append(ba.buffer, bulkAction{Action: action, Index: index, ID: id, Doc: buf.Bytes()})
+	full := len(ba.buffer) >= ba.flushSize
+	ba.mutex.Unlock()
+
+	if full {
+		select {
+		case ba.flushC <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Run start to flush buffered documents every flush interval or when buffer is full until ctx is done
+// documents buffered after ctx is done should be flushed by calling Flush method while shutting down
+func (ba *bulkAgent) Run(ctx context.Context, wg *sync.WaitGroup) {
+	// count flushing goroutine in wait group, so that final Flush is called after periodic flush in progress is finished
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(ba.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-ba.flushC:
+			}
+			ba.Flush()
+		}
+	}()
+}
+
+// Flush replay spooled documents & index buffered documents with bulk API, spooling documents failed to index
+// buffered documents are spooled without indexing if spool is not replayed fully, to keep order of documents
+func (ba *bulkAgent) Flush() {
+	ba.flushMutex.Lock()
+	defer ba.flushMutex.Unlock()
+
+	ba.mutex.Lock()
+	actions := ba.buffer
+	ba.buffer = nil
+	ba.mutex.Unlock()
+
+	if !ba.replay() {
+		ba.spoolActions(actions)
+		return
+	}
+
+	for len(actions) > 0 {
+		chunk := actions
+		if len(chunk) > ba.flushSize {
+			chunk = chunk[:ba.flushSize]
+		}
+		actions = actions[len(chunk):]
+
+		if failed, err := ba.bulk(chunk); err != nil {
+			log.Printf("failed to index history documents with bulk API, spool them, err: %v", err)
+			ba.spoolActions(append(failed, actions...))
+			return
+		}
+	}
+}
+
+// replay index spooled documents if replay time has come, and return if spool is replayed fully
+// replay is backed off after failure, so that whole spool isn't loaded & rewritten in every flush while elasticsearch is down
+func (ba *bulkAgent) replay() bool {
+	if !ba.spooled {
+		return true
+	}
+	if time.Now().Before(ba.nextReplay) {
+		return false
+	}
+
+	if ba.replaySpool() {
+		ba.spooled, ba.replayBackoff = false, 0
+		return true
+	}
+
+	ba.replayBackoff *= 2
+	if ba.replayBackoff == 0 {
+		ba.replayBackoff = ba.flushInterval
+	}
+	if ba.replayBackoff > replayMaxBackoff {
+		ba.replayBackoff = replayMaxBackoff
+	}
+	ba.nextReplay = time.Now().Add(ba.replayBackoff)
+	log.Printf("failed to replay history spool, next replay is after %s", ba.replayBackoff)
+	return false
+}
+
+// replaySpool index spooled documents in order & remove them from spool, and return if spool is replayed fully
+// spool is rewritten only if any line is replayed or dropped, as it is unchanged otherwise
+func (ba *bulkAgent) replaySpool() bool {
+	lines, err := ba.spool.LoadHistorySpool()
+	if err != nil {
+		log.Printf("failed to load history spool, err: %v", err)
+		return false
+	}
+	if len(lines) == 0 {
+		return true
+	}
+
+	actions := make([]bulkAction, 0, len(lines))
+	for _, line := range lines {
+		action := bulkAction{}
+		if err := json.Unmarshal(line, &action); err != nil {
+			log.Printf("dropped broken line in history spool, line: %s, err: %v", string(line), err)
+			continue
+		}
+		actions = append(actions, action)
+	}
+	changed := len(actions) != len(lines)
+
+	for replayed := 0; replayed < len(actions); {
+		end := replayed + ba.flushSize
+		if end > len(actions) {
+			end = len(actions)
+		}
+
+		// spooled document may be created already before index was rolled over, so it is resolved through alias first
+		chunk, err := ba.resolve(actions[replayed:end])
+		if err != nil {
+			log.Printf("failed to resolve index of spooled history documents, err: %v", err)
+			if changed || replayed != 0 {
+				ba.storeSpool(actions[replayed:])
+			}
+			return false
+		}
+
+		if failed, err := ba.bulk(chunk); err != nil {
+			if changed || replayed != 0 || len(failed) != end-replayed {
+				ba.storeSpool(append(failed, actions[end:]...))
+			}
+			return false
+		}
+		replayed = end
+	}
+
+	if err := ba.spool.StoreHistorySpool(nil); err != nil {
+		log.Printf("failed to store history spool, err: %v", err)
+		return false
+	}
+	log.Printf("replayed %d spooled history documents to elasticsearch", len(actions))
+	return true
+}
+
+// resolve find index including document of each action among indices which alias (index of action) points
+// create action of document already existing in any index is removed, and update action is set to index including document
+func (ba *bulkAgent) resolve(actions []bulkAction) (resolved []bulkAction, err error) {
+	ids := map[string][]string{}
+	for _, action := range actions {
+		ids[action.Index] = append(ids[action.Index], action.ID)
+	}
+
+	indices := map[string]string{}
+	for alias, values := range ids {
+		found, err := ba.indicesOfDocuments(alias, values)
+		if err != nil {
+			return nil, err
+		}
+		for id, index := range found {
+			indices[alias+"/"+id] = index
+		}
+	}
+
+	resolved = make([]bulkAction, 0, len(actions))
+	for _, action := range actions {
+		index, ok := indices[action.Index+"/"+action.ID]
+		switch {
+		case ok && action.Action == "create":
+			continue
+		case ok:
+			action.Index = index
+		}
+		resolved = append(resolved, action)
+	}
+	return
+}
+
+// indicesOfDocuments return map of id to name of index including document having that id among indices which alias points
+func (ba *bulkAgent) indicesOfDocuments(alias string, ids []string) (indices map[string]string, err error) {
+	body := map[string]interface{}{
+		"query":   map[string]interface{}{"ids": map[string]interface{}{"values": ids}},
+		"_source": false,
+	}
+	b, _ := json.Marshal(body)
+
+	ctx, cancel := context.WithTimeout(context.Background(), bulkTimeout)
+	defer cancel()
+
+	size := len(ids)
+	resp, err := (esapi.SearchRequest{
+		Index: []string{alias},
+		Body:  bytes.NewReader(b),
+		Size:  &size,
+	}).Do(ctx, ba.esCli)
+
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to call SearchRequest, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	indices = map[string]string{}
+	if resp.StatusCode == http.StatusNotFound {
+		return indices, nil
+	} else if resp.IsError() {
+		return nil, errors.Errorf("SearchRequest return error code, resp: %+v", resp)
+	}
+
+	result := struct {
+		Hits struct {
+			Hits []struct {
+				Index string `json:"_index"`
+				ID    string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode search response body")
+	}
+	for _, hit := range result.Hits.Hits {
+		indices[hit.ID] = hit.Index
+	}
+	return indices, nil
+}
+
+// storeSpool overwrite history spool with actions which are not replayed yet
+func (ba *bulkAgent) storeSpool(actions []bulkAction) {
+	if err := ba.spool.StoreHistorySpool(marshalActions(actions)); err != nil {
+		log.Printf("failed to store history spool, err: %v", err)
+	}
+}
+
+// spoolActions append actions at the end of history spool, actions are lost if failed to append
+func (ba *bulkAgent) spoolActions(actions []bulkAction) {
+	if len(actions) == 0 {
+		return
+	}
+	ba.spooled = true
+	if err := ba.spool.AppendHistorySpool(marshalActions(actions)); err != nil {
+		log.Printf("failed to spool %d history documents, they are lost, err: %v", len(actions), err)
+	}
+}
+
+// bulkResult is struct used for decoding response body of bulk request
+type bulkResult struct {
+	Items []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// bulk send actions in a bulk request, and return actions which should be retried in order with error if exist
+// document already created is regarded as succeed, and action failed with client error is dropped as it never succeeds
+func (ba *bulkAgent) bulk(actions []bulkAction) (failed []bulkAction, err error) {
+	body := &bytes.Buffer{}
+	for _, action := range actions {
+		meta := map[string]interface{}{"_index": action.Index, "_id": action.ID}
+		doc := action.Doc
+		if action.Action == "update" {
+			meta["retry_on_conflict"] = 3
+			doc, _ = json.Marshal(map[string]interface{}{"doc": action.Doc})
+		}
+		b, _ := json.Marshal(map[string]interface{}{action.Action: meta})
+		body.Write(b)
+		body.WriteByte('\n')
+		body.Write(doc)
+		body.WriteByte('\n')
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bulkTimeout)
+	defer cancel()
+
+	resp, err := (esapi.BulkRequest{
+		Body:    bytes.NewReader(body.Bytes()),
+		Timeout: bulkTimeout,
+	}).Do(ctx, ba.esCli)
+	if err != nil {
+		return actions, errors.Wrap(err, fmt.Sprintf("failed to call BulkRequest, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return actions, errors.Errorf("BulkRequest return error code, resp: %+v", resp)
+	}
+
+	result := bulkResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.Items) != len(actions) {
+		return actions, errors.Errorf("failed to decode items of BulkRequest response, err: %v", err)
+	}
+
+	retried := map[string]bool{}
+	for i, action := range actions {
+		item := result.Items[i][action.Action]
+		switch {
+		case item.Status >= 200 && item.Status < 300:
+		case action.Action == "create" && item.Status == http.StatusConflict:
+		case item.Status == http.StatusTooManyRequests || item.Status >= 500,
+			action.Action == "update" && item.Status == http.StatusNotFound && retried[action.Index+"/"+action.ID]:
+			retried[action.Index+"/"+action.ID] = true
+			failed = append(failed, action)
+		default:
+			log.Printf("dropped history document failed to %s in bulk API, id: %s, err: %s", action.Action, action.ID, string(item.Error))
+		}
+	}
+
+	if len(failed) != 0 {
+		err = errors.Errorf("%d of %d actions failed in BulkRequest", len(failed), len(actions))
+	}
+	return
+}
+
+// marshalActions return slice of actions marshaled in JSON, used as lines of history spool
+func marshalActions(actions []bulkAction) (lines [][]byte) {
+	lines = make([][]byte, 0, len(actions))
+	for _, action := range actions {
+		b, _ := json.Marshal(action)
+		lines = append(lines, b)
+	}
+	return
+}
@@ -0,0 +1,260 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+
+	"github.com/DMS-SMS/v1-health-check/file"
+)
+
+// esStandIn is local stand-in of elasticsearch, failing every request while it is down
+// it records bulk actions in order & answers search by ids with documents created in it or registered as existing
+type esStandIn struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	down     bool
+	requests int
+	actions  []string          // bulk actions received in order (Ex, "create sms-system-check uuid-1")
+	indices  map[string]string // index of created or existing document with id as key
+}
+
+func newESStandIn() *esStandIn {
+	s := &esStandIn{indices: map[string]string{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *esStandIn) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests++
+	w.Header().Set("Content-Type", "application/json")
+	if s.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/_bulk":
+		var items []string
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			meta := map[string]struct {
+				Index string `json:"_index"`
+				ID    string `json:"_id"`
+			}{}
+			_ = json.Unmarshal(scanner.Bytes(), &meta)
+			scanner.Scan() // skip document line
+
+			for action, m := range meta {
+				s.actions = append(s.actions, strings.Join([]string{action, m.Index, m.ID}, " "))
+				status := http.StatusOK
+				if _, ok := s.indices[m.ID]; action == "create" && ok {
+					status = http.StatusConflict
+				} else if action == "create" {
+					status, s.indices[m.ID] = http.StatusCreated, m.Index
+				}
+				items = append(items, fmt.Sprintf(`{%q: {"status": %d}}`, action, status))
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"items": [%s]}`, strings.Join(items, ","))
+	case strings.HasSuffix(r.URL.Path, "/_search"):
+		body := struct {
+			Query struct {
+				IDs struct {
+					Values []string `json:"values"`
+				} `json:"ids"`
+			} `json:"query"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		var hits []string
+		for _, id := range body.Query.IDs.Values {
+			if index, ok := s.indices[id]; ok {
+				hits = append(hits, fmt.Sprintf(`{"_index": %q, "_id": %q}`, index, id))
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"hits": {"hits": [%s]}}`, strings.Join(hits, ","))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *esStandIn) setDown(down bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.down = down
+}
+
+// takeActions return bulk actions received until now & reset them
+func (s *esStandIn) takeActions() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	actions := s.actions
+	s.actions = nil
+	return actions
+}
+
+func (s *esStandIn) requestCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+// newTestBulkAgent return bulk agent sending request to stand-in & spooling in file having max size in temp dir
+func newTestBulkAgent(t *testing.T, standIn *esStandIn, spoolMaxSize int64) (*bulkAgent, historySpool) {
+	cli, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{standIn.URL}, DisableRetry: true})
+	if err != nil {
+		t.Fatalf("elasticsearch client should be created, err: %v", err)
+	}
+	spool := file.NewAgent("", "", filepath.Join(t.TempDir(), "history_spool.jsonl"), "", spoolMaxSize)
+	return NewBulkAgent(cli, spool, 2, time.Minute), spool
+}
+
+// spooledIDs return id of documents in history spool in order
+func spooledIDs(t *testing.T, spool historySpool) (ids []string) {
+	lines, err := spool.LoadHistorySpool()
+	if err != nil {
+		t.Fatalf("history spool should be loaded, err: %v", err)
+	}
+	for _, line := range lines {
+		action := bulkAction{}
+		_ = json.Unmarshal(line, &action)
+		ids = append(ids, action.ID)
+	}
+	return
+}
+
+// indexDocuments buffer create action of documents having ids in bulk agent
+func indexDocuments(t *testing.T, ba *bulkAgent, ids ...string) {
+	for _, id := range ids {
+		if err := ba.Index("sms-system-check", id, []byte(fmt.Sprintf(`{"uuid": %q}`, id))); err != nil {
+			t.Fatalf("document should be buffered, err: %v", err)
+		}
+	}
+}
+
+func TestBulkAgentReplaysSpoolInOrderAfterRecovery(t *testing.T) {
+	standIn := newESStandIn()
+	defer standIn.Close()
+	ba, spool := newTestBulkAgent(t, standIn, 1024*1024)
+
+	standIn.setDown(true)
+	indexDocuments(t, ba, "uuid-1", "uuid-2", "uuid-3")
+	ba.Flush()
+	indexDocuments(t, ba, "uuid-4")
+	ba.Flush()
+	if ids := strings.Join(spooledIDs(t, spool), ","); ids != "uuid-1,uuid-2,uuid-3,uuid-4" {
+		t.Fatalf("documents failed to index should be spooled in order, got: %s", ids)
+	}
+	if ba.replayBackoff != ba.flushInterval {
+		t.Errorf("replay should be backed off with flush interval after first failure, got: %s", ba.replayBackoff)
+	}
+
+	// replay is not tried until backoff is passed, buffered document is spooled behind without request
+	requests := standIn.requestCount()
+	indexDocuments(t, ba, "uuid-5")
+	ba.Flush()
+	if standIn.requestCount() != requests {
+		t.Errorf("request should not be sent before replay backoff is passed, got: %d requests", standIn.requestCount()-requests)
+	}
+
+	// backoff is doubled at every failure & limited to max backoff
+	for _, want := range []time.Duration{ba.flushInterval * 2, ba.flushInterval * 4, replayMaxBackoff, replayMaxBackoff} {
+		ba.nextReplay = time.Now().Add(-time.Second)
+		ba.Flush()
+		if ba.replayBackoff != want {
+			t.Errorf("replay backoff should be %s, got: %s", want, ba.replayBackoff)
+		}
+	}
+
+	standIn.setDown(false)
+	ba.nextReplay = time.Now().Add(-time.Second)
+	indexDocuments(t, ba, "uuid-6")
+	ba.Flush()
+
+	want := []string{"uuid-1", "uuid-2", "uuid-3", "uuid-4", "uuid-5", "uuid-6"}
+	actions := standIn.takeActions()
+	if len(actions) != len(want) {
+		t.Fatalf("spooled & buffered documents should be indexed, got: %v", actions)
+	}
+	for i, id := range want {
+		if actions[i] != "create sms-system-check "+id {
+			t.Errorf("documents should be indexed in order, %d-th action should create %s, got: %s", i, id, actions[i])
+		}
+	}
+	if ids := spooledIDs(t, spool); len(ids) != 0 {
+		t.Errorf("replayed documents should be removed from spool, got: %v", ids)
+	}
+	if ba.spooled || ba.replayBackoff != 0 {
+		t.Error("replay backoff should be reset after spool is replayed fully")
+	}
+}
+
+func TestBulkAgentResolvesSpooledDocumentsThroughAlias(t *testing.T) {
+	standIn := newESStandIn()
+	defer standIn.Close()
+	ba, _ := newTestBulkAgent(t, standIn, 1024*1024)
+
+	standIn.setDown(true)
+	indexDocuments(t, ba, "uuid-1", "uuid-2")
+	if err := ba.Update("sms-system-check", "uuid-1", []byte(`{"alerted": true}`)); err != nil {
+		t.Fatalf("update should be buffered, err: %v", err)
+	}
+	ba.Flush()
+	_ = standIn.takeActions()
+
+	// uuid-1 was created before index was rolled over, but response of creation was lost
+	standIn.mutex.Lock()
+	standIn.indices["uuid-1"] = "sms-system-check-v1-000001"
+	standIn.mutex.Unlock()
+	standIn.setDown(false)
+	ba.nextReplay = time.Now().Add(-time.Second)
+	ba.Flush()
+
+	want := []string{
+		"create sms-system-check uuid-2",
+		"update sms-system-check-v1-000001 uuid-1",
+	}
+	if actions := standIn.takeActions(); strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Errorf("create of document already existing should be removed & update sent to index including it, got: %v", actions)
+	}
+}
+
+func TestBulkAgentSpoolCapDropsOldestDocuments(t *testing.T) {
+	standIn := newESStandIn()
+	defer standIn.Close()
+
+	// every spool line has same length, so that max size keeps exactly three lines
+	line := marshalActions([]bulkAction{{Action: "create", Index: "sms-system-check", ID: "uuid-1", Doc: []byte(`{"uuid":"uuid-1"}`)}})[0]
+	ba, spool := newTestBulkAgent(t, standIn, int64(len(line)+1)*3)
+
+	standIn.setDown(true)
+	indexDocuments(t, ba, "uuid-1", "uuid-2")
+	ba.Flush()
+	indexDocuments(t, ba, "uuid-3", "uuid-4", "uuid-5")
+	ba.Flush()
+	if ids := strings.Join(spooledIDs(t, spool), ","); ids != "uuid-3,uuid-4,uuid-5" {
+		t.Fatalf("the oldest documents should be dropped when spool exceeds max size, got: %s", ids)
+	}
+
+	standIn.setDown(false)
+	ba.nextReplay = time.Now().Add(-time.Second)
+	ba.Flush()
+	want := "create sms-system-check uuid-3,create sms-system-check uuid-4,create sms-system-check uuid-5"
+	if actions := strings.Join(standIn.takeActions(), ","); actions != want {
+		t.Errorf("documents kept in spool should be replayed in order, got: %s", actions)
+	}
+}
@@ -14,14 +14,10 @@ import (
 )
 
 // GetIndicesWithRegexp return indices list with regexp pattern
-func (ea *elasticsearchAgent) GetIndicesWithPatterns(patterns []string) (interface {
+func (ea *elasticsearchAgent) GetIndicesWithPatterns(ctx context.Context, patterns []string) (interface {
 	SetMinLifeCycle(cycle time.Duration) // set min life cycle of index of indices
 	IndexNames() []string                // get index name list of indices
 }, error) {
-	var (
-		ctx = context.Background()
-	)
-
 	resp, err := (esapi.CatIndicesRequest{
 		Index:         patterns,
 		Format:        "JSON",
@@ -64,11 +60,7 @@ func (ea *elasticsearchAgent) GetIndicesWithPatterns(patterns []string) (interfa
 }
 
 // DeleteIndices method delete indices in list received from parameter
-func (ea *elasticsearchAgent) DeleteIndices(indices []string) (err error) {
-	var (
-		ctx = context.Background()
-	)
-
+func (ea *elasticsearchAgent) DeleteIndices(ctx context.Context, indices []string) (err error) {
 	resp, err := (esapi.IndicesDeleteRequest{
 		Index:         indices,
 		MasterTimeout: time.Second * 5,
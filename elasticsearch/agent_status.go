@@ -0,0 +1,77 @@
+// agent_status.go file define method of elasticsearchAgent about status of check process
+// implement agency interface about status store defined in each of domain, with document per check in status index
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/pkg/errors"
+	"net/http"
+	"time"
+)
+
+// statusIndex is name of elasticsearch index storing status of check process as document having key as id
+const statusIndex = "sms-check-status"
+
+// LoadStatus load status & last transition time of check saved with key in status index (empty status if not saved yet)
+func (ea *elasticsearchAgent) LoadStatus(ctx context.Context, key string) (status string, transitedAt time.Time, err error) {
+	resp, err := (esapi.GetRequest{
+		Index:      statusIndex,
+		DocumentID: key,
+	}).Do(ctx, ea.esCli)
+
+	if err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("failed to call GetRequest, resp: %+v", resp))
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return
+	} else if resp.IsError() {
+		err = errors.Errorf("GetRequest return error code, resp: %+v", resp)
+		return
+	}
+
+	var doc struct {
+		Source struct {
+			Status      string    `json:"status"`
+			TransitedAt time.Time `json:"transited_at"`
+		} `json:"_source"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		err = errors.Wrap(err, "failed to decode resp body to struct")
+		return
+	}
+
+	return doc.Source.Status, doc.Source.TransitedAt, nil
+}
+
+// StoreStatus store status & last transition time of check with key in status index
+func (ea *elasticsearchAgent) StoreStatus(ctx context.Context, key, status string, transitedAt time.Time) (err error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"status":       status,
+		"transited_at": transitedAt,
+	})
+
+	resp, err := (esapi.IndexRequest{
+		Index:      statusIndex,
+		DocumentID: key,
+		Body:       bytes.NewReader(body),
+		Timeout:    time.Second * 5,
+	}).Do(ctx, ea.esCli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndexRequest, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.IsError() {
+		return errors.Errorf("IndexRequest return error code, resp: %+v", resp)
+	}
+	return
+}
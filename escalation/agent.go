@@ -0,0 +1,96 @@
+// escalation package define struct which is implement escalation agency interface using in each of domain
+// incident of check staying unhealthy long is re-alerted with mentions to escalation target, defined in config
+
+// in agent.go file, define struct type of escalation agent & initializer that are not method.
+// Also if exist, custom type or variable used in common in each of method will declared in this file.
+
+package escalation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// notifier is interface that deliver alert through specific transport
+// you can see implementation in notifier, slack, webhook package
+type notifier interface {
+	// Notify deliver alert and return notified time & text & error
+	Notify(alert *domain.Alert) (t time.Time, text string, err error)
+}
+
+// statusStore is agency that store & load escalation state of check beside status of check process to survive restart
+// you can see implementation in file, elasticsearch package
+type statusStore interface {
+	// LoadStatus load status & last transition time saved with key (empty status if not saved yet)
+	LoadStatus(ctx context.Context, key string) (status string, transitedAt time.Time, err error)
+
+	// StoreStatus store status & last transition time with key
+	StoreStatus(ctx context.Context, key, status string, transitedAt time.Time) (err error)
+}
+
+// escalation state of check stored in status store with key having prefix in front of check key
+const (
+	stateKeyPrefix = "escalation."
+	escalatedState = "ESCALATED" // represent that incident of check is escalated & not closed yet
+	closedState    = "CLOSED"    // represent that escalation of check is closed
+)
+
+// stateStoreTimeout is deadline of calling status store, so that unavailable store doesn't block check process
+const stateStoreTimeout = time.Second * 5
+
+// Rule is model about escalation rule, escalating incident of checks unhealthy for cycles or duration
+type Rule struct {
+	// Checks specifies keys of check covered by rule, domain (Ex, srvcheck) or "*" covers every check in it
+	Checks []string
+
+	// AfterCycles specifies number of check cycles staying unhealthy until escalation (0 if not used)
+	AfterCycles int
+
+	// AfterDuration specifies duration staying unhealthy until escalation (0 if not used)
+	AfterDuration time.Duration
+
+	// Mentions specifies slack user group or user ID mentioned in escalated alert (Ex, S01ABCDEF, U01ABCDEF)
+	Mentions []string
+}
+
+// escalationRule is struct having escalation rule & target delivering escalated alert in addition to primary notifier
+type escalationRule struct {
+	Rule
+	target notifier
+}
+
+// escalationAgent escalate incident of check staying unhealthy according to escalation rule covering that check
+type escalationAgent struct {
+	// primary is notifier delivering every escalated alert (Ex, notifier used in usecase)
+	primary notifier
+
+	// rules is slice of escalation rule, first rule covering check is applied to that check
+	rules []escalationRule
+
+	// store is used for keeping escalation state across restart, so that incident isn't escalated again after restart
+	store statusStore
+
+	// escalated is map having if incident of check is escalated & not closed yet with check key as key
+	// state of check is loaded from status store at first access of that check
+	escalated map[string]bool
+
+	// now return current time, replaced with fake clock in test
+	now func() time.Time
+
+	// mutex help to prevent race condition when usecases escalate incident concurrently
+	mutex sync.Mutex
+}
+
+// NewAgent return new initialized instance of escalationAgent pointer type with primary notifier & status store
+func NewAgent(primary notifier, store statusStore) *escalationAgent {
+	return &escalationAgent{
+		primary:   primary,
+		rules:     []escalationRule{},
+		store:     store,
+		escalated: map[string]bool{},
+		now:       time.Now,
+	}
+}
@@ -0,0 +1,147 @@
+// agent_escalate.go file define method of escalationAgent about adding rule, escalating & closing incident
+// implement escalation agency interface defined in each of domain
+
+package escalation
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// AddRule add escalation rule after validating it, escalated alert is also delivered to target if it is not nil
+// (Ex, slack agent of second channel, webhook agent)
+func (ea *escalationAgent) AddRule(rule Rule, target notifier) error {
+	if len(rule.Checks) == 0 {
+		return errors.New("at least one check is required")
+	}
+	if rule.AfterCycles <= 0 && rule.AfterDuration <= 0 {
+		return errors.New("at least one of after cycles or after duration is required")
+	}
+
+	ea.mutex.Lock()
+	defer ea.mutex.Unlock()
+
+	ea.rules = append(ea.rules, escalationRule{Rule: rule, target: target})
+	return nil
+}
+
+// Escalate deliver alert with mentions of rule if incident of check unhealthy since startedAt for cycles satisfy rule
+// incident is escalated only once until closed, and zero value is returned if alert is not escalated
+func (ea *escalationAgent) Escalate(check string, startedAt time.Time, cycles int, alert *domain.Alert) (t time.Time, text string, err error) {
+	ea.loadState(check)
+
+	ea.mutex.Lock()
+	rule, ok := ea.ruleFor(check)
+	if ea.escalated[check] || !ok || !rule.isSatisfied(startedAt, cycles, ea.now()) {
+		ea.mutex.Unlock()
+		return
+	}
+	ea.escalated[check] = true
+	ea.mutex.Unlock()
+	ea.storeState(check, escalatedState)
+
+	alert.Level = domain.AlertLevelEscalated
+	alert.Mentions = rule.Mentions
+	t, text, err = ea.primary.Notify(alert)
+
+	if rule.target != nil {
+		if _, _, tErr := rule.target.Notify(alert); tErr != nil {
+			log.Printf("failed to deliver escalated alert of %s to escalation target, err: %v", check, tErr)
+		}
+	}
+	return
+}
+
+// CloseEscalation close escalation of check with recovered or reset alert, which is delivered to escalation target too
+// primary notifier is not notified here, because recovered or reset alert is already delivered by usecase
+// escalation target is found with rule covering check now, as escalation may be done before restart
+func (ea *escalationAgent) CloseEscalation(check string, alert *domain.Alert) {
+	ea.loadState(check)
+
+	ea.mutex.Lock()
+	escalated := ea.escalated[check]
+	ea.escalated[check] = false
+	rule, ok := ea.ruleFor(check)
+	ea.mutex.Unlock()
+
+	if !escalated {
+		return
+	}
+	ea.storeState(check, closedState)
+
+	if !ok || rule.target == nil {
+		return
+	}
+	if _, _, err := rule.target.Notify(alert); err != nil {
+		log.Printf("failed to deliver closed escalation of %s to escalation target, err: %v", check, err)
+	}
+}
+
+// loadState load escalation state of check from status store if it is not loaded yet
+// state is loaded again at next access if failed to load, so that incident is not regarded as closed by temporary error
+func (ea *escalationAgent) loadState(check string) {
+	ea.mutex.Lock()
+	_, loaded := ea.escalated[check]
+	ea.mutex.Unlock()
+	if loaded {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stateStoreTimeout)
+	defer cancel()
+
+	state, _, err := ea.store.LoadStatus(ctx, stateKeyPrefix+check)
+	if err != nil {
+		log.Printf("failed to load escalation state of %s, err: %v", check, err)
+		return
+	}
+
+	ea.mutex.Lock()
+	if _, loaded := ea.escalated[check]; !loaded {
+		ea.escalated[check] = state == escalatedState
+	}
+	ea.mutex.Unlock()
+}
+
+// storeState store escalation state of check in status store with transition time
+func (ea *escalationAgent) storeState(check, state string) {
+	ctx, cancel := context.WithTimeout(context.Background(), stateStoreTimeout)
+	defer cancel()
+
+	if err := ea.store.StoreStatus(ctx, stateKeyPrefix+check, state, domain.Now()); err != nil {
+		log.Printf("failed to store escalation state of %s, err: %v", check, err)
+	}
+}
+
+// ruleFor return first escalation rule covering check, must be called with mutex locked
+func (ea *escalationAgent) ruleFor(check string) (escalationRule, bool) {
+	for _, rule := range ea.rules {
+		if rule.coversCheck(check) {
+			return rule, true
+		}
+	}
+	return escalationRule{}, false
+}
+
+// coversCheck return if escalation rule covers check, matched with check key, domain or "*"
+func (r Rule) coversCheck(check string) bool {
+	for _, c := range r.Checks {
+		if c == "*" || c == check || strings.HasPrefix(check, c+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isSatisfied return if incident unhealthy since startedAt for cycles reaches after cycles or after duration of rule at now
+func (r Rule) isSatisfied(startedAt time.Time, cycles int, now time.Time) bool {
+	if r.AfterCycles > 0 && cycles >= r.AfterCycles {
+		return true
+	}
+	return r.AfterDuration > 0 && !startedAt.IsZero() && now.Sub(startedAt) >= r.AfterDuration
+}
@@ -0,0 +1,188 @@
+package escalation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fakeStatusStore is statusStore keeping status in memory, shared by agents to simulate restart
+type fakeStatusStore struct {
+	mutex    sync.Mutex
+	statuses map[string]string
+}
+
+func (s *fakeStatusStore) LoadStatus(_ context.Context, key string) (string, time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.statuses[key], time.Time{}, nil
+}
+
+func (s *fakeStatusStore) StoreStatus(_ context.Context, key, status string, _ time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.statuses[key] = status
+	return nil
+}
+
+// countingNotifier is notifier counting delivered alerts
+type countingNotifier struct {
+	alerts []*domain.Alert
+}
+
+func (n *countingNotifier) Notify(alert *domain.Alert) (time.Time, string, error) {
+	n.alerts = append(n.alerts, alert)
+	return time.Now(), alert.Text, nil
+}
+
+// fakeClock return time moved forward with advance method
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestEscalate(t *testing.T) {
+	const check = "srvcheck.consul"
+
+	// step is action on escalation agent after clock is advanced, expected if alert is escalated in that step
+	type step struct {
+		after     time.Duration
+		action    string // escalate, close, restart
+		cycles    int
+		escalated bool
+	}
+
+	for _, tc := range []struct {
+		name  string
+		rule  Rule
+		steps []step
+	}{
+		{
+			name: "escalate once after cycles",
+			rule: Rule{Checks: []string{check}, AfterCycles: 3},
+			steps: []step{
+				{action: "escalate", cycles: 1},
+				{action: "escalate", cycles: 2},
+				{action: "escalate", cycles: 3, escalated: true},
+				{action: "escalate", cycles: 4},
+				{action: "escalate", cycles: 10},
+			},
+		}, {
+			name: "escalate once after duration",
+			rule: Rule{Checks: []string{"srvcheck"}, AfterDuration: time.Minute * 30},
+			steps: []step{
+				{action: "escalate", cycles: 1},
+				{after: time.Minute * 29, action: "escalate", cycles: 2},
+				{after: time.Minute, action: "escalate", cycles: 3, escalated: true},
+				{after: time.Hour, action: "escalate", cycles: 4},
+			},
+		}, {
+			name: "escalate again after incident is closed",
+			rule: Rule{Checks: []string{"*"}, AfterCycles: 2},
+			steps: []step{
+				{action: "escalate", cycles: 2, escalated: true},
+				{action: "escalate", cycles: 3},
+				{action: "close"},
+				{action: "escalate", cycles: 1},
+				{action: "escalate", cycles: 2, escalated: true},
+			},
+		}, {
+			name: "escalated state restored after restart",
+			rule: Rule{Checks: []string{check}, AfterCycles: 2},
+			steps: []step{
+				{action: "escalate", cycles: 2, escalated: true},
+				{action: "restart"},
+				{action: "escalate", cycles: 3},
+				{action: "close"},
+				{action: "restart"},
+				{action: "escalate", cycles: 2, escalated: true},
+			},
+		}, {
+			name: "check not covered by rule is not escalated",
+			rule: Rule{Checks: []string{"syscheck"}, AfterCycles: 1},
+			steps: []step{
+				{action: "escalate", cycles: 1},
+				{action: "escalate", cycles: 100},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &fakeStatusStore{statuses: map[string]string{}}
+			primary, target := &countingNotifier{}, &countingNotifier{}
+			clock := &fakeClock{t: time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)}
+			startedAt := clock.now()
+
+			newAgent := func() *escalationAgent {
+				agent := NewAgent(primary, store)
+				agent.now = clock.now
+				if err := agent.AddRule(tc.rule, target); err != nil {
+					t.Fatalf("rule should be added, err: %v", err)
+				}
+				return agent
+			}
+			agent := newAgent()
+
+			for i, s := range tc.steps {
+				clock.advance(s.after)
+				switch s.action {
+				case "restart":
+					agent = newAgent()
+					continue
+				case "close":
+					agent.CloseEscalation(check, &domain.Alert{Level: domain.AlertLevelRecovered})
+					startedAt = clock.now()
+					continue
+				}
+
+				before := len(primary.alerts)
+				_, text, _ := agent.Escalate(check, startedAt, s.cycles, &domain.Alert{Level: domain.AlertLevelUnhealthy, Text: "still unhealthy"})
+				if escalated := len(primary.alerts) > before; escalated != s.escalated {
+					t.Fatalf("step %d: if incident is escalated should be %t, got: %t", i, s.escalated, escalated)
+				}
+				if !s.escalated {
+					if text != "" {
+						t.Errorf("step %d: alert not escalated should return empty text, got: %q", i, text)
+					}
+					continue
+				}
+				alert := primary.alerts[len(primary.alerts)-1]
+				if alert.Level != domain.AlertLevelEscalated {
+					t.Errorf("step %d: escalated alert should have escalated level, got: %s", i, alert.Level)
+				}
+			}
+		})
+	}
+}
+
+func TestEscalateDeliversToTargetWithMentions(t *testing.T) {
+	store := &fakeStatusStore{statuses: map[string]string{}}
+	primary, target := &countingNotifier{}, &countingNotifier{}
+	agent := NewAgent(primary, store)
+	_ = agent.AddRule(Rule{Checks: []string{"syscheck"}, AfterCycles: 1, Mentions: []string{"S01ABCDEF"}}, target)
+
+	_, _, _ = agent.Escalate("syscheck.cpu", time.Now(), 1, &domain.Alert{Level: domain.AlertLevelUnhealthy})
+	if len(primary.alerts) != 1 || len(target.alerts) != 1 {
+		t.Fatalf("escalated alert should be delivered to primary notifier & target, got: %d, %d", len(primary.alerts), len(target.alerts))
+	}
+	if mentions := primary.alerts[0].Mentions; len(mentions) != 1 || mentions[0] != "S01ABCDEF" {
+		t.Errorf("escalated alert should have mentions of rule, got: %v", mentions)
+	}
+
+	agent.CloseEscalation("syscheck.cpu", &domain.Alert{Level: domain.AlertLevelRecovered})
+	if len(primary.alerts) != 1 || len(target.alerts) != 2 {
+		t.Errorf("closed escalation should be delivered only to target, got: %d, %d", len(primary.alerts), len(target.alerts))
+	}
+	if store.statuses[stateKeyPrefix+"syscheck.cpu"] != closedState {
+		t.Errorf("closed state should be stored in status store, got: %s", store.statuses[stateKeyPrefix+"syscheck.cpu"])
+	}
+
+	agent.CloseEscalation("syscheck.cpu", &domain.Alert{Level: domain.AlertLevelRecovered})
+	if len(target.alerts) != 2 {
+		t.Errorf("escalation not open should not be closed again, got: %d alerts in target", len(target.alerts))
+	}
+}
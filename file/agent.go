@@ -0,0 +1,61 @@
+// file package define struct which is implement various interface about local file agency using in each of domain
+// there are kind of file agency function such as store or load status of check process, alert queue, history spool, maintenance window, etc ...
+
+// in agent.go file, define struct type of file agent & initializer that are not method.
+// Also if exist, custom type or variable used in common in each of method will declared in this file.
+
+package file
+
+import (
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileAgent agent various command about local file(store or load status, etc ...) as implementation
+type fileAgent struct {
+	// statusFile is path of json file storing status of check process
+	statusFile string
+
+	// alertQueueFile is path of json file storing alert queue waiting retry
+	alertQueueFile string
+
+	// historySpoolFile is path of JSON-lines file spooling history documents failed to index in elasticsearch
+	historySpoolFile string
+
+	// historySpoolMaxSize is max size of history spool file, the oldest lines are dropped when exceeding that size
+	historySpoolMaxSize int64
+
+	// maintenanceWindowFile is path of json file storing maintenance windows added in admin API
+	maintenanceWindowFile string
+
+	// mutex help to prevent race condition when read & write file
+	mutex sync.Mutex
+}
+
+// NewAgent return new initialized instance of fileAgent pointer type with path of status, alert queue, history spool
+// & maintenance window file, and max size of history spool file
+func NewAgent(statusFile, alertQueueFile, historySpoolFile, maintenanceWindowFile string, historySpoolMaxSize int64) *fileAgent {
+	return &fileAgent{
+		statusFile:            statusFile,
+		alertQueueFile:        alertQueueFile,
+		historySpoolFile:      historySpoolFile,
+		historySpoolMaxSize:   historySpoolMaxSize,
+		maintenanceWindowFile: maintenanceWindowFile,
+	}
+}
+
+// writeFile write b to temporary file & replace file with that, so that file is not broken even if process is killed
+func writeFile(file string, b []byte) (err error) {
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directory of file")
+	}
+
+	tmp := file + ".tmp"
+	if err = ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write temporary file")
+	}
+	return errors.Wrap(os.Rename(tmp, file), "failed to replace file with temporary file")
+}
@@ -0,0 +1,39 @@
+// agent_queue.go file define method of fileAgent about queue of alert waiting retry
+// implement agency interface about alert queue store defined in notifier package
+
+package file
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+)
+
+// LoadAlertQueue read alert queue file & unmarshal it into queue (queue is not changed if file doesn't exist)
+func (fa *fileAgent) LoadAlertQueue(queue interface{}) (err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	b, err := ioutil.ReadFile(fa.alertQueueFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to read alert queue file")
+	}
+
+	return errors.Wrap(json.Unmarshal(b, queue), "failed to unmarshal alert queue file")
+}
+
+// StoreAlertQueue marshal queue & overwrite alert queue file with that
+func (fa *fileAgent) StoreAlertQueue(queue interface{}) (err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	b, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal alert queue")
+	}
+
+	return errors.Wrap(writeFile(fa.alertQueueFile, b), "failed to write alert queue file")
+}
@@ -0,0 +1,157 @@
+// agent_spool.go file define method of fileAgent about spool of history documents failed to index
+// implement agency interface about history spool defined in elasticsearch package
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/pkg/errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// maxSpoolLineSize is max size of line in history spool file, longer line is dropped instead of spooled or loaded
+const maxSpoolLineSize = 1024 * 1024
+
+// AppendHistorySpool append lines at the end of history spool file, creating file if not exist
+// line longer than max line size is dropped with log, and the oldest lines are dropped with log if spool file
+// exceeds max size, so that spool doesn't grow forever & keeps the latest documents
+func (fa *fileAgent) AppendHistorySpool(lines [][]byte) (err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	if err = os.MkdirAll(filepath.Dir(fa.historySpoolFile), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directory of history spool file")
+	}
+
+	buf := &bytes.Buffer{}
+	var spooled [][]byte
+	for _, line := range lines {
+		if len(line) > maxSpoolLineSize {
+			log.Printf("dropped line not spooled as it is longer than %d bytes", maxSpoolLineSize)
+			continue
+		}
+		spooled = append(spooled, line)
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	var size int64
+	if info, err := os.Stat(fa.historySpoolFile); err == nil {
+		size = info.Size()
+	} else if !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to get size of history spool file")
+	}
+	if size+int64(buf.Len()) > fa.historySpoolMaxSize {
+		return fa.evictHistorySpool(spooled)
+	}
+
+	f, err := os.OpenFile(fa.historySpoolFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to open history spool file")
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err = f.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "failed to write history spool file")
+	}
+	return errors.Wrap(f.Sync(), "failed to sync history spool file")
+}
+
+// evictHistorySpool overwrite history spool file with lines in it & lines to append, dropping the oldest lines
+// until it doesn't exceed max size, must be called with mutex locked
+func (fa *fileAgent) evictHistorySpool(lines [][]byte) error {
+	existing, err := fa.readHistorySpool()
+	if err != nil {
+		return err
+	}
+	lines = append(existing, lines...)
+
+	var size int64
+	for _, line := range lines {
+		size += int64(len(line) + 1)
+	}
+	var dropped int
+	for ; len(lines) > 0 && size > fa.historySpoolMaxSize; dropped++ {
+		size -= int64(len(lines[0]) + 1)
+		lines = lines[1:]
+	}
+	log.Printf("dropped %d oldest lines of history spool as it exceeds max size %d bytes", dropped, fa.historySpoolMaxSize)
+
+	buf := &bytes.Buffer{}
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return errors.Wrap(writeFile(fa.historySpoolFile, buf.Bytes()), "failed to write history spool file")
+}
+
+// LoadHistorySpool read lines of history spool file in order of appended (empty if file doesn't exist)
+// line longer than max line size is skipped with log, so that one broken line doesn't block loading spool forever
+func (fa *fileAgent) LoadHistorySpool() (lines [][]byte, err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	return fa.readHistorySpool()
+}
+
+// readHistorySpool read lines of history spool file in order of appended, must be called with mutex locked
+func (fa *fileAgent) readHistorySpool() (lines [][]byte, err error) {
+	f, err := os.Open(fa.historySpoolFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to open history spool file")
+	}
+	defer func() { _ = f.Close() }()
+
+	reader := bufio.NewReader(f)
+	line, oversized := []byte{}, false
+	for {
+		fragment, isPrefix, err := reader.ReadLine()
+		if err == io.EOF {
+			return lines, nil
+		} else if err != nil {
+			return lines, errors.Wrap(err, "failed to read history spool file")
+		}
+
+		if !oversized {
+			line = append(line, fragment...)
+			oversized = len(line) > maxSpoolLineSize
+		}
+		if isPrefix {
+			continue
+		}
+
+		switch {
+		case oversized:
+			log.Printf("skipped line of history spool file as it is longer than %d bytes", maxSpoolLineSize)
+		case len(line) != 0:
+			lines = append(lines, line)
+		}
+		line, oversized = []byte{}, false
+	}
+}
+
+// StoreHistorySpool overwrite history spool file with lines, file is removed if lines is empty
+func (fa *fileAgent) StoreHistorySpool(lines [][]byte) (err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	if len(lines) == 0 {
+		if err = os.Remove(fa.historySpoolFile); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove history spool file")
+		}
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return errors.Wrap(writeFile(fa.historySpoolFile, buf.Bytes()), "failed to write history spool file")
+}
@@ -0,0 +1,60 @@
+package file
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppendHistorySpool(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		appends [][]string // lines of each append call in order
+		maxSize int64
+		want    string
+	}{
+		{
+			name:    "lines under max size are kept in order",
+			appends: [][]string{{"line-1", "line-2"}, {"line-3"}},
+			maxSize: 1024,
+			want:    "line-1,line-2,line-3",
+		}, {
+			name:    "oldest lines are dropped when exceeding max size",
+			appends: [][]string{{"line-1", "line-2"}, {"line-3", "line-4"}},
+			maxSize: 7 * 3,
+			want:    "line-2,line-3,line-4",
+		}, {
+			name:    "oldest lines in one append are dropped when exceeding max size",
+			appends: [][]string{{"line-1", "line-2", "line-3"}},
+			maxSize: 7 * 2,
+			want:    "line-2,line-3",
+		}, {
+			name:    "line longer than max line size is dropped",
+			appends: [][]string{{"line-1", strings.Repeat("x", maxSpoolLineSize+1), "line-2"}},
+			maxSize: maxSpoolLineSize * 2,
+			want:    "line-1,line-2",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			agent := NewAgent("", "", filepath.Join(t.TempDir(), "history_spool.jsonl"), "", tc.maxSize)
+			for _, lines := range tc.appends {
+				var b [][]byte
+				for _, line := range lines {
+					b = append(b, []byte(line))
+				}
+				if err := agent.AppendHistorySpool(b); err != nil {
+					t.Fatalf("lines should be appended, err: %v", err)
+				}
+			}
+
+			lines, err := agent.LoadHistorySpool()
+			if err != nil {
+				t.Fatalf("history spool should be loaded, err: %v", err)
+			}
+			if got := string(bytes.Join(lines, []byte(","))); got != tc.want {
+				t.Errorf("lines in history spool should be %s, got: %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// agent_status.go file define method of fileAgent about status of check process
+// implement agency interface about status store defined in each of domain
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// statusRecord is struct having status & last transition time of check process, saved in status file as json
+type statusRecord struct {
+	Status      string    `json:"status"`
+	TransitedAt time.Time `json:"transited_at"`
+}
+
+// LoadStatus load status & last transition time of check saved with key in status file (empty status if not saved yet)
+func (fa *fileAgent) LoadStatus(ctx context.Context, key string) (status string, transitedAt time.Time, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	records, err := fa.readStatusRecords()
+	if err != nil {
+		return
+	}
+
+	record := records[key]
+	return record.Status, record.TransitedAt, nil
+}
+
+// StoreStatus store status & last transition time of check with key in status file
+// status file is replaced with temporary file after writing, so that file is not broken even if process is killed
+func (fa *fileAgent) StoreStatus(ctx context.Context, key, status string, transitedAt time.Time) (err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	records, err := fa.readStatusRecords()
+	if err != nil {
+		return
+	}
+	records[key] = statusRecord{Status: status, TransitedAt: transitedAt}
+
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal status records")
+	}
+
+	return errors.Wrap(writeFile(fa.statusFile, b), "failed to write status file")
+}
+
+// readStatusRecords read status file & return status records per key (empty map if file doesn't exist)
+func (fa *fileAgent) readStatusRecords() (records map[string]statusRecord, err error) {
+	records = map[string]statusRecord{}
+
+	b, err := ioutil.ReadFile(fa.statusFile)
+	if os.IsNotExist(err) {
+		return records, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read status file")
+	}
+
+	if err = json.Unmarshal(b, &records); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal status file")
+	}
+	return
+}
@@ -0,0 +1,39 @@
+// agent_window.go file define method of fileAgent about maintenance windows added in admin API
+// implement agency interface about window store defined in maintenance package
+
+package file
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+)
+
+// LoadMaintenanceWindows read maintenance window file & unmarshal it into windows (not changed if file doesn't exist)
+func (fa *fileAgent) LoadMaintenanceWindows(windows interface{}) (err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	b, err := ioutil.ReadFile(fa.maintenanceWindowFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to read maintenance window file")
+	}
+
+	return errors.Wrap(json.Unmarshal(b, windows), "failed to unmarshal maintenance window file")
+}
+
+// StoreMaintenanceWindows marshal windows & overwrite maintenance window file with that
+func (fa *fileAgent) StoreMaintenanceWindows(windows interface{}) (err error) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+
+	b, err := json.MarshalIndent(windows, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal maintenance windows")
+	}
+
+	return errors.Wrap(writeFile(fa.maintenanceWindowFile, b), "failed to write maintenance window file")
+}
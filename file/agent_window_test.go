@@ -0,0 +1,81 @@
+package file
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+func TestMaintenanceWindowPersistence(t *testing.T) {
+	start := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+	window := domain.MaintenanceWindow{
+		ID:         "window-1",
+		Checks:     []string{"srvcheck.consul"},
+		Containers: []string{"DSM_SMS_service-auth"},
+		StartAt:    start,
+		EndAt:      start.Add(time.Hour),
+		Reason:     "planned work on the swarm",
+		CreatedBy:  "admin",
+	}
+
+	for _, tc := range []struct {
+		name   string
+		stored []domain.MaintenanceWindow // nil if nothing is stored before loading
+		want   []domain.MaintenanceWindow
+	}{
+		{
+			name: "file not exist yet",
+			want: []domain.MaintenanceWindow{},
+		}, {
+			name:   "stored window",
+			stored: []domain.MaintenanceWindow{window},
+			want:   []domain.MaintenanceWindow{window},
+		}, {
+			name:   "every window removed",
+			stored: []domain.MaintenanceWindow{},
+			want:   []domain.MaintenanceWindow{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "data", "maintenance.json")
+			if tc.stored != nil {
+				if err := NewAgent("", "", "", file, 0).StoreMaintenanceWindows(tc.stored); err != nil {
+					t.Fatalf("maintenance windows should be stored, err: %v", err)
+				}
+			}
+
+			// load with new agent, same as health checker is restarted
+			loaded := []domain.MaintenanceWindow{}
+			if err := NewAgent("", "", "", file, 0).LoadMaintenanceWindows(&loaded); err != nil {
+				t.Fatalf("maintenance windows should be loaded, err: %v", err)
+			}
+
+			if len(loaded) != len(tc.want) {
+				t.Fatalf("loaded windows should be %v, got: %v", tc.want, loaded)
+			}
+			for i := range loaded {
+				got, want := loaded[i], tc.want[i]
+				if got.ID != want.ID || got.Reason != want.Reason || got.CreatedBy != want.CreatedBy ||
+					!got.StartAt.Equal(want.StartAt) || !got.EndAt.Equal(want.EndAt) ||
+					len(got.Checks) != len(want.Checks) || len(got.Containers) != len(want.Containers) {
+					t.Errorf("loaded window should be %+v, got: %+v", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMaintenanceWindowsFromBrokenFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "maintenance.json")
+	if err := ioutil.WriteFile(file, []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := []domain.MaintenanceWindow{}
+	if err := NewAgent("", "", "", file, 0).LoadMaintenanceWindows(&loaded); err == nil {
+		t.Error("loading broken maintenance window file should return error")
+	}
+}
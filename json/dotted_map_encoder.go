@@ -0,0 +1,76 @@
+// Create package in v.1.0.0
+// json package is a collection of convenience objects, specific interface implementations related to json.
+// dotted_map_encoder.go is file that declare stateless encoder called DottedMapEncoder to convert dotted map to nested json.
+// Different things of dottedMapEncoder with json.Marshal() is that separate json value step with dot in map key.
+// it doesn't keep any state between calls, so it's safe for concurrent use.
+
+package json
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+)
+
+// dottedMapEncoder is struct to encode map having dotted key (Ex, alarm.text) to nested json (Ex, {"alarm":{"text":...}})
+// it doesn't have any field, so one instance can be shared by every repository & used concurrently
+type dottedMapEncoder struct{}
+
+// DottedMapEncoder return new pointer instance of dottedMapEncoder struct
+func DottedMapEncoder() *dottedMapEncoder {
+	return &dottedMapEncoder{}
+}
+
+// object is type of json object created by splitting dotted key, to distinguish it from map value set in dotted map
+type object map[string]interface{}
+
+// Encode method encode dotted map to nested json, separating json value step with dot in map key.
+// conflict policy: if key is used as both value & object (Ex, "alarm" and "alarm.text"), or has empty step (Ex, "alarm..text"),
+// error is returned without any encoded bytes. value in dotted map is kept as it is, even if it is map type.
+func (dme *dottedMapEncoder) Encode(m map[string]interface{}) (b []byte, err error) {
+	// sort keys so that the same conflict is always reported in the same way
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := object{}
+	for _, k := range keys {
+		steps := strings.Split(k, ".")
+		for _, step := range steps {
+			if step == "" {
+				err = errors.Errorf("invalid key for json format, key has empty step, key: %s", k)
+				return
+			}
+		}
+
+		parent := root
+		for i, step := range steps[:len(steps)-1] {
+			v, ok := parent[step]
+			if !ok {
+				v = object{}
+				parent[step] = v
+			}
+			if parent, ok = v.(object); !ok {
+				err = errors.Errorf("invalid key for json format, key conflicts with value of another key, key: %s, another: %s",
+					k, strings.Join(steps[:i+1], "."))
+				return
+			}
+		}
+
+		last := steps[len(steps)-1]
+		if _, ok := parent[last]; ok {
+			err = errors.Errorf("invalid key for json format, key conflicts with object of another key, key: %s", k)
+			return
+		}
+		parent[last] = m[k]
+	}
+
+	if b, err = json.Marshal(root); err != nil {
+		err = errors.Wrap(err, "failed to json.Marshal nested map")
+		b = nil
+	}
+	return
+}
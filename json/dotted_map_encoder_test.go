@@ -0,0 +1,193 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// flatten convert nested map decoded from json to dotted map, reverse of dottedMapEncoder.Encode
+func flatten(prefix string, nested map[string]interface{}, dotted map[string]interface{}) {
+	for k, v := range nested {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		if m, ok := v.(map[string]interface{}); ok && len(m) != 0 {
+			flatten(k, m, dotted)
+			continue
+		}
+		dotted[k] = v
+	}
+}
+
+// normalize marshal & unmarshal dotted map, so that value types are the same with decoded one (Ex, int -> float64)
+func normalize(t *testing.T, m map[string]interface{}) map[string]interface{} {
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to marshal dotted map, err: %v", err)
+	}
+	normalized := map[string]interface{}{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		t.Fatalf("failed to unmarshal dotted map, err: %v", err)
+	}
+	return normalized
+}
+
+func TestDottedMapEncoderEncodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		dotted map[string]interface{}
+		nested string
+	}{{
+		name:   "empty map",
+		dotted: map[string]interface{}{},
+		nested: `{}`,
+	}, {
+		name:   "keys without dot",
+		dotted: map[string]interface{}{"uuid": "abc", "alerted": true, "usage": 1.5, "error": nil},
+		nested: `{"alerted":true,"error":null,"usage":1.5,"uuid":"abc"}`,
+	}, {
+		name:   "keys sharing prefix",
+		dotted: map[string]interface{}{"alarm.text": "weak", "alarm.time": "2021-01-01T00:00:00Z", "uuid": "abc"},
+		nested: `{"alarm":{"text":"weak","time":"2021-01-01T00:00:00Z"},"uuid":"abc"}`,
+	}, {
+		name:   "deeply nested keys",
+		dotted: map[string]interface{}{"a.b.c": 1, "a.b.d": 2, "a.e": 3, "f": 4},
+		nested: `{"a":{"b":{"c":1,"d":2},"e":3},"f":4}`,
+	}, {
+		name:   "key having prefix without dot",
+		dotted: map[string]interface{}{"alarm": "text", "alarm_time.zone": "KST"},
+		nested: `{"alarm":"text","alarm_time":{"zone":"KST"}}`,
+	}}
+
+	encoder := DottedMapEncoder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := encoder.Encode(tt.dotted)
+			if err != nil {
+				t.Fatalf("unexpected error, err: %v", err)
+			}
+			if string(b) != tt.nested {
+				t.Errorf("unexpected nested json, expected: %s, actual: %s", tt.nested, string(b))
+			}
+
+			nested := map[string]interface{}{}
+			if err := json.Unmarshal(b, &nested); err != nil {
+				t.Fatalf("failed to unmarshal nested json, err: %v", err)
+			}
+			dotted := map[string]interface{}{}
+			flatten("", nested, dotted)
+			if expected := normalize(t, tt.dotted); !reflect.DeepEqual(expected, dotted) {
+				t.Errorf("dotted map is not restored, expected: %v, actual: %v", expected, dotted)
+			}
+		})
+	}
+}
+
+func TestDottedMapEncoderEncodeKeepsNumberPrecision(t *testing.T) {
+	b, err := DottedMapEncoder().Encode(map[string]interface{}{
+		"remaining_capacity":       uint64(1<<62 + 1),
+		"remaining_capacity_human": "4.00EB",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error, err: %v", err)
+	}
+
+	expected := `{"remaining_capacity":4611686018427387905,"remaining_capacity_human":"4.00EB"}`
+	if string(b) != expected {
+		t.Errorf("unexpected nested json, expected: %s, actual: %s", expected, string(b))
+	}
+}
+
+func TestDottedMapEncoderEncodeKeepsMapValue(t *testing.T) {
+	b, err := DottedMapEncoder().Encode(map[string]interface{}{
+		"settings":                 map[string]interface{}{"mapping.ignore_malformed": true},
+		"settings_number.of.shard": 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error, err: %v", err)
+	}
+
+	expected := `{"settings":{"mapping.ignore_malformed":true},"settings_number":{"of":{"shard":1}}}`
+	if string(b) != expected {
+		t.Errorf("unexpected nested json, expected: %s, actual: %s", expected, string(b))
+	}
+}
+
+func TestDottedMapEncoderEncodeConflict(t *testing.T) {
+	tests := []struct {
+		name   string
+		dotted map[string]interface{}
+	}{{
+		name:   "value key is prefix of another key",
+		dotted: map[string]interface{}{"alarm": "text", "alarm.time": "2021-01-01T00:00:00Z"},
+	}, {
+		name:   "value key is deep prefix of another key",
+		dotted: map[string]interface{}{"a.b": 1, "a.b.c": 2},
+	}, {
+		name:   "map value key is prefix of another key",
+		dotted: map[string]interface{}{"alarm": map[string]interface{}{"text": "weak"}, "alarm.time": "2021-01-01T00:00:00Z"},
+	}, {
+		name:   "empty step in middle of key",
+		dotted: map[string]interface{}{"alarm..text": "weak"},
+	}, {
+		name:   "key ending with dot",
+		dotted: map[string]interface{}{"alarm.": "weak"},
+	}, {
+		name:   "empty key",
+		dotted: map[string]interface{}{"": "weak"},
+	}, {
+		name:   "unsupported value",
+		dotted: map[string]interface{}{"alarm.func": func() {}},
+	}}
+
+	encoder := DottedMapEncoder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := encoder.Encode(tt.dotted)
+			if err == nil {
+				t.Fatalf("expected error, but encoded: %s", string(b))
+			}
+			if b != nil {
+				t.Errorf("expected no encoded bytes with error, actual: %s", string(b))
+			}
+		})
+	}
+
+	// encoder must be usable after error, since it doesn't keep any state
+	if _, err := encoder.Encode(map[string]interface{}{"alarm.text": "weak"}); err != nil {
+		t.Errorf("unexpected error after conflict, err: %v", err)
+	}
+}
+
+func TestDottedMapEncoderEncodeConcurrently(t *testing.T) {
+	encoder := DottedMapEncoder()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			dotted := map[string]interface{}{"uuid": fmt.Sprintf("uuid-%d", i), "alarm.text": fmt.Sprintf("text-%d", i)}
+			if i%2 == 0 {
+				dotted["uuid.conflict"] = true
+			}
+
+			b, err := encoder.Encode(dotted)
+			if i%2 == 0 {
+				if err == nil {
+					t.Errorf("expected error, but encoded: %s", string(b))
+				}
+				return
+			}
+
+			expected := fmt.Sprintf(`{"alarm":{"text":"text-%d"},"uuid":"uuid-%d"}`, i, i)
+			if err != nil || string(b) != expected {
+				t.Errorf("unexpected result, expected: %s, actual: %s, err: %v", expected, string(b), err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
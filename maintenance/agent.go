@@ -0,0 +1,53 @@
+// maintenance package define struct which is implement maintenance agency interface using in each of domain
+// maintenance window suppress remediation & alarm of check or container in that window, defined in config or admin API
+
+// in agent.go file, define struct type of maintenance agent & initializer that are not method.
+// Also if exist, custom type or variable used in common in each of method will declared in this file.
+
+package maintenance
+
+import (
+	"log"
+	"sync"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// ConfigCreator is creator of maintenance window defined in config file, which is not stored in window store
+// because it is added again from config file whenever health checker starts
+const ConfigCreator = domain.MaintenanceConfigCreator
+
+// windowStore is agency that keep maintenance windows added in admin API to survive restart
+// you can see implementation in file package
+type windowStore interface {
+	// LoadMaintenanceWindows unmarshal stored maintenance windows into windows (not changed if not stored yet)
+	LoadMaintenanceWindows(windows interface{}) (err error)
+
+	// StoreMaintenanceWindows overwrite stored maintenance windows with windows
+	StoreMaintenanceWindows(windows interface{}) (err error)
+}
+
+// maintenanceAgent manage maintenance windows & check if check or container is in active window
+type maintenanceAgent struct {
+	// store is used for keeping maintenance windows added in admin API across restart
+	store windowStore
+
+	// windows is slice of maintenance window not ended yet
+	windows []domain.MaintenanceWindow
+
+	// mutex help to prevent race condition when access windows field
+	mutex sync.RWMutex
+}
+
+// NewAgent return new initialized instance of maintenanceAgent pointer type with windows loaded from window store
+func NewAgent(store windowStore) *maintenanceAgent {
+	agent := &maintenanceAgent{
+		store:   store,
+		windows: []domain.MaintenanceWindow{},
+	}
+
+	if err := store.LoadMaintenanceWindows(&agent.windows); err != nil {
+		log.Printf("failed to load maintenance windows from window store, err: %v", err)
+	}
+	return agent
+}
@@ -0,0 +1,159 @@
+// agent_window.go file define method of maintenanceAgent about adding, removing & looking up maintenance window
+// implement maintenance agency interface defined in each of domain
+
+package maintenance
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// AddWindow add maintenance window after validating it & return added window having generated id
+// window not defined in config file is stored in window store, so that it survives restart
+func (ma *maintenanceAgent) AddWindow(window domain.MaintenanceWindow) (domain.MaintenanceWindow, error) {
+	if len(window.Checks) == 0 && len(window.Containers) == 0 {
+		return domain.MaintenanceWindow{}, errors.New("at least one of checks or containers is required")
+	}
+	if window.StartAt.IsZero() || window.EndAt.IsZero() {
+		return domain.MaintenanceWindow{}, errors.New("start_at and end_at are required")
+	}
+	if !window.EndAt.After(window.StartAt) {
+		return domain.MaintenanceWindow{}, errors.New("end_at must be after start_at")
+	}
+
+	window.ID = uuid.New().String()
+	if window.Checks == nil {
+		window.Checks = []string{}
+	}
+	if window.Containers == nil {
+		window.Containers = []string{}
+	}
+
+	ma.mutex.Lock()
+	defer ma.mutex.Unlock()
+
+	ma.windows = append(ma.windows, window)
+	if window.CreatedBy != ConfigCreator {
+		ma.storeWindows()
+	}
+	return window, nil
+}
+
+// RemoveWindow remove maintenance window having id & return if window was exist
+func (ma *maintenanceAgent) RemoveWindow(id string) bool {
+	ma.mutex.Lock()
+	defer ma.mutex.Unlock()
+
+	for i, window := range ma.windows {
+		if window.ID == id {
+			ma.windows = append(ma.windows[:i], ma.windows[i+1:]...)
+			ma.storeWindows()
+			return true
+		}
+	}
+	return false
+}
+
+// GetWindows return every maintenance window not ended yet, removing ended windows
+func (ma *maintenanceAgent) GetWindows() []domain.MaintenanceWindow {
+	ma.mutex.Lock()
+	defer ma.mutex.Unlock()
+
+	now := time.Now()
+	windows := ma.windows[:0]
+	for _, window := range ma.windows {
+		if window.EndAt.After(now) {
+			windows = append(windows, window)
+		}
+	}
+	if len(windows) < len(ma.windows) {
+		ma.windows = windows
+		ma.storeWindows()
+	}
+	return append([]domain.MaintenanceWindow{}, windows...)
+}
+
+// GetActiveWindow return id of maintenance window active now & covering check or container (empty if not exist)
+// container name can be name of docker container (Ex, /DSM_SMS_service-auth.1.xxx) or docker service name
+func (ma *maintenanceAgent) GetActiveWindow(check, container string) (id string) {
+	ma.mutex.RLock()
+	defer ma.mutex.RUnlock()
+
+	service := serviceName(container)
+	now := time.Now()
+	for _, window := range ma.windows {
+		if !isActiveAt(window, now) {
+			continue
+		}
+		if check != "" && coversCheck(window, check) {
+			return window.ID
+		}
+		if service != "" && coversContainer(window, service) {
+			return window.ID
+		}
+	}
+	return ""
+}
+
+// GetActiveContainers return names of container covered by maintenance window active now
+func (ma *maintenanceAgent) GetActiveContainers() (containers []string) {
+	ma.mutex.RLock()
+	defer ma.mutex.RUnlock()
+
+	now := time.Now()
+	for _, window := range ma.windows {
+		if isActiveAt(window, now) {
+			containers = append(containers, window.Containers...)
+		}
+	}
+	return
+}
+
+// storeWindows store maintenance windows not defined in config file in window store, called while mutex is locked
+func (ma *maintenanceAgent) storeWindows() {
+	windows := []domain.MaintenanceWindow{}
+	for _, window := range ma.windows {
+		if window.CreatedBy != ConfigCreator {
+			windows = append(windows, window)
+		}
+	}
+
+	if err := ma.store.StoreMaintenanceWindows(windows); err != nil {
+		log.Printf("failed to store maintenance windows in window store, err: %v", err)
+	}
+}
+
+// isActiveAt return if maintenance window is active at t
+func isActiveAt(w domain.MaintenanceWindow, t time.Time) bool {
+	return !t.Before(w.StartAt) && t.Before(w.EndAt)
+}
+
+// coversCheck return if maintenance window covers check, matched with check key, domain or "*"
+func coversCheck(w domain.MaintenanceWindow, check string) bool {
+	for _, c := range w.Checks {
+		if c == "*" || c == check || strings.HasPrefix(check, c+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// coversContainer return if maintenance window covers container having docker service name
+func coversContainer(w domain.MaintenanceWindow, service string) bool {
+	for _, c := range w.Containers {
+		if c == service {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceName return docker service name from docker container name (Ex, /DSM_SMS_consul.1.xxx -> DSM_SMS_consul)
+func serviceName(container string) string {
+	return strings.Split(strings.TrimPrefix(container, "/"), ".")[0]
+}
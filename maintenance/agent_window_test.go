@@ -0,0 +1,182 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fakeWindowStore is windowStore keeping stored maintenance windows in memory
+type fakeWindowStore struct {
+	stored []domain.MaintenanceWindow
+}
+
+func (s *fakeWindowStore) LoadMaintenanceWindows(windows interface{}) error {
+	*windows.(*[]domain.MaintenanceWindow) = append([]domain.MaintenanceWindow{}, s.stored...)
+	return nil
+}
+
+func (s *fakeWindowStore) StoreMaintenanceWindows(windows interface{}) error {
+	s.stored = append([]domain.MaintenanceWindow{}, windows.([]domain.MaintenanceWindow)...)
+	return nil
+}
+
+func TestGetActiveWindow(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name      string
+		window    domain.MaintenanceWindow
+		check     string
+		container string
+		active    bool
+	}{
+		{
+			name:   "check key covered",
+			window: domain.MaintenanceWindow{Checks: []string{"srvcheck.consul"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+			check:  "srvcheck.consul",
+			active: true,
+		}, {
+			name:   "domain covers every check in it",
+			window: domain.MaintenanceWindow{Checks: []string{"syscheck"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+			check:  "syscheck.cpu",
+			active: true,
+		}, {
+			name:   "wildcard covers every check",
+			window: domain.MaintenanceWindow{Checks: []string{"*"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+			check:  "srvcheck.swarmpit",
+			active: true,
+		}, {
+			name:   "domain prefix without dot not covered",
+			window: domain.MaintenanceWindow{Checks: []string{"srvcheck.consul"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+			check:  "srvcheck.consulx",
+			active: false,
+		}, {
+			name:      "container name matched with docker service name",
+			window:    domain.MaintenanceWindow{Containers: []string{"DSM_SMS_service-auth"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+			container: "/DSM_SMS_service-auth.1.abcdef",
+			active:    true,
+		}, {
+			name:      "another container not covered",
+			window:    domain.MaintenanceWindow{Containers: []string{"DSM_SMS_service-auth"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+			container: "/DSM_SMS_service-club.1.abcdef",
+			active:    false,
+		}, {
+			name:   "window not started yet",
+			window: domain.MaintenanceWindow{Checks: []string{"*"}, StartAt: now.Add(time.Minute), EndAt: now.Add(time.Hour)},
+			check:  "syscheck.cpu",
+			active: false,
+		}, {
+			name:   "window already ended",
+			window: domain.MaintenanceWindow{Checks: []string{"*"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(-time.Minute)},
+			check:  "syscheck.cpu",
+			active: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.window.ID, tc.window.CreatedBy = "window-1", "admin"
+			agent := NewAgent(&fakeWindowStore{stored: []domain.MaintenanceWindow{tc.window}})
+
+			id := agent.GetActiveWindow(tc.check, tc.container)
+			if tc.active && id != "window-1" {
+				t.Errorf("maintenance window should be active, got id: %q", id)
+			}
+			if !tc.active && id != "" {
+				t.Errorf("maintenance window should not be active, got id: %q", id)
+			}
+		})
+	}
+}
+
+func TestGetWindowsRemovesEndedWindows(t *testing.T) {
+	now := time.Now()
+	store := &fakeWindowStore{stored: []domain.MaintenanceWindow{
+		{ID: "ended", Checks: []string{"*"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(-time.Minute), CreatedBy: "admin"},
+		{ID: "active", Checks: []string{"*"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour), CreatedBy: "admin"},
+		{ID: "upcoming", Checks: []string{"*"}, StartAt: now.Add(time.Hour), EndAt: now.Add(time.Hour * 2), CreatedBy: "admin"},
+	}}
+	agent := NewAgent(store)
+
+	windows := agent.GetWindows()
+	if len(windows) != 2 || windows[0].ID != "active" || windows[1].ID != "upcoming" {
+		t.Fatalf("windows not ended yet should be returned, got: %v", windows)
+	}
+	if len(store.stored) != 2 {
+		t.Errorf("ended window should be removed from window store, got: %v", store.stored)
+	}
+}
+
+func TestAddWindow(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name   string
+		window domain.MaintenanceWindow
+		valid  bool
+		stored bool
+	}{
+		{
+			name:   "window created in admin API is stored",
+			window: domain.MaintenanceWindow{Checks: []string{"syscheck"}, StartAt: now, EndAt: now.Add(time.Hour), CreatedBy: "admin"},
+			valid:  true,
+			stored: true,
+		}, {
+			name:   "window defined in config file is not stored",
+			window: domain.MaintenanceWindow{Checks: []string{"syscheck"}, StartAt: now, EndAt: now.Add(time.Hour), CreatedBy: ConfigCreator},
+			valid:  true,
+			stored: false,
+		}, {
+			name:   "window without check & container",
+			window: domain.MaintenanceWindow{StartAt: now, EndAt: now.Add(time.Hour), CreatedBy: "admin"},
+		}, {
+			name:   "window without end",
+			window: domain.MaintenanceWindow{Checks: []string{"syscheck"}, StartAt: now, CreatedBy: "admin"},
+		}, {
+			name:   "window ending before start",
+			window: domain.MaintenanceWindow{Checks: []string{"syscheck"}, StartAt: now, EndAt: now.Add(-time.Hour), CreatedBy: "admin"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &fakeWindowStore{}
+			agent := NewAgent(store)
+
+			added, err := agent.AddWindow(tc.window)
+			if tc.valid != (err == nil) {
+				t.Fatalf("validity of window should be %t, err: %v", tc.valid, err)
+			}
+			if !tc.valid {
+				return
+			}
+
+			if added.ID == "" {
+				t.Error("added window should have generated id")
+			}
+			if got := agent.GetActiveWindow("syscheck.disk", ""); got != added.ID {
+				t.Errorf("added window should be active, got id: %q", got)
+			}
+			if stored := len(store.stored) == 1; stored != tc.stored {
+				t.Errorf("if window is stored in window store should be %t, got: %v", tc.stored, store.stored)
+			}
+		})
+	}
+}
+
+func TestRemoveWindow(t *testing.T) {
+	now := time.Now()
+	store := &fakeWindowStore{stored: []domain.MaintenanceWindow{
+		{ID: "window-1", Checks: []string{"*"}, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour), CreatedBy: "admin"},
+	}}
+	agent := NewAgent(store)
+
+	if agent.RemoveWindow("not-exist") {
+		t.Error("removing window not exist should return false")
+	}
+	if !agent.RemoveWindow("window-1") {
+		t.Fatal("removing window exist should return true")
+	}
+	if id := agent.GetActiveWindow("syscheck.cpu", ""); id != "" {
+		t.Errorf("removed window should not be active, got id: %q", id)
+	}
+	if len(store.stored) != 0 {
+		t.Errorf("removed window should be removed from window store, got: %v", store.stored)
+	}
+}
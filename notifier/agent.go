@@ -0,0 +1,176 @@
+// notifier package define struct which is implement notifier agency interface using in each of domain
+// multi agent deliver alert to several notifiers (slack, webhook, etc ...) configured at once
+// dedup agent suppress same failure alert repeated in every check cycle & remind it periodically instead
+// retry agent keep alert failed in decorated notifiers in durable queue & retry it with backoff, updating history when it is sent later
+
+// in agent.go file, define struct type of notifier agent & initializer that are not method.
+// Also if exist, custom type or variable used in common in each of method will declared in this file.
+
+package notifier
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// notifier is interface that deliver alert through specific transport
+// you can see implementation in slack, webhook package
+type notifier interface {
+	// Notify deliver alert and return notified time & text & error
+	Notify(alert *domain.Alert) (t time.Time, text string, err error)
+}
+
+// multiAgent agent several notifiers to deliver same alert to all of them
+type multiAgent struct {
+	// notifiers is slice of notifier to deliver alert, first one is treated as primary notifier
+	notifiers []notifier
+}
+
+// NewMultiAgent return new initialized instance of multiAgent pointer type with notifiers
+func NewMultiAgent(notifiers ...notifier) *multiAgent {
+	return &multiAgent{
+		notifiers: notifiers,
+	}
+}
+
+// Append add notifier to deliver alert, must be called before multiAgent is used in check process
+func (ma *multiAgent) Append(n notifier) {
+	ma.notifiers = append(ma.notifiers, n)
+}
+
+// dedupAgent decorate notifier to deduplicate failure alert per check & reason, sharing state across all usecases
+type dedupAgent struct {
+	// next is notifier to deliver alert which is not suppressed
+	next notifier
+
+	// cooldown is duration after last occurrence until same failure alert is regarded as new one
+	cooldown time.Duration
+
+	// reminderInterval is interval to send reminder of failure alert which keeps occurring
+	reminderInterval time.Duration
+
+	// occurrences is map having occurrence state of failure alert with key made of check & reason
+	occurrences map[string]*alertOccurrence
+
+	// now return current time, replaced with fake clock in test
+	now func() time.Time
+
+	// mutex help to prevent race condition when usecases notify alert concurrently
+	mutex sync.Mutex
+}
+
+// alertOccurrence is struct having state about how many times same failure alert occurred since when
+type alertOccurrence struct {
+	count    int
+	since    time.Time
+	lastSeen time.Time
+	lastSent time.Time
+}
+
+// NewDedupAgent return new initialized instance of dedupAgent pointer type with notifier to decorate & durations
+func NewDedupAgent(next notifier, cooldown, reminderInterval time.Duration) *dedupAgent {
+	return &dedupAgent{
+		next:             next,
+		cooldown:         cooldown,
+		reminderInterval: reminderInterval,
+		occurrences:      map[string]*alertOccurrence{},
+		now:              time.Now,
+	}
+}
+
+// alertQueueStore is agency that store & load queue of alert waiting retry to survive restart
+// you can see implementation in file package
+type alertQueueStore interface {
+	// LoadAlertQueue load saved alert queue into queue received from parameter
+	LoadAlertQueue(queue interface{}) (err error)
+
+	// StoreAlertQueue store alert queue received from parameter
+	StoreAlertQueue(queue interface{}) (err error)
+}
+
+// alarmResultUpdater is interface that update alarm result of history after alert is sent by retry
+// you can see implementation in repository of each domain
+type alarmResultUpdater interface {
+	// UpdateAlarmResult update alarm result of history having uuid, replacing alarm error with err (cleared if nil)
+	// alarm time & text are kept if text is empty
+	UpdateAlarmResult(uuid string, t time.Time, text string, err error) error
+}
+
+// retryAgent keep alert failed to deliver in notifiers decorated by it & retry it with backoff, sharing one alert queue store
+type retryAgent struct {
+	// targets is map having notifier decorated by retry agent with target name as key, used for retrying queued alert
+	targets map[string]notifier
+
+	// primary is name of target decorated first, history alarm time & text are updated only when alert is sent to it by retry
+	primary string
+
+	// queueStore is used for keeping queue of alert waiting retry across restart
+	queueStore alertQueueStore
+
+	// updaters is map having updater of history alarm result with check type as key
+	updaters map[string]alarmResultUpdater
+
+	// queue is slice of alert waiting retry
+	queue []*queuedAlert
+
+	// mutex help to prevent race condition when access queue & updaters field
+	mutex sync.Mutex
+}
+
+// retryTarget is notifier decorated by retry agent, pushing alert failed to deliver to queue of retry agent with its name
+type retryTarget struct {
+	agent *retryAgent
+	name  string
+	next  notifier
+}
+
+// queuedAlert is struct having alert waiting retry & retry state of that alert
+type queuedAlert struct {
+	Alert     *domain.Alert `json:"alert"`
+	Target    string        `json:"target"`
+	Attempts  int           `json:"attempts"`
+	NextRetry time.Time     `json:"next_retry"`
+
+	// ValueTypes specifies type of measurement & threshold value which is changed by json (Ex, bytesize -> float64)
+	ValueTypes map[string]string `json:"value_types,omitempty"`
+}
+
+// legacyRetryTarget is target name of alert queued before target is recorded, which was alert failed to send in slack
+const legacyRetryTarget = "slack"
+
+// NewRetryAgent return new initialized instance of retryAgent pointer type with queue store
+// alert queue saved before restart is loaded in this function, and retried after Run method is called
+func NewRetryAgent(qs alertQueueStore) *retryAgent {
+	agent := &retryAgent{
+		targets:    map[string]notifier{},
+		queueStore: qs,
+		updaters:   map[string]alarmResultUpdater{},
+	}
+
+	if err := qs.LoadAlertQueue(&agent.queue); err != nil {
+		log.Printf("failed to load alert queue, err: %v", err)
+	}
+	for _, queued := range agent.queue {
+		if queued.Target == "" {
+			queued.Target = legacyRetryTarget
+		}
+		restoreValueTypes(queued.Alert, queued.ValueTypes)
+	}
+	return agent
+}
+
+// Decorate return notifier delivering alert to next, pushing alert to queue with name if failed to deliver
+// name should be unique & same across restart, since alert in queue is retried with notifier decorated with that name
+func (ra *retryAgent) Decorate(name string, next notifier) notifier {
+	ra.mutex.Lock()
+	defer ra.mutex.Unlock()
+
+	if ra.primary == "" {
+		ra.primary = name
+	}
+	ra.targets[name] = next
+	return &retryTarget{agent: ra, name: name, next: next}
+}
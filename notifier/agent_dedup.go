@@ -0,0 +1,69 @@
+// agent_dedup.go file define method of dedupAgent about deduplicating failure alert before delivering
+// implement notifier agency interface defined in each of domain
+
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// Notify deliver alert to decorated notifier if it is new failure or not failure, and suppress repeated failure alert
+// repeated failure alert is delivered as reminder with count & first occurred time every reminder interval
+// suppressed alert return zero time & empty text & nil error, so history doesn't record it as alerted
+func (da *dedupAgent) Notify(alert *domain.Alert) (t time.Time, text string, err error) {
+	switch alert.Level {
+	case domain.AlertLevelError, domain.AlertLevelRecoveryError, domain.AlertLevelUnhealthy:
+		break
+	case domain.AlertLevelRecovered, domain.AlertLevelReset:
+		da.forget(alert.Domain, alert.Type)
+		return da.next.Notify(alert)
+	default:
+		return da.next.Notify(alert)
+	}
+
+	now := da.now()
+	// text is not used in key, as it can contain value changing in every check process (Ex, container id, error)
+	key := strings.Join([]string{alert.Domain, alert.Type, string(alert.Level), alert.Reason}, "|")
+
+	da.mutex.Lock()
+	o, ok := da.occurrences[key]
+	if !ok || now.Sub(o.lastSeen) >= da.cooldown {
+		o = &alertOccurrence{since: now}
+		da.occurrences[key] = o
+	}
+	o.count++
+	o.lastSeen = now
+
+	if o.count > 1 && now.Sub(o.lastSent) < da.reminderInterval {
+		da.mutex.Unlock()
+		return
+	}
+	remind := o.count > 1
+	o.lastSent = now
+	count, since := o.count, o.since
+	da.mutex.Unlock()
+
+	if remind {
+		reminder := *alert
+		reminder.Text = fmt.Sprintf("%s (still failing, %d times since %s)", alert.Text, count, since.In(domain.Location()).Format("15:04"))
+		alert = &reminder
+	}
+	return da.next.Notify(alert)
+}
+
+// forget remove occurrence state of every failure alert about check, so next failure is alerted immediately
+func (da *dedupAgent) forget(_domain, _type string) {
+	da.mutex.Lock()
+	defer da.mutex.Unlock()
+
+	prefix := _domain + "|" + _type + "|"
+	for key := range da.occurrences {
+		if strings.HasPrefix(key, prefix) {
+			delete(da.occurrences, key)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package notifier
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// recordingNotifier is notifier recording delivered alerts, failing while err is set
+type recordingNotifier struct {
+	mutex  sync.Mutex
+	alerts []*domain.Alert
+	err    error
+}
+
+func (n *recordingNotifier) Notify(alert *domain.Alert) (time.Time, string, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.alerts = append(n.alerts, alert)
+	if n.err != nil {
+		return time.Time{}, "", n.err
+	}
+	return time.Now(), alert.Text, nil
+}
+
+func (n *recordingNotifier) delivered() []*domain.Alert {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]*domain.Alert{}, n.alerts...)
+}
+
+// fakeClock return time moved forward with advance method
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestDedupAgentNotify(t *testing.T) {
+	const (
+		cooldown         = time.Minute * 10
+		reminderInterval = time.Minute * 30
+	)
+
+	// step is alert sent after clock is advanced, and expected result of that alert
+	type step struct {
+		after     time.Duration
+		level     domain.AlertLevel
+		reason    string
+		delivered bool
+		reminder  bool
+	}
+
+	for _, tc := range []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "same alert inside cooldown is suppressed",
+			steps: []step{
+				{level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelError, reason: "container_remove_failed"},
+				{after: time.Minute * 9, level: domain.AlertLevelError, reason: "container_remove_failed"},
+			},
+		}, {
+			name: "same alert outside cooldown is delivered as new alert",
+			steps: []step{
+				{level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+				{after: cooldown, level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+				{after: cooldown + time.Minute, level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+			},
+		}, {
+			name: "alert keeping occurring is reminded after reminder interval",
+			steps: []step{
+				{level: domain.AlertLevelUnhealthy, reason: "deteriorated", delivered: true},
+				{after: time.Minute * 5, level: domain.AlertLevelUnhealthy, reason: "deteriorated"},
+				{after: time.Minute * 5, level: domain.AlertLevelUnhealthy, reason: "deteriorated"},
+				{after: time.Minute * 5, level: domain.AlertLevelUnhealthy, reason: "deteriorated"},
+				{after: time.Minute * 5, level: domain.AlertLevelUnhealthy, reason: "deteriorated"},
+				{after: time.Minute * 5, level: domain.AlertLevelUnhealthy, reason: "deteriorated"},
+				{after: time.Minute * 5, level: domain.AlertLevelUnhealthy, reason: "deteriorated", delivered: true, reminder: true},
+				{after: time.Minute * 5, level: domain.AlertLevelUnhealthy, reason: "deteriorated"},
+			},
+		}, {
+			name: "level change passes through immediately",
+			steps: []step{
+				{level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelUnhealthy, reason: "container_remove_failed", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelRecoveryError, reason: "container_remove_failed", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelUnhealthy, reason: "container_remove_failed"},
+			},
+		}, {
+			name: "reason change passes through immediately",
+			steps: []step{
+				{level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelError, reason: "cluster_health_unavailable", delivered: true},
+			},
+		}, {
+			name: "recovered alert is delivered & next failure is alerted immediately",
+			steps: []step{
+				{level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelRecovered, reason: "recovered", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelError, reason: "container_remove_failed", delivered: true},
+			},
+		}, {
+			name: "not failure alert is never suppressed",
+			steps: []step{
+				{level: domain.AlertLevelWeakDetected, reason: "container_remove_started", delivered: true},
+				{after: time.Minute, level: domain.AlertLevelWeakDetected, reason: "container_remove_started", delivered: true},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			next := &recordingNotifier{}
+			clock := &fakeClock{t: time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)}
+			agent := NewDedupAgent(next, cooldown, reminderInterval)
+			agent.now = clock.now
+
+			for i, s := range tc.steps {
+				clock.advance(s.after)
+				before := len(next.delivered())
+				alert := &domain.Alert{Domain: "syscheck", Type: "CPUCheck", Level: s.level, Reason: s.reason, Text: "cpu check alert"}
+				_, text, err := agent.Notify(alert)
+				if err != nil {
+					t.Fatalf("step %d: notify should succeed, err: %v", i, err)
+				}
+
+				delivered := next.delivered()
+				if got := len(delivered) > before; got != s.delivered {
+					t.Fatalf("step %d: if alert is delivered should be %t, got: %t", i, s.delivered, got)
+				}
+				if !s.delivered {
+					if text != "" {
+						t.Errorf("step %d: suppressed alert should return empty text, got: %q", i, text)
+					}
+					continue
+				}
+				if got := strings.Contains(delivered[len(delivered)-1].Text, "still failing"); got != s.reminder {
+					t.Errorf("step %d: if alert is reminder should be %t, got text: %q", i, s.reminder, delivered[len(delivered)-1].Text)
+				}
+			}
+		})
+	}
+}
+
+func TestDedupAgentReminderHasCount(t *testing.T) {
+	domain.SetLocation(time.UTC)
+	next := &recordingNotifier{}
+	clock := &fakeClock{t: time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)}
+	agent := NewDedupAgent(next, time.Minute*10, time.Minute*30)
+	agent.now = clock.now
+
+	for i := 0; i < 7; i++ {
+		_, _, _ = agent.Notify(&domain.Alert{Domain: "syscheck", Type: "CPUCheck", Level: domain.AlertLevelError, Reason: "r", Text: "cpu check error"})
+		clock.advance(time.Minute * 5)
+	}
+
+	delivered := next.delivered()
+	if len(delivered) != 2 {
+		t.Fatalf("first alert & one reminder should be delivered, got: %d", len(delivered))
+	}
+	if want := "cpu check error (still failing, 7 times since 09:00)"; delivered[1].Text != want {
+		t.Errorf("reminder text should be %q, got: %q", want, delivered[1].Text)
+	}
+}
@@ -0,0 +1,35 @@
+// agent_notify.go file define method of multiAgent about delivering alert to every notifier
+// implement notifier agency interface defined in each of domain
+
+package notifier
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// Notify deliver alert to every notifier and return time & text of first succeeded notifier & joined error
+// alert is delivered to remaining notifiers even if some of them failed, so one broken notifier doesn't block others
+func (ma *multiAgent) Notify(alert *domain.Alert) (t time.Time, text string, err error) {
+	var errs []string
+	var notified bool
+
+	for _, n := range ma.notifiers {
+		_t, _text, _err := n.Notify(alert)
+		if _err != nil {
+			errs = append(errs, _err.Error())
+			continue
+		}
+		if !notified {
+			t, text, notified = _t, _text, true
+		}
+	}
+
+	if len(errs) != 0 {
+		err = errors.Errorf("failed to notify alert to %d notifiers, err: %s", len(errs), strings.Join(errs, " | "))
+	}
+	return
+}
@@ -0,0 +1,247 @@
+// agent_retry.go file define method of retryAgent about retrying alert failed to deliver
+// implement notifier agency interface defined in each of domain
+
+package notifier
+
+import (
+	"context"
+	"github.com/inhies/go-bytesize"
+	"github.com/pkg/errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// constants used for deciding when & how long alert in queue is retried
+const (
+	retryCycle      = time.Second * 10 // cycle to look for alert which should be retried now
+	retryMinBackoff = time.Second * 30 // backoff after first failure, doubled at every failure
+	retryMaxBackoff = time.Minute * 30 // max backoff between retries
+	retryMaxAge     = time.Hour * 24   // alert older than this is dropped from queue as it is meaningless
+)
+
+// Notify deliver alert to decorated notifier, and push alert to queue of retry agent if failed to deliver
+func (rt *retryTarget) Notify(alert *domain.Alert) (t time.Time, text string, err error) {
+	if t, text, err = rt.next.Notify(alert); err == nil {
+		return
+	}
+
+	queued := &queuedAlert{
+		Alert:      alert,
+		Target:     rt.name,
+		Attempts:   1,
+		NextRetry:  time.Now().Add(retryMinBackoff),
+		ValueTypes: valueTypes(alert),
+	}
+
+	rt.agent.mutex.Lock()
+	rt.agent.queue = append(rt.agent.queue, queued)
+	rt.agent.storeQueue()
+	rt.agent.mutex.Unlock()
+
+	err = errors.Wrap(err, "alert is queued for retry")
+	return
+}
+
+// RegisterUpdater register updater of history alarm result about check, called when alert of that check is sent by retry
+func (ra *retryAgent) RegisterUpdater(_domain, _type string, updater alarmResultUpdater) {
+	ra.mutex.Lock()
+	defer ra.mutex.Unlock()
+
+	ra.updaters[_domain+"."+_type] = updater
+}
+
+// Run start to retry alert in queue every retry cycle until ctx is done
+func (ra *retryAgent) Run(ctx context.Context, wg *sync.WaitGroup) {
+	// count retrying goroutine in wait group, so that shutdown waits for retry in progress
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(retryCycle)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ra.retry()
+			}
+		}
+	}()
+}
+
+// retry deliver alert in queue whose retry time has come, and update history alarm result if succeed
+func (ra *retryAgent) retry() {
+	now := time.Now()
+	var due []*queuedAlert
+
+	ra.mutex.Lock()
+	for _, queued := range ra.queue {
+		if !queued.NextRetry.After(now) {
+			due = append(due, queued)
+		}
+	}
+	ra.mutex.Unlock()
+
+	for _, queued := range due {
+		alert := queued.Alert
+		if now.Sub(alert.Timestamp) > retryMaxAge {
+			log.Printf("dropped alert from queue as it is too old, uuid: %s, text: %s", alert.UUID, alert.Text)
+			ra.remove(queued)
+			continue
+		}
+
+		ra.mutex.Lock()
+		target, ok := ra.targets[queued.Target]
+		ra.mutex.Unlock()
+		if !ok {
+			log.Printf("dropped alert from queue as target is not configured, target: %s, uuid: %s", queued.Target, alert.UUID)
+			ra.remove(queued)
+			continue
+		}
+
+		t, text, err := target.Notify(alert)
+		if err != nil {
+			ra.mutex.Lock()
+			queued.Attempts++
+			queued.NextRetry = time.Now().Add(retryBackoff(queued.Attempts))
+			ra.storeQueue()
+			ra.mutex.Unlock()
+			continue
+		}
+		ra.remove(queued)
+
+		// alarm error of history is updated whichever target alert is sent to, and cleared if no target is left in queue
+		// alarm time & text of history is the result of primary target, same as multi agent returns
+		ra.mutex.Lock()
+		updater, ok := ra.updaters[alert.Domain+"."+alert.Type]
+		pending := ra.pendingTargets(alert.UUID)
+		isPrimary := queued.Target == ra.primary
+		ra.mutex.Unlock()
+		if !ok {
+			continue
+		}
+
+		var alarmErr error
+		if len(pending) != 0 {
+			alarmErr = errors.Errorf("alert is still queued for retry to %d notifiers, targets: %v", len(pending), pending)
+		}
+		if !isPrimary {
+			t, text = time.Time{}, ""
+		}
+		if err := updater.UpdateAlarmResult(alert.UUID, t, text, alarmErr); err != nil {
+			log.Printf("failed to update alarm result of history, uuid: %s, err: %v", alert.UUID, err)
+		}
+	}
+}
+
+// remove delete alert from queue & store changed queue
+func (ra *retryAgent) remove(target *queuedAlert) {
+	ra.mutex.Lock()
+	defer ra.mutex.Unlock()
+
+	for i, queued := range ra.queue {
+		if queued == target {
+			ra.queue = append(ra.queue[:i], ra.queue[i+1:]...)
+			break
+		}
+	}
+	ra.storeQueue()
+}
+
+// pendingTargets return name of targets having alert with uuid in queue, must be called with mutex locked
+func (ra *retryAgent) pendingTargets(uuid string) (targets []string) {
+	for _, queued := range ra.queue {
+		if queued.Alert != nil && queued.Alert.UUID == uuid {
+			targets = append(targets, queued.Target)
+		}
+	}
+	return
+}
+
+// storeQueue store queue in alert queue store, must be called with mutex locked
+func (ra *retryAgent) storeQueue() {
+	if err := ra.queueStore.StoreAlertQueue(ra.queue); err != nil {
+		log.Printf("failed to store alert queue, err: %v", err)
+	}
+}
+
+// retryBackoff return backoff before next retry, doubled from min backoff at every failure & limited to max backoff
+func retryBackoff(attempts int) time.Duration {
+	backoff := retryMinBackoff
+	for i := 1; i < attempts && backoff < retryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > retryMaxBackoff {
+		backoff = retryMaxBackoff
+	}
+	return backoff
+}
+
+// type name of measurement & threshold value recorded in queued alert, which is restored after loaded from queue store
+const (
+	bytesizeValueType = "bytesize"
+	durationValueType = "duration"
+	intValueType      = "int"
+	stringsValueType  = "strings"
+)
+
+// valueTypes return type name of measurement & threshold value which is not restored from json as it is
+// key of returned map is made of field & key of value (Ex, measurements.remaining_capacity)
+func valueTypes(alert *domain.Alert) (types map[string]string) {
+	types = map[string]string{}
+	for field, values := range map[string]map[string]interface{}{"measurements": alert.Measurements, "thresholds": alert.Thresholds} {
+		for key, value := range values {
+			switch value.(type) {
+			case bytesize.ByteSize:
+				types[field+"."+key] = bytesizeValueType
+			case time.Duration:
+				types[field+"."+key] = durationValueType
+			case int:
+				types[field+"."+key] = intValueType
+			case []string:
+				types[field+"."+key] = stringsValueType
+			}
+		}
+	}
+	return
+}
+
+// restoreValueTypes convert measurement & threshold value decoded from json into type recorded in types
+func restoreValueTypes(alert *domain.Alert, types map[string]string) {
+	if alert == nil {
+		return
+	}
+
+	for field, values := range map[string]map[string]interface{}{"measurements": alert.Measurements, "thresholds": alert.Thresholds} {
+		for key, value := range values {
+			f, isNumber := value.(float64)
+			switch types[field+"."+key] {
+			case bytesizeValueType:
+				if isNumber {
+					values[key] = bytesize.ByteSize(f)
+				}
+			case durationValueType:
+				if isNumber {
+					values[key] = time.Duration(f)
+				}
+			case intValueType:
+				if isNumber {
+					values[key] = int(f)
+				}
+			case stringsValueType:
+				if list, ok := value.([]interface{}); ok {
+					strs := make([]string, 0, len(list))
+					for _, v := range list {
+						s, _ := v.(string)
+						strs = append(strs, s)
+					}
+					values[key] = strs
+				}
+			}
+		}
+	}
+}
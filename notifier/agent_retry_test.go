@@ -0,0 +1,237 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inhies/go-bytesize"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// memoryQueueStore is alertQueueStore keeping alert queue as json in memory, same as file store
+type memoryQueueStore struct {
+	mutex sync.Mutex
+	b     []byte
+}
+
+func (s *memoryQueueStore) LoadAlertQueue(queue interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.b == nil {
+		return nil
+	}
+	return json.Unmarshal(s.b, queue)
+}
+
+func (s *memoryQueueStore) StoreAlertQueue(queue interface{}) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.b, err = json.Marshal(queue)
+	return
+}
+
+// alarmResult is arguments of UpdateAlarmResult method called in recordingUpdater
+type alarmResult struct {
+	uuid string
+	t    time.Time
+	text string
+	err  error
+}
+
+// recordingUpdater is alarmResultUpdater recording called alarm result
+type recordingUpdater struct {
+	results []alarmResult
+}
+
+func (u *recordingUpdater) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	u.results = append(u.results, alarmResult{uuid: uuid, t: t, text: text, err: err})
+	return nil
+}
+
+// makeDue make every alert in queue of retry agent due to retry now
+func makeDue(ra *retryAgent) {
+	ra.mutex.Lock()
+	defer ra.mutex.Unlock()
+	for _, queued := range ra.queue {
+		queued.NextRetry = time.Now().Add(-time.Second)
+	}
+}
+
+func newTestAlert(uuid string) *domain.Alert {
+	return &domain.Alert{Domain: "syscheck", Type: "CPUCheck", Level: domain.AlertLevelError, UUID: uuid, Text: "cpu check error", Timestamp: time.Now()}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int
+		backoff  time.Duration
+	}{
+		{attempts: 1, backoff: retryMinBackoff},
+		{attempts: 2, backoff: retryMinBackoff * 2},
+		{attempts: 3, backoff: retryMinBackoff * 4},
+		{attempts: 6, backoff: retryMinBackoff * 32},
+		{attempts: 7, backoff: retryMaxBackoff},
+		{attempts: 100, backoff: retryMaxBackoff},
+	} {
+		if backoff := retryBackoff(tc.attempts); backoff != tc.backoff {
+			t.Errorf("backoff after %d attempts should be %s, got: %s", tc.attempts, tc.backoff, backoff)
+		}
+	}
+}
+
+func TestRetryAgentBacksOffWhileTargetFails(t *testing.T) {
+	failing := &recordingNotifier{err: errors.New("slack is unavailable")}
+	agent := NewRetryAgent(&memoryQueueStore{})
+	target := agent.Decorate("slack", failing)
+
+	before := time.Now()
+	if _, _, err := target.Notify(newTestAlert("uuid-1")); err == nil {
+		t.Fatal("notify should return error when decorated notifier fails")
+	}
+	if len(agent.queue) != 1 || agent.queue[0].Attempts != 1 {
+		t.Fatalf("failed alert should be queued with one attempt, got: %v", agent.queue)
+	}
+	if delay := agent.queue[0].NextRetry.Sub(before); delay < retryMinBackoff || delay > retryMinBackoff+time.Second {
+		t.Errorf("first retry should be after min backoff, got: %s", delay)
+	}
+
+	for attempts := 2; attempts <= 8; attempts++ {
+		makeDue(agent)
+		before = time.Now()
+		agent.retry()
+
+		if len(failing.delivered()) != attempts {
+			t.Fatalf("alert should be tried %d times, got: %d", attempts, len(failing.delivered()))
+		}
+		queued := agent.queue[0]
+		if queued.Attempts != attempts {
+			t.Errorf("attempts of queued alert should be %d, got: %d", attempts, queued.Attempts)
+		}
+		want := retryBackoff(attempts)
+		if delay := queued.NextRetry.Sub(before); delay < want || delay > want+time.Second || delay > retryMaxBackoff+time.Second {
+			t.Errorf("retry after %d attempts should be delayed %s, got: %s", attempts, want, delay)
+		}
+	}
+
+	// alert not due yet is not tried
+	agent.retry()
+	if len(failing.delivered()) != 8 {
+		t.Errorf("alert not due yet should not be tried, got: %d attempts", len(failing.delivered()))
+	}
+}
+
+func TestRetryAgentDropsOldAlert(t *testing.T) {
+	failing := &recordingNotifier{err: errors.New("slack is unavailable")}
+	agent := NewRetryAgent(&memoryQueueStore{})
+	target := agent.Decorate("slack", failing)
+
+	alert := newTestAlert("uuid-1")
+	alert.Timestamp = time.Now().Add(-retryMaxAge - time.Minute)
+	_, _, _ = target.Notify(alert)
+
+	makeDue(agent)
+	agent.retry()
+	if len(failing.delivered()) != 1 {
+		t.Errorf("alert older than max age should not be retried, got: %d attempts", len(failing.delivered()))
+	}
+	if len(agent.queue) != 0 {
+		t.Errorf("alert older than max age should be dropped from queue, got: %v", agent.queue)
+	}
+}
+
+func TestRetryAgentUpdatesAlarmResult(t *testing.T) {
+	primary := &recordingNotifier{err: errors.New("slack is unavailable")}
+	secondary := &recordingNotifier{err: errors.New("webhook is unavailable")}
+	agent := NewRetryAgent(&memoryQueueStore{})
+	updater := &recordingUpdater{}
+	agent.RegisterUpdater("syscheck", "CPUCheck", updater)
+
+	multi := NewMultiAgent(agent.Decorate("slack", primary), agent.Decorate("webhook", secondary))
+	if _, _, err := multi.Notify(newTestAlert("uuid-1")); err == nil {
+		t.Fatal("notify should return error when every notifier fails")
+	}
+	if len(agent.queue) != 2 {
+		t.Fatalf("alert should be queued per failed notifier, got: %d", len(agent.queue))
+	}
+
+	// secondary notifier recovers first, alarm error is updated but alarm time & text are kept
+	secondary.mutex.Lock()
+	secondary.err = nil
+	secondary.mutex.Unlock()
+	makeDue(agent)
+	agent.retry()
+	if len(updater.results) != 1 {
+		t.Fatalf("alarm result should be updated when alert is sent by retry, got: %v", updater.results)
+	}
+	if r := updater.results[0]; r.uuid != "uuid-1" || r.text != "" || !r.t.IsZero() || r.err == nil {
+		t.Errorf("alarm error should be updated with pending primary notifier & alarm text kept, got: %+v", r)
+	}
+
+	// primary notifier recovers, alarm time & text are updated & alarm error is cleared
+	primary.mutex.Lock()
+	primary.err = nil
+	primary.mutex.Unlock()
+	makeDue(agent)
+	agent.retry()
+	if len(updater.results) != 2 {
+		t.Fatalf("alarm result should be updated when alert is sent by retry, got: %v", updater.results)
+	}
+	if r := updater.results[1]; r.text != "cpu check error" || r.t.IsZero() || r.err != nil {
+		t.Errorf("alarm result should be result of primary notifier without error, got: %+v", r)
+	}
+	if len(agent.queue) != 0 {
+		t.Errorf("sent alert should be removed from queue, got: %v", agent.queue)
+	}
+}
+
+func TestRetryAgentRestoresQueueAfterRestart(t *testing.T) {
+	store := &memoryQueueStore{}
+	alert := newTestAlert("uuid-1")
+	alert.Measurements = map[string]interface{}{
+		"remaining_capacity": bytesize.GB * 3,
+		"usage_core":         1.5,
+		"container":          "DSM_SMS_service-auth",
+	}
+	alert.Thresholds = map[string]interface{}{
+		"minimum_capacity": bytesize.GB,
+		"check_timeout":    time.Second * 30,
+		"maximum_shards":   1000,
+		"target_services":  []string{"auth", "club"},
+	}
+	want := map[string]map[string]interface{}{
+		"measurements": {
+			"remaining_capacity": bytesize.GB * 3,
+			"usage_core":         1.5,
+			"container":          "DSM_SMS_service-auth",
+		},
+		"thresholds": {
+			"minimum_capacity": bytesize.GB,
+			"check_timeout":    time.Second * 30,
+			"maximum_shards":   1000,
+			"target_services":  []string{"auth", "club"},
+		},
+	}
+
+	failing := &recordingNotifier{err: errors.New("slack is unavailable")}
+	_, _, _ = NewRetryAgent(store).Decorate("slack", failing).Notify(alert)
+
+	// load queue with new agent, same as health checker is restarted
+	restarted := NewRetryAgent(store)
+	if len(restarted.queue) != 1 || restarted.queue[0].Target != "slack" {
+		t.Fatalf("queued alert should be loaded from queue store, got: %v", restarted.queue)
+	}
+	restored := restarted.queue[0].Alert
+	got := map[string]map[string]interface{}{"measurements": restored.Measurements, "thresholds": restored.Thresholds}
+	for field, values := range want {
+		for key, value := range values {
+			if !reflect.DeepEqual(got[field][key], value) {
+				t.Errorf("%s.%s should be restored as %T(%v), got: %T(%v)", field, key, value, value, got[field][key], got[field][key])
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Create package in v.1.0.0
+// prometheus package define struct which is implement various interface about metric agency using in each of domain
+// collected metric is exposed with prometheus text exposition format through http handler (ServeHTTP method)
+
+// in agent.go file, define struct type of prometheus agent & initializer that are not method.
+// Also if exist, custom type or variable used in common in each of method will declared in this file.
+
+package prometheus
+
+import (
+	"sync"
+)
+
+// metric type used in TYPE line of prometheus text exposition format
+const (
+	gaugeType     = "gauge"     // represent metric value that can arbitrarily go up and down
+	counterType   = "counter"   // represent metric value that only increase (reset when process restarted)
+	histogramType = "histogram" // represent distribution of observed values counted in buckets
+)
+
+// histogramBuckets is upper bounds of histogram buckets, which cover duration of check process in seconds
+var histogramBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// metricHelps is help text of metrics collected in usecases, written in HELP line. metric not in this map has no HELP line
+var metricHelps = map[string]string{
+	"health_check_runs_total":                      "Number of check process runs per process level.",
+	"health_check_duration_seconds":                "Duration of check process runs in seconds.",
+	"health_check_status":                          "Current status of check process state machine, 1 in current status and 0 in another.",
+	"health_check_remediations_total":              "Number of remediations per action done in check process.",
+	"syscheck_cpu_total_usage_cores":               "Total cpu usage of runtime system in cores.",
+	"syscheck_cpu_docker_usage_cores":              "Cpu usage of docker containers in cores.",
+	"syscheck_memory_total_usage_bytes":            "Total memory usage of runtime system in bytes.",
+	"syscheck_memory_docker_usage_bytes":           "Memory usage of docker containers in bytes.",
+	"syscheck_disk_remaining_capacity_bytes":       "Remaining disk capacity of runtime system in bytes.",
+	"syscheck_disk_reclaimed_bytes_total":          "Disk capacity reclaimed by docker system prune in bytes.",
+	"srvcheck_elasticsearch_active_primary_shards": "Number of active primary shards in elasticsearch cluster.",
+	"srvcheck_elasticsearch_active_shards":         "Number of active shards in elasticsearch cluster.",
+	"srvcheck_elasticsearch_unassigned_shards":     "Number of unassigned shards in elasticsearch cluster.",
+	"srvcheck_elasticsearch_active_shards_percent": "Percent of active shards in elasticsearch cluster.",
+	"srvcheck_swarmpit_app_memory_usage_bytes":     "Memory usage of swarmpit app container in bytes.",
+	"srvcheck_consul_instances":                    "Number of instances registered in consul per service.",
+}
+
+// prometheusAgent is struct that collect gauge, counter metrics & expose them as prometheus text format
+type prometheusAgent struct {
+	// families is map binding metric name to metric family having type & values per label set
+	families map[string]*family
+
+	// mutex help to prevent race condition when set or read families field
+	mutex sync.RWMutex
+}
+
+// family is struct having metric type & sample values of one metric name
+type family struct {
+	// _type specifies metric type of this family (gauge, counter or histogram)
+	_type string
+
+	// samples is map binding encoded label set to sample having labels & value
+	samples map[string]*sample
+}
+
+// sample is struct having label set & value of one time series in family
+// in histogram, value is sum of observed values & buckets has count of observed values less than or equal to each bound
+type sample struct {
+	labels  string
+	value   float64
+	buckets []uint64
+	count   uint64
+}
+
+// NewAgent return new initialized instance of prometheusAgent pointer type
+func NewAgent() *prometheusAgent {
+	return &prometheusAgent{
+		families: map[string]*family{},
+		mutex:    sync.RWMutex{},
+	}
+}
@@ -0,0 +1,165 @@
+// Create file in v.1.0.0
+// agent_metric.go is file that define method of prometheusAgent that agent command about metric
+// For example in metric command, there are set gauge, add counter, observe histogram, expose metrics as http response, etc ...
+
+package prometheus
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SetGauge set gauge metric value having name & labels received from param
+func (pa *prometheusAgent) SetGauge(name string, labels map[string]string, value float64) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+
+	if s := pa.sampleOf(gaugeType, name, labels); s != nil {
+		s.value = value
+	}
+}
+
+// AddCounter add value to counter metric having name & labels received from param
+func (pa *prometheusAgent) AddCounter(name string, labels map[string]string, value float64) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+
+	if value < 0 {
+		log.Printf("counter metric cannot be decreased, name: %s, value: %f", name, value)
+		return
+	}
+
+	if s := pa.sampleOf(counterType, name, labels); s != nil {
+		s.value += value
+	}
+}
+
+// ObserveHistogram add value observed to histogram metric having name & labels received from param
+func (pa *prometheusAgent) ObserveHistogram(name string, labels map[string]string, value float64) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+
+	s := pa.sampleOf(histogramType, name, labels)
+	if s == nil {
+		return
+	}
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(histogramBuckets))
+	}
+	for i, bound := range histogramBuckets {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.value += value
+	s.count++
+}
+
+// ServeHTTP write all collected metrics as prometheus text exposition format, implement http.Handler
+func (pa *prometheusAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pa.mutex.RLock()
+	defer pa.mutex.RUnlock()
+
+	names := make([]string, 0, len(pa.families))
+	for name := range pa.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := pa.families[name]
+		if help, ok := metricHelps[name]; ok {
+			b.WriteString(fmt.Sprintf("# HELP %s %s\n", name, helpEscaper.Replace(help)))
+		}
+		b.WriteString(fmt.Sprintf("# TYPE %s %s\n", name, f._type))
+
+		keys := make([]string, 0, len(f.samples))
+		for k := range f.samples {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			s := f.samples[k]
+			if f._type != histogramType {
+				b.WriteString(name + s.labels + " " + formatValue(s.value) + "\n")
+				continue
+			}
+			for i, bound := range histogramBuckets {
+				b.WriteString(name + "_bucket" + withLabel(s.labels, "le", formatValue(bound)) + " " + strconv.FormatUint(s.buckets[i], 10) + "\n")
+			}
+			b.WriteString(name + "_bucket" + withLabel(s.labels, "le", "+Inf") + " " + strconv.FormatUint(s.count, 10) + "\n")
+			b.WriteString(name + "_sum" + s.labels + " " + formatValue(s.value) + "\n")
+			b.WriteString(name + "_count" + s.labels + " " + strconv.FormatUint(s.count, 10) + "\n")
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		log.Printf("failed to write metrics to response, err: %v", err)
+	}
+}
+
+// sampleOf return sample having name & labels, create if not exist. return nil if name is using in another type
+func (pa *prometheusAgent) sampleOf(_type, name string, labels map[string]string) *sample {
+	f, ok := pa.families[name]
+	if !ok {
+		f = &family{_type: _type, samples: map[string]*sample{}}
+		pa.families[name] = f
+	} else if f._type != _type {
+		log.Printf("metric name is already used as another type, name: %s, type: %s", name, f._type)
+		return nil
+	}
+
+	encoded := encodeLabels(labels)
+	s, ok := f.samples[encoded]
+	if !ok {
+		s = &sample{labels: encoded}
+		f.samples[encoded] = s
+	}
+	return s
+}
+
+// encodeLabels return label set string sorted by label name, Ex) {check="cpu",domain="syscheck"}
+func encodeLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", k, labelValueEscaper.Replace(labels[k]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// withLabel return label set string having label added at the end, Ex) {check="cpu",le="0.5"}
+func withLabel(encoded, name, value string) string {
+	pair := fmt.Sprintf("%s=\"%s\"", name, labelValueEscaper.Replace(value))
+	if encoded == "" {
+		return "{" + pair + "}"
+	}
+	return strings.TrimSuffix(encoded, "}") + "," + pair + "}"
+}
+
+// formatValue return sample value formatted in text exposition format, Ex) 0.5, 1e+06, +Inf
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// labelValueEscaper escape backslash, double-quote and line feed in label value
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// helpEscaper escape backslash and line feed in help text
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
@@ -0,0 +1,146 @@
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// exposition return body of response served by agent after recording metrics with record function
+func exposition(t *testing.T, record func(pa *prometheusAgent)) string {
+	pa := NewAgent()
+	record(pa)
+
+	w := httptest.NewRecorder()
+	pa.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("content type should be text exposition format, got: %s", ct)
+	}
+	return w.Body.String()
+}
+
+func TestServeHTTP(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		record func(pa *prometheusAgent)
+		want   []string // lines of exposition in order
+	}{
+		{
+			name: "gauge with HELP & TYPE lines",
+			record: func(pa *prometheusAgent) {
+				pa.SetGauge("syscheck_cpu_total_usage_cores", nil, 1.5)
+				pa.SetGauge("syscheck_cpu_total_usage_cores", nil, 0.75)
+			},
+			want: []string{
+				"# HELP syscheck_cpu_total_usage_cores Total cpu usage of runtime system in cores.",
+				"# TYPE syscheck_cpu_total_usage_cores gauge",
+				"syscheck_cpu_total_usage_cores 0.75",
+			},
+		}, {
+			name: "metric without help has only TYPE line",
+			record: func(pa *prometheusAgent) {
+				pa.SetGauge("custom_metric", nil, 1e+06)
+			},
+			want: []string{
+				"# TYPE custom_metric gauge",
+				"custom_metric 1e+06",
+			},
+		}, {
+			name: "counter only increases",
+			record: func(pa *prometheusAgent) {
+				pa.AddCounter("health_check_runs_total", map[string]string{"check": "cpu"}, 1)
+				pa.AddCounter("health_check_runs_total", map[string]string{"check": "cpu"}, 2)
+				pa.AddCounter("health_check_runs_total", map[string]string{"check": "cpu"}, -5)
+			},
+			want: []string{
+				"# HELP health_check_runs_total Number of check process runs per process level.",
+				"# TYPE health_check_runs_total counter",
+				`health_check_runs_total{check="cpu"} 3`,
+			},
+		}, {
+			name: "families are sorted by name & samples by label set",
+			record: func(pa *prometheusAgent) {
+				pa.SetGauge("b_metric", map[string]string{"check": "memory"}, 2)
+				pa.SetGauge("b_metric", map[string]string{"domain": "syscheck", "check": "cpu"}, 1)
+				pa.SetGauge("a_metric", nil, 3)
+			},
+			want: []string{
+				"# TYPE a_metric gauge",
+				"a_metric 3",
+				"# TYPE b_metric gauge",
+				`b_metric{check="cpu",domain="syscheck"} 1`,
+				`b_metric{check="memory"} 2`,
+			},
+		}, {
+			name: "label values are escaped",
+			record: func(pa *prometheusAgent) {
+				pa.SetGauge("escaped_metric", map[string]string{"path": `C:\dir`, "text": "say \"hi\"\nbye"}, 1)
+			},
+			want: []string{
+				"# TYPE escaped_metric gauge",
+				`escaped_metric{path="C:\\dir",text="say \"hi\"\nbye"} 1`,
+			},
+		}, {
+			name: "name used as another type is ignored",
+			record: func(pa *prometheusAgent) {
+				pa.SetGauge("mixed_metric", nil, 1)
+				pa.AddCounter("mixed_metric", nil, 10)
+			},
+			want: []string{
+				"# TYPE mixed_metric gauge",
+				"mixed_metric 1",
+			},
+		}, {
+			name: "histogram has cumulative buckets, sum & count",
+			record: func(pa *prometheusAgent) {
+				labels := map[string]string{"check": "cpu"}
+				pa.ObserveHistogram("health_check_duration_seconds", labels, 0.3)
+				pa.ObserveHistogram("health_check_duration_seconds", labels, 4)
+				pa.ObserveHistogram("health_check_duration_seconds", labels, 200)
+			},
+			want: []string{
+				"# HELP health_check_duration_seconds Duration of check process runs in seconds.",
+				"# TYPE health_check_duration_seconds histogram",
+				`health_check_duration_seconds_bucket{check="cpu",le="0.05"} 0`,
+				`health_check_duration_seconds_bucket{check="cpu",le="0.1"} 0`,
+				`health_check_duration_seconds_bucket{check="cpu",le="0.25"} 0`,
+				`health_check_duration_seconds_bucket{check="cpu",le="0.5"} 1`,
+				`health_check_duration_seconds_bucket{check="cpu",le="1"} 1`,
+				`health_check_duration_seconds_bucket{check="cpu",le="2.5"} 1`,
+				`health_check_duration_seconds_bucket{check="cpu",le="5"} 2`,
+				`health_check_duration_seconds_bucket{check="cpu",le="10"} 2`,
+				`health_check_duration_seconds_bucket{check="cpu",le="30"} 2`,
+				`health_check_duration_seconds_bucket{check="cpu",le="60"} 2`,
+				`health_check_duration_seconds_bucket{check="cpu",le="120"} 2`,
+				`health_check_duration_seconds_bucket{check="cpu",le="+Inf"} 3`,
+				`health_check_duration_seconds_sum{check="cpu"} 204.3`,
+				`health_check_duration_seconds_count{check="cpu"} 3`,
+			},
+		}, {
+			name: "histogram without labels",
+			record: func(pa *prometheusAgent) {
+				pa.ObserveHistogram("plain_histogram", nil, 0.01)
+			},
+			want: append([]string{"# TYPE plain_histogram histogram"}, func() (lines []string) {
+				for _, bound := range []string{"0.05", "0.1", "0.25", "0.5", "1", "2.5", "5", "10", "30", "60", "120", "+Inf"} {
+					lines = append(lines, `plain_histogram_bucket{le="`+bound+`"} 1`)
+				}
+				return append(lines, "plain_histogram_sum 0.01", "plain_histogram_count 1")
+			}()...),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := exposition(t, tc.record)
+			if want := strings.Join(tc.want, "\n") + "\n"; got != want {
+				t.Errorf("exposition should be\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestServeHTTPWithoutMetric(t *testing.T) {
+	if got := exposition(t, func(*prometheusAgent) {}); got != "" {
+		t.Errorf("exposition without metric should be empty, got: %q", got)
+	}
+}
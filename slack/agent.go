@@ -7,7 +7,30 @@
 
 package slack
 
-import "github.com/slack-go/slack"
+import (
+	"github.com/slack-go/slack"
+	"sync"
+	"time"
+)
+
+// threadTTL is duration to keep thread of check process run, which is longer enough than one check process
+const threadTTL = time.Hour * 1
+
+// incidentTTL is duration to keep incident without any alert of that check, which is longer enough than alert reminder interval
+// incident opened by alert not followed by recovered or reset alert (Ex, error in one run) is closed after that
+const incidentTTL = time.Hour * 6
+
+// chatThread is struct having timestamp of thread parent message & time when that message was posted
+type chatThread struct {
+	ts       string
+	postedAt time.Time
+}
+
+// chatIncident is struct having thread of run which started incident & time when the latest alert of that check was sent
+type chatIncident struct {
+	chatThread
+	alertedAt time.Time
+}
 
 // slackAgent agent various slack API(chat, conversations, admin, etc ...) as implementation
 type slackAgent struct {
@@ -16,12 +39,42 @@ type slackAgent struct {
 
 	// chatChannel is having channel ID value to send chat in SendMessage method
 	chatChannel string
+
+	// kibanaURL is address of kibana used for linking history of alert in message (optional)
+	kibanaURL string
+
+	// kibanaIndexPattern is id of kibana index pattern covering history index, used in link of history (optional)
+	kibanaIndexPattern string
+
+	// threads is map having thread parent message of each check process run with uuid as key
+	threads map[string]chatThread
+
+	// incidents is map having thread of run which started incident not recovered yet with check type as key
+	incidents map[string]chatIncident
+
+	// threadMutex help to prevent race condition when access threads & incidents field
+	threadMutex sync.Mutex
+
+	// now return current time, used for checking TTL of thread & incident (replaced with fake clock in test)
+	now func() time.Time
 }
 
 // NewAgent return new initialized instance of slackAgent pointer type with slack client & chat channel
-func NewAgent(token, cnl string) *slackAgent {
+// if apiURL is not empty, slack client send request to that url instead of slack API (Ex, local stand-in for test)
+// if kibanaURL is not empty, alert message has button linking history of alert in kibana, searched in kibanaIndexPattern
+func NewAgent(token, cnl, apiURL, kibanaURL, kibanaIndexPattern string) *slackAgent {
+	var opts []slack.Option
+	if apiURL != "" {
+		opts = append(opts, slack.OptionAPIURL(apiURL))
+	}
+
 	return &slackAgent{
-		slkCli:      slack.New(token),
-		chatChannel: cnl,
+		slkCli:             slack.New(token, opts...),
+		chatChannel:        cnl,
+		kibanaURL:          kibanaURL,
+		kibanaIndexPattern: kibanaIndexPattern,
+		threads:            map[string]chatThread{},
+		incidents:          map[string]chatIncident{},
+		now:                time.Now,
 	}
 }
@@ -0,0 +1,145 @@
+// agent_block.go file define function building block kit message about alert delivered to slackAgent
+// plain text of message is still sent with blocks, so that used as fallback in notification or old client
+
+package slack
+
+import (
+	"fmt"
+	"github.com/slack-go/slack"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// maxSectionFields is max number of fields in one section block limited by slack
+const maxSectionFields = 10
+
+// alertBlocks return blocks of message about alert having fields of check, level, uuid, measurements & thresholds
+// if kibanaURL is not empty, button linking history of alert in kibana is added at the end of blocks
+func alertBlocks(alert *domain.Alert, emoji, kibanaURL, indexPattern string) (blocks []slack.Block) {
+	header := fmt.Sprintf(":%s: *%s*", emoji, alert.Text)
+	if mentions := mentionText(alert.Mentions); mentions != "" {
+		header += "\n" + mentions
+	}
+	blocks = append(blocks, slack.NewSectionBlock(markdown(header), nil, nil))
+
+	fields := []*slack.TextBlockObject{
+		markdown(fmt.Sprintf("*check*\n%s.%s", alert.Domain, alert.Type)),
+		markdown(fmt.Sprintf("*level*\n%s", alert.Level)),
+		markdown(fmt.Sprintf("*uuid*\n%s", alert.UUID)),
+	}
+	fields = append(fields, valueFields(alert.Measurements, "")...)
+	fields = append(fields, valueFields(alert.Thresholds, "threshold ")...)
+
+	for len(fields) > 0 {
+		n := maxSectionFields
+		if len(fields) < n {
+			n = len(fields)
+		}
+		blocks = append(blocks, slack.NewSectionBlock(nil, fields[:n], nil))
+		fields = fields[n:]
+	}
+
+	if kibanaURL != "" {
+		button := slack.NewButtonBlockElement("history", alert.UUID, plainText("View history in Kibana"))
+		button.URL = historyURL(kibanaURL, indexPattern, alert)
+		blocks = append(blocks, slack.NewActionBlock("", button))
+	}
+	return
+}
+
+// mentionText return text mentioning slack user groups or users, ID starting with S is regarded as user group
+func mentionText(ids []string) string {
+	mentions := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if strings.HasPrefix(id, "S") {
+			mentions = append(mentions, fmt.Sprintf("<!subteam^%s>", id))
+		} else {
+			mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+		}
+	}
+	return strings.Join(mentions, " ")
+}
+
+// valueFields return fields of value in map sorted by key, except value which is nil or empty collection
+func valueFields(values map[string]interface{}, prefix string) (fields []*slack.TextBlockObject) {
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if !isEmptyValue(value) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fields = append(fields, markdown(fmt.Sprintf("*%s%s*\n%s", prefix, key, formatValue(values[key]))))
+	}
+	return
+}
+
+// isEmptyValue return if value is nil or empty slice & map, zero value is not empty as it is meaningful (Ex, 0% usage)
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// formatValue return value formatted as string to show in field
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return fmt.Sprintf("%.02f", v)
+	case []string:
+		return strings.Join(v, ", ")
+	case map[string][]string:
+		pairs := make([]string, 0, len(v))
+		for key, list := range v {
+			pairs = append(pairs, fmt.Sprintf("%s: %d", key, len(list)))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// historyTimeRange is time range searched around time of alert in kibana discover page
+const historyTimeRange = time.Hour
+
+// historyURL return url of kibana discover page searching history of alert in index pattern around time of alert
+// default index pattern of kibana is used if indexPattern is empty
+func historyURL(kibanaURL, indexPattern string, alert *domain.Alert) string {
+	const layout = "2006-01-02T15:04:05.000Z"
+	from := alert.Timestamp.Add(-historyTimeRange).UTC().Format(layout)
+	to := alert.Timestamp.Add(historyTimeRange).UTC().Format(layout)
+
+	app := fmt.Sprintf("query:(language:kuery,query:'%s')", url.PathEscape(fmt.Sprintf(`uuid:"%s"`, alert.UUID)))
+	if indexPattern != "" {
+		app = fmt.Sprintf("index:'%s',%s", url.PathEscape(indexPattern), app)
+	}
+	return fmt.Sprintf("%s/app/discover#/?_g=(time:(from:'%s',to:'%s'))&_a=(%s)", kibanaURL, from, to, app)
+}
+
+// markdown return text block object of mrkdwn type with text
+func markdown(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+}
+
+// plainText return text block object of plain_text type with text
+func plainText(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.PlainTextType, text, false, false)
+}
@@ -0,0 +1,47 @@
+package slack
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+func TestValueFieldsKeepZeroValue(t *testing.T) {
+	fields := valueFields(map[string]interface{}{
+		"cpu_usage":          float64(0),
+		"removed_containers": 0,
+		"container":          "",
+		"target_containers":  []string{},
+		"unhealthy_services": map[string][]string{},
+		"error":              nil,
+	}, "")
+
+	var keys []string
+	for _, field := range fields {
+		keys = append(keys, strings.SplitN(field.Text, "\n", 2)[0])
+	}
+	if got := strings.Join(keys, ","); got != "*container*,*cpu_usage*,*removed_containers*" {
+		t.Errorf("fields of zero value should be kept & nil or empty collection should be dropped, got: %s", got)
+	}
+}
+
+func TestHistoryURLSearchesAroundAlertTime(t *testing.T) {
+	alert := &domain.Alert{UUID: "abc", Timestamp: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+
+	u := historyURL("http://kibana", "health-check-history", alert)
+	for _, want := range []string{
+		"time:(from:'2026-10-16T11:00:00.000Z',to:'2026-10-16T13:00:00.000Z')",
+		"index:'health-check-history'",
+		"uuid:%22abc%22",
+	} {
+		if !strings.Contains(u, want) {
+			t.Errorf("history url should contain %s, got: %s", want, u)
+		}
+	}
+
+	if u := historyURL("http://kibana", "", alert); strings.Contains(u, "index:") {
+		t.Errorf("history url without index pattern should use default index pattern, got: %s", u)
+	}
+}
@@ -9,28 +9,146 @@ import (
 	"github.com/pkg/errors"
 	"github.com/slack-go/slack"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
 )
 
 // SendMessage send message with text & emoji using slack API and return send time & text & error
 func (sa *slackAgent) SendMessage(emoji, text, uuid string, opts ...slack.MsgOption) (t time.Time, _text string, err error) {
+	t, _text, _, err = sa.sendMessage(emoji, text, uuid, opts...)
+	return
+}
+
+// sendMessage send message with text & emoji using slack API and return send time & text & timestamp of message & error
+func (sa *slackAgent) sendMessage(emoji, text, uuid string, opts ...slack.MsgOption) (t time.Time, _text, ts string, err error) {
 	if emoji != "" {
 		_text = fmt.Sprintf(":%s: %s (%s)", emoji, text, uuid)
 	}
 
 	opts = append(opts, slack.MsgOptionText(_text, false))
-	_, _time, _, err := sa.slkCli.SendMessage(sa.chatChannel, opts...)
+	_, ts, _, err = sa.slkCli.SendMessage(sa.chatChannel, opts...)
 	if err != nil {
 		err = errors.Wrap(err, "failed to send message with slack API")
 		return
 	}
 
-	if len(_time) >= 10 {
-		i, _ := strconv.ParseInt(_time[:10], 10, 64)
-		t = time.Unix(i, 0)
-		if t.Location().String() == time.UTC.String() {
-			t = t.Add(time.Hour * 9)
+	t = parseTimestamp(ts)
+	return
+}
+
+// parseTimestamp parse timestamp of slack message (Ex, 1605139140.000700) to time in timezone set in domain package
+// both seconds & fraction part are parsed, so that precision of timestamp is not lost
+func parseTimestamp(ts string) (t time.Time) {
+	parts := strings.SplitN(ts, ".", 2)
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return
+	}
+
+	var nsec int64
+	if len(parts) == 2 && parts[1] != "" {
+		frac := (parts[1] + "000000000")[:9]
+		if nsec, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			nsec = 0
 		}
 	}
+	return time.Unix(sec, nsec).In(domain.Location())
+}
+
+// alertEmojis is map having emoji to decorate message of each alert level
+var alertEmojis = map[domain.AlertLevel]string{
+	domain.AlertLevelWarning:       "warning",
+	domain.AlertLevelWeakDetected:  "pill",
+	domain.AlertLevelRecovered:     "heart",
+	domain.AlertLevelUnhealthy:     "broken_heart",
+	domain.AlertLevelError:         "x",
+	domain.AlertLevelRecoveryError: "anger",
+	domain.AlertLevelReset:         "wrench",
+	domain.AlertLevelEscalated:     "rotating_light",
+}
+
+// incidentLevels is set of alert level which start incident of check, closed with alert of other level except escalated one
+var incidentLevels = map[domain.AlertLevel]bool{
+	domain.AlertLevelWeakDetected:  true,
+	domain.AlertLevelUnhealthy:     true,
+	domain.AlertLevelError:         true,
+	domain.AlertLevelRecoveryError: true,
+}
+
+// Notify send message about alert with emoji matched to alert level using slack API and return send time & text & error
+// message is built with block kit having fields about alert, and plain text is sent together as fallback
+// first message of check process run is posted as thread parent & later messages with same uuid are replied in that thread
+// recovered or reset message of later run is replied in thread of incident with broadcast, so that links back to incident
+// escalated message is also replied in thread of incident with broadcast, mentioning users set in alert
+// incident is closed with healthy alert (Ex, warning) of later run too, or when no alert of that check is sent in incident TTL
+func (sa *slackAgent) Notify(alert *domain.Alert) (t time.Time, text string, err error) {
+	check := alert.Domain + "." + alert.Type
+	closing := alert.Level == domain.AlertLevelRecovered || alert.Level == domain.AlertLevelReset
+	escalating := alert.Level == domain.AlertLevelEscalated
+
+	sa.threadMutex.Lock()
+	sa.pruneIncidents()
+	thread, inThread := sa.threads[alert.UUID]
+	incident, inIncident := sa.incidents[check]
+	sa.threadMutex.Unlock()
+
+	emoji := alertEmojis[alert.Level]
+	opts := []slack.MsgOption{slack.MsgOptionBlocks(alertBlocks(alert, emoji, sa.kibanaURL, sa.kibanaIndexPattern)...)}
+	switch {
+	case inThread:
+		opts = append(opts, slack.MsgOptionTS(thread.ts))
+	case (closing || escalating) && inIncident:
+		opts = append(opts, slack.MsgOptionTS(incident.ts), slack.MsgOptionBroadcast())
+	}
+
+	msg := alert.Text
+	if mentions := mentionText(alert.Mentions); mentions != "" {
+		msg = mentions + " " + msg
+	}
+
+	t, text, ts, err := sa.sendMessage(emoji, msg, alert.UUID, opts...)
+	if err != nil {
+		return
+	}
+
+	sa.threadMutex.Lock()
+	defer sa.threadMutex.Unlock()
+
+	now := sa.now()
+	if !inThread && !((closing || escalating) && inIncident) {
+		thread = chatThread{ts: ts, postedAt: now}
+		sa.threads[alert.UUID] = thread
+		sa.pruneThreads()
+	}
+
+	if incident, ok := sa.incidents[check]; ok && (incidentLevels[alert.Level] || escalating) {
+		incident.alertedAt = now
+		sa.incidents[check] = incident
+	} else if ok {
+		// recovered, reset or healthy alert like warning means that check is not in incident any more
+		delete(sa.incidents, check)
+	} else if incidentLevels[alert.Level] {
+		sa.incidents[check] = chatIncident{chatThread: thread, alertedAt: now}
+	}
 	return
 }
+
+// pruneThreads remove thread of check process run posted before thread TTL, must be called with threadMutex locked
+func (sa *slackAgent) pruneThreads() {
+	for uuid, thread := range sa.threads {
+		if sa.now().Sub(thread.postedAt) > threadTTL {
+			delete(sa.threads, uuid)
+		}
+	}
+}
+
+// pruneIncidents close incident of check which no alert was sent in incident TTL, must be called with threadMutex locked
+func (sa *slackAgent) pruneIncidents() {
+	for check, incident := range sa.incidents {
+		if sa.now().Sub(incident.alertedAt) > incidentTTL {
+			delete(sa.incidents, check)
+		}
+	}
+}
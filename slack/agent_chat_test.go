@@ -0,0 +1,88 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+func TestNotifyIncidentThread(t *testing.T) {
+	// step is alert notified after clock is advanced, expected thread (empty if posted as thread parent) & if broadcast
+	type step struct {
+		after     time.Duration
+		uuid      string
+		level     domain.AlertLevel
+		thread    string
+		broadcast bool
+	}
+
+	// ts of n-th message posted in stand-in is 1600000000.00000n
+	for _, tc := range []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "alerts of one run are replied in thread of that run",
+			steps: []step{
+				{uuid: "uuid-1", level: domain.AlertLevelWeakDetected},
+				{uuid: "uuid-1", level: domain.AlertLevelRecovered, thread: "1600000000.000001"},
+				{uuid: "uuid-2", level: domain.AlertLevelWarning},
+			},
+		}, {
+			name: "recovered alert of later run is broadcast in thread of incident & closes it",
+			steps: []step{
+				{uuid: "uuid-1", level: domain.AlertLevelUnhealthy},
+				{uuid: "uuid-2", level: domain.AlertLevelEscalated, thread: "1600000000.000001", broadcast: true},
+				{uuid: "uuid-3", level: domain.AlertLevelRecovered, thread: "1600000000.000001", broadcast: true},
+				{uuid: "uuid-4", level: domain.AlertLevelReset},
+			},
+		}, {
+			name: "error incident is closed by healthy alert of later run",
+			steps: []step{
+				{uuid: "uuid-1", level: domain.AlertLevelError},
+				{uuid: "uuid-2", level: domain.AlertLevelWarning},
+				{uuid: "uuid-3", level: domain.AlertLevelRecovered},
+			},
+		}, {
+			name: "incident without alert in TTL is closed",
+			steps: []step{
+				{uuid: "uuid-1", level: domain.AlertLevelError},
+				{after: incidentTTL + time.Minute, uuid: "uuid-2", level: domain.AlertLevelRecovered},
+			},
+		}, {
+			name: "incident TTL is extended by alert of that check",
+			steps: []step{
+				{uuid: "uuid-1", level: domain.AlertLevelError},
+				{after: incidentTTL - time.Minute, uuid: "uuid-2", level: domain.AlertLevelError},
+				{after: incidentTTL - time.Minute, uuid: "uuid-3", level: domain.AlertLevelRecovered, thread: "1600000000.000001", broadcast: true},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			standIn := newSlackStandIn()
+			defer standIn.Close()
+
+			agent := NewAgent("xoxb-test", testChannel, standIn.URL+"/", "", "")
+			clock := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+			agent.now = func() time.Time { return clock }
+
+			for i, s := range tc.steps {
+				clock = clock.Add(s.after)
+				alert := &domain.Alert{Domain: "syscheck", Type: "CPUCheck", Level: s.level, UUID: s.uuid, Text: "cpu check", Timestamp: clock}
+				if _, _, err := agent.Notify(alert); err != nil {
+					t.Fatalf("step %d: alert should be notified, err: %v", i, err)
+				}
+
+				messages := standIn.postedMessages()
+				msg := messages[len(messages)-1]
+				if thread := msg.Get("thread_ts"); thread != s.thread {
+					t.Errorf("step %d: %s alert of %s should be posted in thread %q, got: %q", i, s.level, s.uuid, s.thread, thread)
+				}
+				if broadcast := msg.Get("reply_broadcast") == "true"; broadcast != s.broadcast {
+					t.Errorf("step %d: if %s alert of %s is broadcast should be %t, got: %t", i, s.level, s.uuid, s.broadcast, broadcast)
+				}
+			}
+		})
+	}
+}
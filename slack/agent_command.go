@@ -0,0 +1,285 @@
+// agent_command.go file define commandRouter which deliver slack command to handler registered in each of domain
+// command can be received as slash command(/health run consul) or app mention event(@health-check run consul)
+// result of command is replied in thread of alert channel using slack chat API
+
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// commandHandler is function handling command about one target & returning reply text
+// user is name of user who sent command & args is the remaining words after action and target
+type commandHandler func(ctx context.Context, user string, args []string) (reply string)
+
+// actionForAllTargets is action that is handled with every target if target is omitted (Ex, /health status)
+const actionForAllTargets = "status"
+
+// commandRouter route slack command consisted of action & target to handler registered with HandleCommand method
+type commandRouter struct {
+	// agent is used for replying result of command in alert channel
+	agent *slackAgent
+
+	// signingSecret is used for verifying request is sent from slack
+	signingSecret string
+
+	// wg is used for waiting command in process while shutting down
+	wg *sync.WaitGroup
+
+	// handlers is map binding type having handler per target per action
+	handlers map[string]map[string]commandHandler
+
+	// mutex help to prevent race condition when access handlers field
+	mutex sync.RWMutex
+}
+
+// NewCommandRouter return new initialized instance of commandRouter pointer type replying with slackAgent
+func NewCommandRouter(sa *slackAgent, signingSecret string, wg *sync.WaitGroup) *commandRouter {
+	return &commandRouter{
+		agent:         sa,
+		signingSecret: signingSecret,
+		wg:            wg,
+		handlers:      map[string]map[string]commandHandler{},
+	}
+}
+
+// HandleCommand register handler about command consisted of action & target (Ex, action: run, target: consul)
+func (cr *commandRouter) HandleCommand(action, target string, handler func(ctx context.Context, user string, args []string) (reply string)) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	if _, ok := cr.handlers[action]; !ok {
+		cr.handlers[action] = map[string]commandHandler{}
+	}
+	cr.handlers[action][target] = handler
+}
+
+// ServeSlashCommand handle http request of slash command, reply result in thread of message echoing that command
+func (cr *commandRouter) ServeSlashCommand(w http.ResponseWriter, r *http.Request) {
+	if !cr.verify(w, r) {
+		return
+	}
+
+	cmd, err := slack.SlashCommandParse(r)
+	if err != nil {
+		http.Error(w, "failed to parse slash command", http.StatusBadRequest)
+		return
+	}
+
+	if cmd.ChannelID != cr.agent.chatChannel {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"text": "health check command is only allowed in alert channel"})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+
+	cr.wg.Add(1)
+	go func() {
+		defer cr.wg.Done()
+		echo := fmt.Sprintf("<@%s> requested `%s %s`", cmd.UserID, cmd.Command, cmd.Text)
+		_, ts, err := cr.agent.slkCli.PostMessage(cmd.ChannelID, slack.MsgOptionText(echo, false))
+		if err != nil {
+			log.Printf("failed to post slash command echo message, err: %v", err)
+			return
+		}
+		cr.reply(cmd.ChannelID, ts, cr.dispatch(cr.userName(cmd.UserID), cmd.Text))
+	}()
+}
+
+// ServeEvent handle http request of events API, reply result of app mention in thread of that mention
+func (cr *commandRouter) ServeEvent(w http.ResponseWriter, r *http.Request) {
+	if !cr.verify(w, r) {
+		return
+	}
+
+	// slack retry sending event if response is late, but command should not be run twice
+	if r.Header.Get("X-Slack-Retry-Num") != "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	event, err := slackevents.ParseEvent(body, slackevents.OptionNoVerifyToken())
+	if err != nil {
+		http.Error(w, "failed to parse event", http.StatusBadRequest)
+		return
+	}
+
+	switch event.Type {
+	case slackevents.URLVerification:
+		var challenge slackevents.ChallengeResponse
+		if err := json.Unmarshal(body, &challenge); err != nil {
+			http.Error(w, "failed to parse challenge", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(challenge.Challenge))
+		return
+	case slackevents.CallbackEvent:
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	ev, ok := event.InnerEvent.Data.(*slackevents.AppMentionEvent)
+	if !ok || ev.BotID != "" || ev.Channel != cr.agent.chatChannel {
+		return
+	}
+
+	threadTS := ev.ThreadTimeStamp
+	if threadTS == "" {
+		threadTS = ev.TimeStamp
+	}
+
+	cr.wg.Add(1)
+	go func() {
+		defer cr.wg.Done()
+		cr.reply(ev.Channel, threadTS, cr.dispatch(cr.userName(ev.User), trimMention(ev.Text)))
+	}()
+}
+
+// verify return if request is signed with signing secret & write 401 response if not
+// request body is restored after verifying, so can be read again in caller
+func (cr *commandRouter) verify(w http.ResponseWriter, r *http.Request) bool {
+	sv, err := slack.NewSecretsVerifier(r.Header, cr.signingSecret)
+	if err != nil {
+		http.Error(w, "invalid slack signature header", http.StatusUnauthorized)
+		return false
+	}
+
+	body, err := ioutil.ReadAll(io.TeeReader(r.Body, &sv))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return false
+	}
+
+	if err := sv.Ensure(); err != nil {
+		http.Error(w, "invalid slack signature", http.StatusUnauthorized)
+		return false
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return true
+}
+
+// dispatch parse text to action & target, call handler registered with those & return reply text of handler
+// handler is called after releasing mutex, as it can run check process which lasts until check timeout
+func (cr *commandRouter) dispatch(user, text string) string {
+	handlers, args, reply := cr.route(text)
+	if len(handlers) == 0 {
+		return reply
+	}
+
+	ctx := context.WithValue(context.Background(), "time", time.Now())
+	var replies []string
+	for _, handler := range handlers {
+		replies = append(replies, handler(ctx, user, args))
+	}
+	return strings.Join(replies, "\n")
+}
+
+// route parse text to action & target, and return handlers registered with those & args to deliver to handlers
+// if text can't be routed to any handler, reply text about that (Ex, usage, unknown action) is returned instead
+func (cr *commandRouter) route(text string) (handlers []commandHandler, args []string, reply string) {
+	cr.mutex.RLock()
+	defer cr.mutex.RUnlock()
+
+	fields := strings.Fields(text)
+	if len(fields) == 0 || fields[0] == "help" {
+		reply = cr.usage()
+		return
+	}
+
+	action := fields[0]
+	targets, ok := cr.handlers[action]
+	if !ok {
+		reply = fmt.Sprintf("unknown action `%s`\n%s", action, cr.usage())
+		return
+	}
+
+	if len(fields) == 1 {
+		if action != actionForAllTargets {
+			reply = fmt.Sprintf("please specify target of `%s` (%s)", action, strings.Join(sortedKeys(targets), ", "))
+			return
+		}
+		for _, target := range sortedKeys(targets) {
+			handlers = append(handlers, targets[target])
+		}
+		return
+	}
+
+	handler, ok := targets[fields[1]]
+	if !ok {
+		reply = fmt.Sprintf("unknown target `%s` of `%s` (%s)", fields[1], action, strings.Join(sortedKeys(targets), ", "))
+		return
+	}
+	return []commandHandler{handler}, fields[2:], ""
+}
+
+// userName return name of user having id with users.info API, or id if failed to get user info
+func (cr *commandRouter) userName(id string) string {
+	if info, err := cr.agent.slkCli.GetUserInfo(id); err == nil && info.Name != "" {
+		return info.Name
+	}
+	return id
+}
+
+// usage return text describing every action & target registered in router
+func (cr *commandRouter) usage() string {
+	lines := []string{"usage: `<action> <target> [args...]`"}
+	for _, action := range sortedActions(cr.handlers) {
+		lines = append(lines, fmt.Sprintf("• `%s` - %s", action, strings.Join(sortedKeys(cr.handlers[action]), ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reply send text in thread of message having threadTS in channel
+func (cr *commandRouter) reply(channel, threadTS, text string) {
+	opts := []slack.MsgOption{slack.MsgOptionText(text, false), slack.MsgOptionTS(threadTS)}
+	if _, _, err := cr.agent.slkCli.PostMessage(channel, opts...); err != nil {
+		log.Printf("failed to reply slack command result in thread, err: %v", err)
+	}
+}
+
+// trimMention remove mention of user or bot(<@U012AB3CD>) from text of app mention event
+func trimMention(text string) string {
+	var fields []string
+	for _, field := range strings.Fields(text) {
+		if !strings.HasPrefix(field, "<@") {
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// sortedKeys return keys of handler map about target in sorted order
+func sortedKeys(m map[string]commandHandler) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// sortedActions return keys of handler map about action in sorted order
+func sortedActions(m map[string]map[string]commandHandler) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
@@ -0,0 +1,168 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testSigningSecret = "test-signing-secret"
+	testChannel       = "C0123456"
+)
+
+// slackStandIn is local stand-in of slack API, recording messages posted with chat.postMessage
+type slackStandIn struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	messages []url.Values
+}
+
+func newSlackStandIn() *slackStandIn {
+	s := &slackStandIn{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			s.mutex.Lock()
+			s.messages = append(s.messages, r.PostForm)
+			ts := fmt.Sprintf("1600000000.%06d", len(s.messages))
+			s.mutex.Unlock()
+			_, _ = fmt.Fprintf(w, `{"ok": true, "channel": %q, "ts": %q}`, r.PostForm.Get("channel"), ts)
+		case "/users.info":
+			_, _ = fmt.Fprintf(w, `{"ok": true, "user": {"id": %q, "name": "admin"}}`, r.PostForm.Get("user"))
+		default:
+			_, _ = fmt.Fprint(w, `{"ok": false, "error": "unknown_method"}`)
+		}
+	}))
+	return s
+}
+
+func (s *slackStandIn) postedMessages() []url.Values {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]url.Values{}, s.messages...)
+}
+
+// newSlashCommandRequest return slash command request signed with secret
+func newSlashCommandRequest(secret, text string) *http.Request {
+	body := url.Values{
+		"command":    {"/health"},
+		"text":       {text},
+		"channel_id": {testChannel},
+		"user_id":    {"U0123456"},
+	}.Encode()
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + ts + ":" + body))
+
+	r := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Slack-Request-Timestamp", ts)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestServeSlashCommandRejectsInvalidSignature(t *testing.T) {
+	standIn := newSlackStandIn()
+	defer standIn.Close()
+
+	wg := &sync.WaitGroup{}
+	router := NewCommandRouter(NewAgent("xoxb-test", testChannel, standIn.URL+"/", "", ""), testSigningSecret, wg)
+	called := false
+	router.HandleCommand("run", "cpu", func(context.Context, string, []string) string {
+		called = true
+		return "ran"
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeSlashCommand(w, newSlashCommandRequest("wrong-secret", "run cpu"))
+	wg.Wait()
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status code of request signed with wrong secret should be 401, got: %d", w.Code)
+	}
+	if called || len(standIn.postedMessages()) != 0 {
+		t.Error("command of request signed with wrong secret should not be dispatched")
+	}
+}
+
+func TestServeSlashCommandDispatchesToHandler(t *testing.T) {
+	standIn := newSlackStandIn()
+	defer standIn.Close()
+
+	wg := &sync.WaitGroup{}
+	router := NewCommandRouter(NewAgent("xoxb-test", testChannel, standIn.URL+"/", "", ""), testSigningSecret, wg)
+	var gotUser string
+	var gotArgs []string
+	router.HandleCommand("reset", "cpu", func(_ context.Context, user string, args []string) string {
+		gotUser, gotArgs = user, args
+		return "cpu is reset"
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeSlashCommand(w, newSlashCommandRequest(testSigningSecret, "reset cpu container removed"))
+	wg.Wait()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code of signed request should be 200, got: %d", w.Code)
+	}
+	if gotUser != "admin" {
+		t.Errorf("user delivered to handler should be name of user id, got: %s", gotUser)
+	}
+	if strings.Join(gotArgs, " ") != "container removed" {
+		t.Errorf("args delivered to handler should be words after target, got: %v", gotArgs)
+	}
+
+	messages := standIn.postedMessages()
+	if len(messages) != 2 {
+		t.Fatalf("echo & reply message should be posted, got: %d messages", len(messages))
+	}
+	if reply := messages[1]; reply.Get("text") != "cpu is reset" || reply.Get("thread_ts") == "" {
+		t.Errorf("reply of handler should be posted in thread of echo message, got: %v", reply)
+	}
+}
+
+func TestDispatchDoesNotBlockRegistrationWhileHandlerRuns(t *testing.T) {
+	router := NewCommandRouter(NewAgent("xoxb-test", testChannel, "", "", ""), testSigningSecret, &sync.WaitGroup{})
+	running, release := make(chan struct{}), make(chan struct{})
+	router.HandleCommand("run", "cpu", func(context.Context, string, []string) string {
+		close(running)
+		<-release
+		return "ran"
+	})
+
+	done := make(chan string)
+	go func() { done <- router.dispatch("admin", "run cpu") }()
+	<-running
+
+	registered := make(chan struct{})
+	go func() {
+		router.HandleCommand("run", "disk", func(context.Context, string, []string) string { return "ran" })
+		close(registered)
+	}()
+
+	select {
+	case <-registered:
+	case <-time.After(time.Second):
+		t.Error("handler registration should not be blocked by handler in progress")
+	}
+	close(release)
+
+	if reply := <-done; reply != "ran" {
+		t.Errorf("reply of dispatch should be reply of handler, got: %s", reply)
+	}
+}
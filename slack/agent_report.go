@@ -0,0 +1,46 @@
+// agent_report.go file define method of slackAgent about posting report (Ex, digest of check histories)
+// implement report agency interface defined in each of domain
+
+package slack
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+	"strings"
+	"time"
+)
+
+// maxSectionTextLength is max length of text in one section block limited by slack
+const maxSectionTextLength = 3000
+
+// PostReport post report having title & lines as block kit message in chat channel and return posted time & error
+// lines are split into several section blocks, so that each section doesn't exceed text length limited by slack
+func (sa *slackAgent) PostReport(title string, lines []string) (t time.Time, err error) {
+	header := fmt.Sprintf(":bar_chart: *%s*", title)
+	blocks := []slack.Block{slack.NewSectionBlock(markdown(header), nil, nil)}
+
+	var section []string
+	var length int
+	for _, line := range lines {
+		line = "• " + line
+		if length+len(line)+1 > maxSectionTextLength && len(section) != 0 {
+			blocks = append(blocks, slack.NewSectionBlock(markdown(strings.Join(section, "\n")), nil, nil))
+			section, length = nil, 0
+		}
+		section = append(section, line)
+		length += len(line) + 1
+	}
+	if len(section) != 0 {
+		blocks = append(blocks, slack.NewSectionBlock(markdown(strings.Join(section, "\n")), nil, nil))
+	}
+
+	_, ts, _, err := sa.slkCli.SendMessage(sa.chatChannel, slack.MsgOptionBlocks(blocks...), slack.MsgOptionText(title, false))
+	if err != nil {
+		err = errors.Wrap(err, "failed to send report with slack API")
+		return
+	}
+
+	t = parseTimestamp(ts)
+	return
+}
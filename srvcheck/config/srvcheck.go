@@ -29,6 +29,40 @@ type srvcheckConfig struct {
 	// indexReplicaNum represent replica number of elasticsearch index to replace index when node become unable
 	indexReplicaNum *int
 
+	// indexRolloverPeriod represent max age of write index, new write index is created by rollover after that
+	indexRolloverPeriod *time.Duration
+
+	// indexRetentionPeriod represent period to keep srvcheck history, index having only older history is deleted
+	indexRetentionPeriod *time.Duration
+
+	// fields about history file information (implement fileRepositoryComponentConfig)
+	// repositoryType represent type of repository storing srvcheck history (elasticsearch or file)
+	repositoryType *string
+
+	// historyFileDir represent path of directory to store srvcheck history file in if repository type is file
+	historyFileDir *string
+
+	// historyFileMaxSize represent max size of history file, file is rotated when exceeding it
+	historyFileMaxSize *bytesize.ByteSize
+
+	// historyFileMaxBackups represent max number of rotated history file to keep
+	historyFileMaxBackups *int
+
+	// ---
+
+	// fields using in all check usecase in common (implement serviceCheckUsecaseComponentConfig)
+	// checkOverlapPolicy represent policy about check requested while same check is running (skip or queue)
+	checkOverlapPolicy *string
+
+	// elasticsearchCheckTimeOut represent deadline of one elasticsearch check process, applied to context delivered to agency
+	elasticsearchCheckTimeOut *time.Duration
+
+	// swarmpitCheckTimeOut represent deadline of one swarmpit check process, applied to context delivered to agency
+	swarmpitCheckTimeOut *time.Duration
+
+	// consulCheckTimeOut represent deadline of one consul check process, applied to context delivered to agency
+	consulCheckTimeOut *time.Duration
+
 	// ---
 
 	// fields using in elasticsearch health checking (implement elasticsearchCheckUsecaseConfig)
@@ -83,6 +117,20 @@ const (
 	defaultIndexShardNum   = 2                   // default const int for indexShardNum
 	defaultIndexReplicaNum = 0                   // default const int for indexReplicaNum
 
+	defaultIndexRolloverPeriod  = time.Hour * 24      // default const Duration for indexRolloverPeriod
+	defaultIndexRetentionPeriod = time.Hour * 24 * 30 // default const Duration for indexRetentionPeriod
+
+	defaultRepositoryType        = "elasticsearch"                   // default const string for repositoryType
+	defaultHistoryFileDir        = "/usr/share/health-check/history" // default const string for historyFileDir
+	defaultHistoryFileMaxSize    = bytesize.MB * 100                 // default const byte size for historyFileMaxSize
+	defaultHistoryFileMaxBackups = 5                                 // default const int for historyFileMaxBackups
+
+	defaultCheckOverlapPolicy = "skip" // default const string for checkOverlapPolicy
+
+	defaultElasticsearchCheckTimeOut = time.Minute * 1  // default const Duration for elasticsearchCheckTimeOut
+	defaultSwarmpitCheckTimeOut      = time.Minute * 1  // default const Duration for swarmpitCheckTimeOut
+	defaultConsulCheckTimeOut        = time.Second * 50 // default const Duration for consulCheckTimeOut
+
 	defaultMaximumShardsNumber     = 900             // default const int for MaximumShardsNumber
 	defaultJaegerIndexMinLifeCycle = time.Hour * 720 // default const duration for JaegerIndexMinLifeCycle
 	defaultJaegerIndexPattern      = "jaeger-*"      // default const string for JaegerIndexRegexp
@@ -100,6 +148,73 @@ const (
 	defaultConsulCheckDeliveryPingCycle   = time.Minute * 1 // default const Duration for consulCheckDeliveryPingCycle
 )
 
+// implement CheckOverlapPolicy method of serviceCheckUsecaseComponentConfig interface
+func (sc *srvcheckConfig) CheckOverlapPolicy() string {
+	var key = "srvcheck.usecase.overlapPolicy"
+	if sc.checkOverlapPolicy != nil {
+		return *sc.checkOverlapPolicy
+	}
+
+	switch viper.GetString(key) {
+	case "skip", "queue":
+	default:
+		viper.Set(key, defaultCheckOverlapPolicy)
+	}
+	sc.checkOverlapPolicy = _string(viper.GetString(key))
+	return *sc.checkOverlapPolicy
+}
+
+// implement ElasticsearchCheckTimeOut method of elasticsearchCheckUsecaseConfig interface
+func (sc *srvcheckConfig) ElasticsearchCheckTimeOut() time.Duration {
+	var key = "srvcheck.elasticsearch.checkTimeOut"
+	if sc.elasticsearchCheckTimeOut != nil {
+		return *sc.elasticsearchCheckTimeOut
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultElasticsearchCheckTimeOut.String())
+		d = defaultElasticsearchCheckTimeOut
+	}
+
+	sc.elasticsearchCheckTimeOut = &d
+	return *sc.elasticsearchCheckTimeOut
+}
+
+// implement SwarmpitCheckTimeOut method of swarmpitCheckUsecaseConfig interface
+func (sc *srvcheckConfig) SwarmpitCheckTimeOut() time.Duration {
+	var key = "srvcheck.swarmpit.checkTimeOut"
+	if sc.swarmpitCheckTimeOut != nil {
+		return *sc.swarmpitCheckTimeOut
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultSwarmpitCheckTimeOut.String())
+		d = defaultSwarmpitCheckTimeOut
+	}
+
+	sc.swarmpitCheckTimeOut = &d
+	return *sc.swarmpitCheckTimeOut
+}
+
+// implement ConsulCheckTimeOut method of consulCheckUsecaseConfig interface
+func (sc *srvcheckConfig) ConsulCheckTimeOut() time.Duration {
+	var key = "srvcheck.consul.checkTimeOut"
+	if sc.consulCheckTimeOut != nil {
+		return *sc.consulCheckTimeOut
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultConsulCheckTimeOut.String())
+		d = defaultConsulCheckTimeOut
+	}
+
+	sc.consulCheckTimeOut = &d
+	return *sc.consulCheckTimeOut
+}
+
 // implement IndexName method of esRepositoryComponentConfig interface
 func (sc *srvcheckConfig) IndexName() string {
 	var key = "srvcheck.repository.elasticsearch.index.name"
@@ -136,6 +251,97 @@ func (sc *srvcheckConfig) IndexReplicaNum() int {
 	return *sc.indexReplicaNum
 }
 
+// implement IndexRolloverPeriod method of esIndexManagerConfig interface
+func (sc *srvcheckConfig) IndexRolloverPeriod() time.Duration {
+	var key = "srvcheck.repository.elasticsearch.index.rolloverPeriod"
+	if sc.indexRolloverPeriod != nil {
+		return *sc.indexRolloverPeriod
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultIndexRolloverPeriod.String())
+		d = defaultIndexRolloverPeriod
+	}
+
+	sc.indexRolloverPeriod = &d
+	return *sc.indexRolloverPeriod
+}
+
+// implement IndexRetentionPeriod method of esIndexManagerConfig interface
+func (sc *srvcheckConfig) IndexRetentionPeriod() time.Duration {
+	var key = "srvcheck.repository.elasticsearch.index.retentionPeriod"
+	if sc.indexRetentionPeriod != nil {
+		return *sc.indexRetentionPeriod
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultIndexRetentionPeriod.String())
+		d = defaultIndexRetentionPeriod
+	}
+
+	sc.indexRetentionPeriod = &d
+	return *sc.indexRetentionPeriod
+}
+
+// not implement any interface, just using in main function for selecting repository (elasticsearch or file)
+func (sc *srvcheckConfig) RepositoryType() string {
+	var key = "srvcheck.repository.type"
+	if sc.repositoryType != nil {
+		return *sc.repositoryType
+	}
+
+	switch viper.GetString(key) {
+	case "elasticsearch", "file":
+	default:
+		viper.Set(key, defaultRepositoryType)
+	}
+	sc.repositoryType = _string(viper.GetString(key))
+	return *sc.repositoryType
+}
+
+// implement HistoryFileDir method of fileRepositoryComponentConfig interface
+func (sc *srvcheckConfig) HistoryFileDir() string {
+	var key = "srvcheck.repository.file.dir"
+	if sc.historyFileDir == nil {
+		if _, ok := viper.Get(key).(string); !ok {
+			viper.Set(key, defaultHistoryFileDir)
+		}
+		sc.historyFileDir = _string(viper.GetString(key))
+	}
+	return *sc.historyFileDir
+}
+
+// implement HistoryFileMaxSize method of fileRepositoryComponentConfig interface
+func (sc *srvcheckConfig) HistoryFileMaxSize() int64 {
+	var key = "srvcheck.repository.file.maxSize"
+	if sc.historyFileMaxSize != nil {
+		return int64(*sc.historyFileMaxSize)
+	}
+
+	size, err := bytesize.Parse(viper.GetString(key))
+	if err != nil || size == 0 {
+		viper.Set(key, defaultHistoryFileMaxSize.String())
+		size = defaultHistoryFileMaxSize
+	}
+
+	sc.historyFileMaxSize = &size
+	return int64(*sc.historyFileMaxSize)
+}
+
+// implement HistoryFileMaxBackups method of fileRepositoryComponentConfig interface
+func (sc *srvcheckConfig) HistoryFileMaxBackups() int {
+	var key = "srvcheck.repository.file.maxBackups"
+	if sc.historyFileMaxBackups == nil {
+		if _, ok := viper.Get(key).(int); !ok {
+			viper.Set(key, defaultHistoryFileMaxBackups)
+		}
+		sc.historyFileMaxBackups = _int(viper.GetInt(key))
+	}
+	return *sc.historyFileMaxBackups
+}
+
 // implement MaximumShardsNumber method of elasticsearchCheckUsecaseConfig interface
 func (sc *srvcheckConfig) MaximumShardsNumber() int {
 	var key = "srvcheck.elasticsearch.maximumShardsNumber"
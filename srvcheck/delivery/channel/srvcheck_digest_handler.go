@@ -0,0 +1,75 @@
+// in srvcheck_digest_handler.go file, define delivery from channel msg to digest usecase handler
+// publishing msg to golang channel which is received from outside is not occurred in this package
+
+package channel
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// digestTimeout is deadline of one digest process, which read histories of whole period from repository
+const digestTimeout = time.Minute * 5
+
+// digestHandler is delivered data handler about digest of service check using usecase layer
+type digestHandler struct {
+	// DUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	DUsecase domain.ServiceCheckDigestUseCase
+
+	// wg is used for waiting in-flight digest process before process exit, injected from outside (maybe, in main)
+	wg *sync.WaitGroup
+}
+
+// NewDigestHandler define digestHandler ptr instance & register handling daily & weekly channel msg to usecase
+func NewDigestHandler(ctx context.Context, daily, weekly <-chan time.Time, wg *sync.WaitGroup, du domain.ServiceCheckDigestUseCase) {
+	handler := &digestHandler{
+		DUsecase: du,
+		wg:       wg,
+	}
+
+	// count listening goroutine in wait group, so that wg.Add is never called after counter reaches zero
+	wg.Add(1)
+	go handler.startListening(ctx, daily, weekly)
+	log.Println("START TO LISTEN CHANNEL MSG ABOUT SERVICE CHECK DIGEST")
+}
+
+// startListening method start listening msg from golang channel & stream msg to another method
+// listening is stopped when ctx is done, but digest process already started is not canceled
+func (dh *digestHandler) startListening(ctx context.Context, daily, weekly <-chan time.Time) {
+	defer dh.wg.Done()
+
+	for {
+		select {
+		case t := <-daily:
+			dh.wg.Add(1)
+			go func() {
+				defer dh.wg.Done()
+				dh.digest("daily", t.AddDate(0, 0, -1), t)
+			}()
+		case t := <-weekly:
+			dh.wg.Add(1)
+			go func() {
+				defer dh.wg.Done()
+				dh.digest("weekly", t.AddDate(0, 0, -7), t)
+			}()
+		case <-ctx.Done():
+			log.Println("STOP TO LISTEN CHANNEL MSG ABOUT SERVICE CHECK DIGEST")
+			return
+		}
+	}
+}
+
+// digest method set context & call usecase Digest method, handle error
+func (dh *digestHandler) digest(period string, start, end time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), digestTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, "time", end)
+
+	if _, err := dh.DUsecase.Digest(ctx, period, start, end); err != nil {
+		log.Printf("error occurs in Digest, period: %s, err: %v", period, err)
+	}
+}
@@ -6,6 +6,7 @@ package channel
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
@@ -15,24 +16,40 @@ import (
 type elasticsearchCheckHandler struct {
 	// EUsecase is usecase layer interface which is injected from package outside (maybe, in main)
 	EUsecase domain.ElasticsearchCheckUseCase
+
+	// wg is used for waiting in-flight check process before process exit, injected from outside (maybe, in main)
+	wg *sync.WaitGroup
 }
 
 // NewElasticsearchCheckHandler define elasticsearchCheckHandler ptr instance & register handling channel msg to usecase
-func NewElasticsearchCheckHandler(c <-chan time.Time, eu domain.ElasticsearchCheckUseCase) {
+func NewElasticsearchCheckHandler(ctx context.Context, c <-chan time.Time, wg *sync.WaitGroup, eu domain.ElasticsearchCheckUseCase) {
 	handler := &elasticsearchCheckHandler{
 		EUsecase: eu,
+		wg:       wg,
 	}
 
-	go handler.startListening(c)
+	// count listening goroutine in wait group, so that wg.Add is never called after counter reaches zero
+	wg.Add(1)
+	go handler.startListening(ctx, c)
 	log.Println("START TO LISTEN CHANNEL MSG ABOUT SERVICE ELASTICSEARCH CHECK")
 }
 
 // startListening method start listening msg from golang channel & stream msg to another method
-func (eh *elasticsearchCheckHandler) startListening(c <-chan time.Time) {
+// listening is stopped when ctx is done, but check process already started is not canceled
+func (eh *elasticsearchCheckHandler) startListening(ctx context.Context, c <-chan time.Time) {
+	defer eh.wg.Done()
+
 	for {
 		select {
 		case t := <-c:
-			go eh.checkElasticsearch(t)
+			eh.wg.Add(1)
+			go func() {
+				defer eh.wg.Done()
+				eh.checkElasticsearch(t)
+			}()
+		case <-ctx.Done():
+			log.Println("STOP TO LISTEN CHANNEL MSG ABOUT SERVICE ELASTICSEARCH CHECK")
+			return
 		}
 	}
 }
@@ -42,7 +59,7 @@ func (eh *elasticsearchCheckHandler) checkElasticsearch(t time.Time) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, "time", t)
 
-	if err := eh.EUsecase.CheckElasticsearch(ctx); err != nil {
+	if _, err := eh.EUsecase.CheckElasticsearch(ctx); err != nil {
 		log.Printf("error occurs in CheckElasticsearch, err: %v", err)
 	}
 }
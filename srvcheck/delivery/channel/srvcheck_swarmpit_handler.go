@@ -6,6 +6,7 @@ package channel
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
@@ -15,24 +16,40 @@ import (
 type swarmpitCheckHandler struct {
 	// SUsecase is usecase layer interface which is injected from package outside (maybe, in main)
 	SUsecase domain.SwarmpitCheckUseCase
+
+	// wg is used for waiting in-flight check process before process exit, injected from outside (maybe, in main)
+	wg *sync.WaitGroup
 }
 
 // NewSwarmpitCheckHandler define swarmpitCheckHandler ptr instance & register handling channel msg to usecase
-func NewSwarmpitCheckHandler(c <-chan time.Time, su domain.SwarmpitCheckUseCase) {
+func NewSwarmpitCheckHandler(ctx context.Context, c <-chan time.Time, wg *sync.WaitGroup, su domain.SwarmpitCheckUseCase) {
 	handler := &swarmpitCheckHandler{
 		SUsecase: su,
+		wg:       wg,
 	}
 
-	go handler.startListening(c)
+	// count listening goroutine in wait group, so that wg.Add is never called after counter reaches zero
+	wg.Add(1)
+	go handler.startListening(ctx, c)
 	log.Println("START TO LISTEN CHANNEL MSG ABOUT SERVICE SWARMPIT CHECK")
 }
 
 // startListening method start listening msg from golang channel & stream msg to another method
-func (sh *swarmpitCheckHandler) startListening(c <-chan time.Time) {
+// listening is stopped when ctx is done, but check process already started is not canceled
+func (sh *swarmpitCheckHandler) startListening(ctx context.Context, c <-chan time.Time) {
+	defer sh.wg.Done()
+
 	for {
 		select {
 		case t := <-c:
-			go sh.checkSwarmpit(t)
+			sh.wg.Add(1)
+			go func() {
+				defer sh.wg.Done()
+				sh.checkSwarmpit(t)
+			}()
+		case <-ctx.Done():
+			log.Println("STOP TO LISTEN CHANNEL MSG ABOUT SERVICE SWARMPIT CHECK")
+			return
 		}
 	}
 }
@@ -42,7 +59,7 @@ func (sh *swarmpitCheckHandler) checkSwarmpit(t time.Time) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, "time", t)
 
-	if err := sh.SUsecase.CheckSwarmpit(ctx); err != nil {
+	if _, err := sh.SUsecase.CheckSwarmpit(ctx); err != nil {
 		log.Printf("error occurs in CheckSwarmpit, err: %v", err)
 	}
 }
@@ -0,0 +1,185 @@
+// delivery package is for delivery layer acted as presenter layer in srvcheck domain which decide how the data will presented
+// in delivery type, could be as REST API, gRPC, golang channel, or HTML file, etc ...
+// in http delivery, deliver data to usecase by receiving from http request & present result as json response
+
+// srvcheck.go is file that define interface or function used jointly in http delivery package as private.
+// registering handler to http server router is occurred in this package, but running http server is not.
+
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// router is interface to register http handler function with pattern, implemented in *http.ServeMux
+type router interface {
+	// HandleFunc register the handler function for the given pattern
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// statusResponse is response body format about current status of check process state machine
+type statusResponse struct {
+	// Status specifies current status of check process state machine (Ex, HEALTHY, RECOVERING)
+	Status string `json:"status"`
+
+	// LastHistory specifies history produced in last check process as dotted map
+	LastHistory map[string]interface{} `json:"last_history"`
+}
+
+// checkResponse is response body format about result of check process run on demand
+type checkResponse struct {
+	// UUID specifies uuid of history produced in check process run on demand
+	UUID string `json:"uuid"`
+
+	// ProcessLevel specifies process level of history produced in check process run on demand
+	ProcessLevel string `json:"process_level"`
+
+	// Message specifies message of history produced in check process run on demand
+	Message string `json:"message"`
+
+	// Error specifies error occurred while running check process or storing history (empty if not occurred)
+	Error string `json:"error,omitempty"`
+}
+
+// historiesResponse is response body format about histories queried from repository
+type historiesResponse struct {
+	// Histories specifies queried histories as dotted map (in order of time, or newest first if latest is queried)
+	Histories []map[string]interface{} `json:"histories"`
+}
+
+// default & max number of latest histories queried if time range & process level are not set in query parameter
+const (
+	defaultLatestHistories = 20
+	maxLatestHistories     = 1000
+)
+
+// default & max time range of histories queried, default is used if only one of start & end is set in query parameter
+const (
+	defaultHistoriesRange = time.Hour * 24
+	maxHistoriesRange     = time.Hour * 24 * 7
+)
+
+// historiesQuery is query parameters about querying histories, latest histories are queried if Latest is not zero
+type historiesQuery struct {
+	Level  string
+	Start  time.Time
+	End    time.Time
+	Latest int
+}
+
+// parseHistoriesQuery parse query parameters about querying histories & write 400 response if query is invalid
+// if start, end (RFC3339) or level is set, histories in time range (up to max range) are queried. if not, latest n histories are queried
+func parseHistoriesQuery(w http.ResponseWriter, r *http.Request) (q historiesQuery, ok bool) {
+	values := r.URL.Query()
+	q.Level = strings.ToUpper(values.Get("level"))
+
+	if values.Get("start") == "" && values.Get("end") == "" && q.Level == "" {
+		q.Latest = defaultLatestHistories
+		if s := values.Get("latest"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 || n > maxLatestHistories {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("latest should be number between 1 and %d", maxLatestHistories)})
+				return
+			}
+			q.Latest = n
+		}
+		ok = true
+		return
+	}
+
+	var err error
+	q.End = time.Now()
+	if s := values.Get("end"); s != "" {
+		if q.End, err = time.Parse(time.RFC3339, s); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "end should be formatted in RFC3339, " + err.Error()})
+			return
+		}
+	}
+	q.Start = q.End.Add(-defaultHistoriesRange)
+	if s := values.Get("start"); s != "" {
+		if q.Start, err = time.Parse(time.RFC3339, s); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "start should be formatted in RFC3339, " + err.Error()})
+			return
+		}
+	}
+	if !q.Start.Before(q.End) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "start should be before end"})
+		return
+	}
+	if q.End.Sub(q.Start) > maxHistoriesRange {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("time range should not be longer than %s", maxHistoriesRange)})
+		return
+	}
+	ok = true
+	return
+}
+
+// resetRequest is request body format about resetting status of check process by administrator
+type resetRequest struct {
+	// By specifies who reset status of check process (required)
+	By string `json:"by"`
+
+	// Reason specifies why administrator reset status of check process (required)
+	Reason string `json:"reason"`
+}
+
+// newCheckContext return context to deliver to usecase when running check process on demand
+// request context is not used, because check process should not be canceled even if client disconnected
+// it is not derived from root context either, so that remediation in progress is not canceled while shutting down
+func newCheckContext() context.Context {
+	return context.WithValue(context.Background(), "time", time.Now())
+}
+
+// allowMethod return if request method is same with method received from param & write 405 response if not
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	return false
+}
+
+// authorize return if request has admin token in Authorization header with Bearer scheme & write 401 response if not
+func authorize(w http.ResponseWriter, r *http.Request, token string) bool {
+	const scheme = "Bearer "
+	header := r.Header.Get("Authorization")
+	if token != "" && strings.HasPrefix(header, scheme) &&
+		subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, scheme)), []byte(token)) == 1 {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+	return false
+}
+
+// decodeResetRequest decode request body to resetRequest & write 400 response if body is invalid
+func decodeResetRequest(w http.ResponseWriter, r *http.Request) (req resetRequest, ok bool) {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body, " + err.Error()})
+		return
+	}
+	if req.By == "" || req.Reason == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "by and reason are required in request body"})
+		return
+	}
+	ok = true
+	return
+}
+
+// writeJSON write v received from param to response writer as json with status code
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to encode json response, err: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// in srvcheck_admin_handler.go file, define delivery from http request to administrator operation of srvcheck usecase
+// every path is prefixed with /admin/srvcheck & requires admin token, Ex) /admin/srvcheck/status, /admin/srvcheck/elasticsearch/reset
+
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// adminHandler is delivered data handler about administrator operation to service check using usecase layer
+type adminHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token
+	token string
+
+	// EUsecase is usecase layer interface about elasticsearch check which is injected from package outside (maybe, in main)
+	EUsecase domain.ElasticsearchCheckUseCase
+
+	// SUsecase is usecase layer interface about swarmpit check which is injected from package outside (maybe, in main)
+	SUsecase domain.SwarmpitCheckUseCase
+
+	// CUsecase is usecase layer interface about consul check which is injected from package outside (maybe, in main)
+	CUsecase domain.ConsulCheckUseCase
+}
+
+// NewAdminHandler define adminHandler ptr instance & register handling http request to usecase with admin token
+func NewAdminHandler(
+	r router,
+	token string,
+	eu domain.ElasticsearchCheckUseCase,
+	su domain.SwarmpitCheckUseCase,
+	cu domain.ConsulCheckUseCase,
+) {
+	handler := &adminHandler{
+		token:    token,
+		EUsecase: eu,
+		SUsecase: su,
+		CUsecase: cu,
+	}
+
+	r.HandleFunc("/admin/srvcheck/status", handler.getStatuses)
+	r.HandleFunc("/admin/srvcheck/elasticsearch/reset", handler.resetElasticsearch)
+	r.HandleFunc("/admin/srvcheck/swarmpit/reset", handler.resetSwarmpit)
+	r.HandleFunc("/admin/srvcheck/consul/reset", handler.resetConsul)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SERVICE CHECK ADMINISTRATION")
+}
+
+// getStatuses method respond current status & last history of every service check at once
+func (ah *adminHandler) getStatuses(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := map[string]statusResponse{}
+	elasticsearchResp := statusResponse{Status: ah.EUsecase.GetStatus()}
+	if history := ah.EUsecase.GetLastHistory(); history != nil {
+		elasticsearchResp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	resp["elasticsearch"] = elasticsearchResp
+
+	swarmpitResp := statusResponse{Status: ah.SUsecase.GetStatus()}
+	if history := ah.SUsecase.GetLastHistory(); history != nil {
+		swarmpitResp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	resp["swarmpit"] = swarmpitResp
+
+	consulResp := statusResponse{Status: ah.CUsecase.GetStatus()}
+	if history := ah.CUsecase.GetLastHistory(); history != nil {
+		consulResp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	resp["consul"] = consulResp
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// resetElasticsearch method reset status of elasticsearch check to healthy & respond history produced in that reset
+func (ah *adminHandler) resetElasticsearch(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req, ok := decodeResetRequest(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := ah.EUsecase.ResetStatus(r.Context(), req.By, req.Reason)
+	if history == nil {
+		writeResetResponse(w, "", "", "", err)
+		return
+	}
+	writeResetResponse(w, history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// resetSwarmpit method reset status of swarmpit check to healthy & respond history produced in that reset
+func (ah *adminHandler) resetSwarmpit(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req, ok := decodeResetRequest(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := ah.SUsecase.ResetStatus(r.Context(), req.By, req.Reason)
+	if history == nil {
+		writeResetResponse(w, "", "", "", err)
+		return
+	}
+	writeResetResponse(w, history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// resetConsul method reset status of consul check to healthy & respond history produced in that reset
+func (ah *adminHandler) resetConsul(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req, ok := decodeResetRequest(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := ah.CUsecase.ResetStatus(r.Context(), req.By, req.Reason)
+	if history == nil {
+		writeResetResponse(w, "", "", "", err)
+		return
+	}
+	writeResetResponse(w, history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// writeResetResponse write uuid and process level of history produced in reset process as response
+func writeResetResponse(w http.ResponseWriter, uuid, level, message string, err error) {
+	resp := checkResponse{UUID: uuid, ProcessLevel: level, Message: message}
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
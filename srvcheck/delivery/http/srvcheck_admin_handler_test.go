@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+const testToken = "test-admin-token"
+
+// fakeConsulUsecase is ConsulCheckUseCase recording check run, histories query & reset by administrator
+type fakeConsulUsecase struct {
+	domain.ConsulCheckUseCase
+	checked int
+	found   int
+	resetBy string
+	reason  string
+}
+
+func (u *fakeConsulUsecase) GetStatus() string                          { return "HEALTHY" }
+func (u *fakeConsulUsecase) GetLastHistory() *domain.ConsulCheckHistory { return nil }
+
+func (u *fakeConsulUsecase) CheckConsul(context.Context) (*domain.ConsulCheckHistory, error) {
+	u.checked++
+	history := &domain.ConsulCheckHistory{}
+	history.FillPrivateComponent()
+	history.UUID = "uuid-check"
+	history.ProcessLevel.Set("HEALTHY")
+	return history, nil
+}
+
+func (u *fakeConsulUsecase) GetHistory(_ context.Context, uuid string) (*domain.ConsulCheckHistory, error) {
+	u.found++
+	if uuid != "uuid-check" {
+		return nil, nil
+	}
+	history := &domain.ConsulCheckHistory{}
+	history.FillPrivateComponent()
+	history.UUID = uuid
+	return history, nil
+}
+
+func (u *fakeConsulUsecase) FindHistories(context.Context, string, time.Time, time.Time) ([]*domain.ConsulCheckHistory, error) {
+	u.found++
+	return nil, nil
+}
+
+func (u *fakeConsulUsecase) FindLatestHistories(context.Context, int) ([]*domain.ConsulCheckHistory, error) {
+	u.found++
+	return nil, nil
+}
+
+func (u *fakeConsulUsecase) ResetStatus(_ context.Context, by, reason string) (*domain.ConsulCheckHistory, error) {
+	u.resetBy, u.reason = by, reason
+	history := &domain.ConsulCheckHistory{}
+	history.FillPrivateComponent()
+	history.UUID = "uuid-reset"
+	history.ProcessLevel.Set("RESET")
+	return history, nil
+}
+
+type fakeElasticsearchUsecase struct {
+	domain.ElasticsearchCheckUseCase
+}
+
+func (fakeElasticsearchUsecase) GetStatus() string                                 { return "HEALTHY" }
+func (fakeElasticsearchUsecase) GetLastHistory() *domain.ElasticsearchCheckHistory { return nil }
+
+type fakeSwarmpitUsecase struct {
+	domain.SwarmpitCheckUseCase
+}
+
+func (fakeSwarmpitUsecase) GetStatus() string                            { return "HEALTHY" }
+func (fakeSwarmpitUsecase) GetLastHistory() *domain.SwarmpitCheckHistory { return nil }
+
+// serveRequest serve request having method, path, body & token (not set if empty) with mux and return response
+func serveRequest(mux *http.ServeMux, method, path, body, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	return w
+}
+
+func TestAdminHandler(t *testing.T) {
+	const validBody = `{"by": "admin", "reason": "consul agent is fixed"}`
+
+	for _, tc := range []struct {
+		name        string
+		serverToken string
+		method      string
+		path        string
+		token       string
+		body        string
+		code        int
+		reset       bool // if consul check status is reset by request
+	}{
+		{
+			name:        "GET status",
+			serverToken: testToken,
+			method:      http.MethodGet,
+			path:        "/admin/srvcheck/status",
+			token:       testToken,
+			code:        http.StatusOK,
+		}, {
+			name:        "GET status with wrong token",
+			serverToken: testToken,
+			method:      http.MethodGet,
+			path:        "/admin/srvcheck/status",
+			token:       "wrong-token",
+			code:        http.StatusUnauthorized,
+		}, {
+			name:        "POST reset",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/srvcheck/consul/reset",
+			token:       testToken,
+			body:        validBody,
+			code:        http.StatusOK,
+			reset:       true,
+		}, {
+			name:        "POST reset without token",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/srvcheck/consul/reset",
+			body:        validBody,
+			code:        http.StatusUnauthorized,
+		}, {
+			name:        "PUT reset",
+			serverToken: testToken,
+			method:      http.MethodPut,
+			path:        "/admin/srvcheck/consul/reset",
+			token:       testToken,
+			body:        validBody,
+			code:        http.StatusMethodNotAllowed,
+		}, {
+			name:        "POST reset without by",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/srvcheck/consul/reset",
+			token:       testToken,
+			body:        `{"reason": "consul agent is fixed"}`,
+			code:        http.StatusBadRequest,
+		}, {
+			name:        "POST reset while admin API is disabled",
+			serverToken: "",
+			method:      http.MethodPost,
+			path:        "/admin/srvcheck/consul/reset",
+			token:       testToken,
+			body:        validBody,
+			code:        http.StatusUnauthorized,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cu := &fakeConsulUsecase{}
+			mux := http.NewServeMux()
+			NewAdminHandler(mux, tc.serverToken, fakeElasticsearchUsecase{}, fakeSwarmpitUsecase{}, cu)
+
+			w := serveRequest(mux, tc.method, tc.path, tc.body, tc.token)
+			if w.Code != tc.code {
+				t.Errorf("status code should be %d, got: %d, body: %s", tc.code, w.Code, w.Body.String())
+			}
+			if reset := cu.resetBy != ""; reset != tc.reset {
+				t.Errorf("if consul check status is reset should be %t, got: %t", tc.reset, reset)
+			}
+			if tc.reset && (cu.resetBy != "admin" || cu.reason != "consul agent is fixed") {
+				t.Errorf("reset should be delivered with by & reason in body, got: %s, %s", cu.resetBy, cu.reason)
+			}
+		})
+	}
+}
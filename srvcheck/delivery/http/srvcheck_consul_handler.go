@@ -0,0 +1,127 @@
+// in srvcheck_consul_handler.go file, define delivery from http request to consul check usecase handler
+// registered path is prefixed with domain & check name, Ex) /srvcheck/consul/status, /srvcheck/consul/check, /srvcheck/consul/histories
+
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// consulCheckHandler is delivered data handler about consul check using usecase layer
+type consulCheckHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token to run check on demand
+	token string
+
+	// CUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	CUsecase domain.ConsulCheckUseCase
+}
+
+// NewConsulCheckHandler define consulCheckHandler ptr instance & register handling http request to usecase
+func NewConsulCheckHandler(r router, token string, cu domain.ConsulCheckUseCase) {
+	handler := &consulCheckHandler{
+		token:    token,
+		CUsecase: cu,
+	}
+
+	r.HandleFunc("/srvcheck/consul/status", handler.getStatus)
+	r.HandleFunc("/srvcheck/consul/check", handler.runCheck)
+	r.HandleFunc("/srvcheck/consul/histories", handler.getHistories)
+	r.HandleFunc("/srvcheck/consul/histories/", handler.getHistory)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SERVICE CONSUL CHECK")
+}
+
+// getStatus method respond current status of consul check & history produced in last check process
+func (ch *consulCheckHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := statusResponse{Status: ch.CUsecase.GetStatus()}
+	if history := ch.CUsecase.GetLastHistory(); history != nil {
+		resp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runCheck method run consul check immediately & respond uuid and process level of history produced in that process
+// history is stored in repository in usecase layer same as check process run by channel delivery
+// check process can remediate runtime environment, so admin token is required same as admin handler
+func (ch *consulCheckHandler) runCheck(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ch.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	history, err := ch.CUsecase.CheckConsul(newCheckContext())
+	resp := checkResponse{
+		UUID:         history.UUID,
+		ProcessLevel: history.ProcessLevel.String(),
+		Message:      history.Message,
+	}
+	if err != nil {
+		log.Printf("error occurs in CheckConsul, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistories method respond consul check histories stored in time range (filtered by process level), or latest histories
+// Ex) /srvcheck/consul/histories?latest=20, /srvcheck/consul/histories?start=2020-12-01T00:00:00+09:00&level=UNHEALTHY
+func (ch *consulCheckHandler) getHistories(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ch.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	q, ok := parseHistoriesQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var histories []*domain.ConsulCheckHistory
+	var err error
+	if q.Latest != 0 {
+		histories, err = ch.CUsecase.FindLatestHistories(r.Context(), q.Latest)
+	} else {
+		histories, err = ch.CUsecase.FindHistories(r.Context(), q.Level, q.Start, q.End)
+	}
+	if err != nil {
+		log.Printf("error occurs in finding consul check histories, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := historiesResponse{Histories: make([]map[string]interface{}, 0, len(histories))}
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, history.DottedMapWithPrefix(""))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistory method respond consul check history having uuid in path, Ex) /srvcheck/consul/histories/{uuid}
+func (ch *consulCheckHandler) getHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ch.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	uuid := strings.TrimPrefix(r.URL.Path, "/srvcheck/consul/histories/")
+	if uuid == "" || strings.Contains(uuid, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+
+	history, err := ch.CUsecase.GetHistory(r.Context(), uuid)
+	if err != nil {
+		log.Printf("error occurs in GetHistory, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	} else if history == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, history.DottedMapWithPrefix(""))
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestConsulCheckHandlerRunCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		serverToken string
+		method      string
+		token       string
+		code        int
+		checked     bool
+	}{
+		{name: "POST check", serverToken: testToken, method: http.MethodPost, token: testToken, code: http.StatusOK, checked: true},
+		{name: "POST check without token", serverToken: testToken, method: http.MethodPost, code: http.StatusUnauthorized},
+		{name: "POST check with wrong token", serverToken: testToken, method: http.MethodPost, token: "wrong-token", code: http.StatusUnauthorized},
+		{name: "GET check", serverToken: testToken, method: http.MethodGet, token: testToken, code: http.StatusMethodNotAllowed},
+		{name: "POST check while admin API is disabled", serverToken: "", method: http.MethodPost, token: testToken, code: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cu := &fakeConsulUsecase{}
+			mux := http.NewServeMux()
+			NewConsulCheckHandler(mux, tc.serverToken, cu)
+
+			w := serveRequest(mux, tc.method, "/srvcheck/consul/check", "", tc.token)
+			if w.Code != tc.code {
+				t.Errorf("status code should be %d, got: %d, body: %s", tc.code, w.Code, w.Body.String())
+			}
+			if checked := cu.checked != 0; checked != tc.checked {
+				t.Errorf("if consul check is run should be %t, got: %t", tc.checked, checked)
+			}
+		})
+	}
+}
+
+func TestConsulCheckHandlerGetHistories(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		path  string
+		token string
+		code  int
+		found bool
+	}{
+		{name: "GET latest histories", path: "/srvcheck/consul/histories?latest=5", token: testToken, code: http.StatusOK, found: true},
+		{name: "GET histories in time range", path: "/srvcheck/consul/histories?start=2021-03-01T00:00:00Z&end=2021-03-08T00:00:00Z", token: testToken, code: http.StatusOK, found: true},
+		{name: "GET history", path: "/srvcheck/consul/histories/uuid-check", token: testToken, code: http.StatusOK, found: true},
+		{name: "GET histories without token", path: "/srvcheck/consul/histories?latest=5", code: http.StatusUnauthorized},
+		{name: "GET history without token", path: "/srvcheck/consul/histories/uuid-check", code: http.StatusUnauthorized},
+		{name: "GET history with wrong token", path: "/srvcheck/consul/histories/uuid-check", token: "wrong-token", code: http.StatusUnauthorized},
+		{name: "GET histories in time range longer than max range", path: "/srvcheck/consul/histories?start=2021-03-01T00:00:00Z&end=2021-03-08T00:00:01Z", token: testToken, code: http.StatusBadRequest},
+		{name: "GET histories with start after end", path: "/srvcheck/consul/histories?start=2021-03-08T00:00:00Z&end=2021-03-01T00:00:00Z", token: testToken, code: http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cu := &fakeConsulUsecase{}
+			mux := http.NewServeMux()
+			NewConsulCheckHandler(mux, testToken, cu)
+
+			w := serveRequest(mux, http.MethodGet, tc.path, "", tc.token)
+			if w.Code != tc.code {
+				t.Errorf("status code should be %d, got: %d, body: %s", tc.code, w.Code, w.Body.String())
+			}
+			if found := cu.found != 0; found != tc.found {
+				t.Errorf("if consul check histories are queried should be %t, got: %t", tc.found, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// in srvcheck_elasticsearch_handler.go file, define delivery from http request to elasticsearch check usecase handler
+// registered path is prefixed with domain & check name, Ex) /srvcheck/elasticsearch/status, /srvcheck/elasticsearch/check, /srvcheck/elasticsearch/histories
+
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// elasticsearchCheckHandler is delivered data handler about elasticsearch check using usecase layer
+type elasticsearchCheckHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token to run check on demand
+	token string
+
+	// EUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	EUsecase domain.ElasticsearchCheckUseCase
+}
+
+// NewElasticsearchCheckHandler define elasticsearchCheckHandler ptr instance & register handling http request to usecase
+func NewElasticsearchCheckHandler(r router, token string, eu domain.ElasticsearchCheckUseCase) {
+	handler := &elasticsearchCheckHandler{
+		token:    token,
+		EUsecase: eu,
+	}
+
+	r.HandleFunc("/srvcheck/elasticsearch/status", handler.getStatus)
+	r.HandleFunc("/srvcheck/elasticsearch/check", handler.runCheck)
+	r.HandleFunc("/srvcheck/elasticsearch/histories", handler.getHistories)
+	r.HandleFunc("/srvcheck/elasticsearch/histories/", handler.getHistory)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SERVICE ELASTICSEARCH CHECK")
+}
+
+// getStatus method respond current status of elasticsearch check & history produced in last check process
+func (eh *elasticsearchCheckHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := statusResponse{Status: eh.EUsecase.GetStatus()}
+	if history := eh.EUsecase.GetLastHistory(); history != nil {
+		resp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runCheck method run elasticsearch check immediately & respond uuid and process level of history produced in that process
+// history is stored in repository in usecase layer same as check process run by channel delivery
+// check process can remediate runtime environment, so admin token is required same as admin handler
+func (eh *elasticsearchCheckHandler) runCheck(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, eh.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	history, err := eh.EUsecase.CheckElasticsearch(newCheckContext())
+	resp := checkResponse{
+		UUID:         history.UUID,
+		ProcessLevel: history.ProcessLevel.String(),
+		Message:      history.Message,
+	}
+	if err != nil {
+		log.Printf("error occurs in CheckElasticsearch, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistories method respond elasticsearch check histories stored in time range (filtered by process level), or latest histories
+// Ex) /srvcheck/elasticsearch/histories?latest=20, /srvcheck/elasticsearch/histories?start=2020-12-01T00:00:00+09:00&level=UNHEALTHY
+func (eh *elasticsearchCheckHandler) getHistories(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, eh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	q, ok := parseHistoriesQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var histories []*domain.ElasticsearchCheckHistory
+	var err error
+	if q.Latest != 0 {
+		histories, err = eh.EUsecase.FindLatestHistories(r.Context(), q.Latest)
+	} else {
+		histories, err = eh.EUsecase.FindHistories(r.Context(), q.Level, q.Start, q.End)
+	}
+	if err != nil {
+		log.Printf("error occurs in finding elasticsearch check histories, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := historiesResponse{Histories: make([]map[string]interface{}, 0, len(histories))}
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, history.DottedMapWithPrefix(""))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistory method respond elasticsearch check history having uuid in path, Ex) /srvcheck/elasticsearch/histories/{uuid}
+func (eh *elasticsearchCheckHandler) getHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, eh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	uuid := strings.TrimPrefix(r.URL.Path, "/srvcheck/elasticsearch/histories/")
+	if uuid == "" || strings.Contains(uuid, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+
+	history, err := eh.EUsecase.GetHistory(r.Context(), uuid)
+	if err != nil {
+		log.Printf("error occurs in GetHistory, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	} else if history == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, history.DottedMapWithPrefix(""))
+}
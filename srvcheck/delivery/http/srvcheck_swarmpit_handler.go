@@ -0,0 +1,127 @@
+// in srvcheck_swarmpit_handler.go file, define delivery from http request to swarmpit check usecase handler
+// registered path is prefixed with domain & check name, Ex) /srvcheck/swarmpit/status, /srvcheck/swarmpit/check, /srvcheck/swarmpit/histories
+
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// swarmpitCheckHandler is delivered data handler about swarmpit check using usecase layer
+type swarmpitCheckHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token to run check on demand
+	token string
+
+	// SUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	SUsecase domain.SwarmpitCheckUseCase
+}
+
+// NewSwarmpitCheckHandler define swarmpitCheckHandler ptr instance & register handling http request to usecase
+func NewSwarmpitCheckHandler(r router, token string, su domain.SwarmpitCheckUseCase) {
+	handler := &swarmpitCheckHandler{
+		token:    token,
+		SUsecase: su,
+	}
+
+	r.HandleFunc("/srvcheck/swarmpit/status", handler.getStatus)
+	r.HandleFunc("/srvcheck/swarmpit/check", handler.runCheck)
+	r.HandleFunc("/srvcheck/swarmpit/histories", handler.getHistories)
+	r.HandleFunc("/srvcheck/swarmpit/histories/", handler.getHistory)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SERVICE SWARMPIT CHECK")
+}
+
+// getStatus method respond current status of swarmpit check & history produced in last check process
+func (sh *swarmpitCheckHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := statusResponse{Status: sh.SUsecase.GetStatus()}
+	if history := sh.SUsecase.GetLastHistory(); history != nil {
+		resp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runCheck method run swarmpit check immediately & respond uuid and process level of history produced in that process
+// history is stored in repository in usecase layer same as check process run by channel delivery
+// check process can remediate runtime environment, so admin token is required same as admin handler
+func (sh *swarmpitCheckHandler) runCheck(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, sh.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	history, err := sh.SUsecase.CheckSwarmpit(newCheckContext())
+	resp := checkResponse{
+		UUID:         history.UUID,
+		ProcessLevel: history.ProcessLevel.String(),
+		Message:      history.Message,
+	}
+	if err != nil {
+		log.Printf("error occurs in CheckSwarmpit, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistories method respond swarmpit check histories stored in time range (filtered by process level), or latest histories
+// Ex) /srvcheck/swarmpit/histories?latest=20, /srvcheck/swarmpit/histories?start=2020-12-01T00:00:00+09:00&level=UNHEALTHY
+func (sh *swarmpitCheckHandler) getHistories(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, sh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	q, ok := parseHistoriesQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var histories []*domain.SwarmpitCheckHistory
+	var err error
+	if q.Latest != 0 {
+		histories, err = sh.SUsecase.FindLatestHistories(r.Context(), q.Latest)
+	} else {
+		histories, err = sh.SUsecase.FindHistories(r.Context(), q.Level, q.Start, q.End)
+	}
+	if err != nil {
+		log.Printf("error occurs in finding swarmpit check histories, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := historiesResponse{Histories: make([]map[string]interface{}, 0, len(histories))}
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, history.DottedMapWithPrefix(""))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistory method respond swarmpit check history having uuid in path, Ex) /srvcheck/swarmpit/histories/{uuid}
+func (sh *swarmpitCheckHandler) getHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, sh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	uuid := strings.TrimPrefix(r.URL.Path, "/srvcheck/swarmpit/histories/")
+	if uuid == "" || strings.Contains(uuid, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+
+	history, err := sh.SUsecase.GetHistory(r.Context(), uuid)
+	if err != nil {
+		log.Printf("error occurs in GetHistory, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	} else if history == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, history.DottedMapWithPrefix(""))
+}
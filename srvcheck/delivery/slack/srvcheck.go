@@ -0,0 +1,45 @@
+// delivery package is for delivery layer acted as presenter layer in srvcheck domain which decide how the data will presented
+// in delivery type, could be as REST API, gRPC, golang channel, or HTML file, etc ...
+// in slack delivery, deliver data to usecase by receiving slack command & present result as reply text in thread
+
+// srvcheck.go is file that define interface or function used jointly in slack delivery package as private.
+// receiving slack command & replying text is occurred in slack agent, this package only register handler of command.
+
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// commandRouter is interface to register slack command handler with action & target, implemented in slack agent
+type commandRouter interface {
+	// HandleCommand register handler about command consisted of action & target (Ex, action: run, target: consul)
+	HandleCommand(action, target string, handler func(ctx context.Context, user string, args []string) (reply string))
+}
+
+// statusReply return reply text about current status of check process & history produced in last check process
+func statusReply(check, status string, lastUUID, lastLevel, lastMessage string) string {
+	if lastUUID == "" {
+		return fmt.Sprintf("*%s check* is `%s` (not checked yet)", check, status)
+	}
+	return fmt.Sprintf("*%s check* is `%s`, last history - %s: %s (%s)", check, status, lastLevel, lastMessage, lastUUID)
+}
+
+// historyReply return reply text about history produced in check or reset process run by command
+func historyReply(check, action, uuid, level, message string, err error) string {
+	reply := fmt.Sprintf("*%s check* %s result - %s: %s (%s)", check, action, level, message, uuid)
+	if err != nil {
+		reply += fmt.Sprintf("\nerror occurs in %s process, err: %v", action, err)
+	}
+	return reply
+}
+
+// resetReason return reason of reset joined from args & if reason is empty, return false with usage text
+func resetReason(check string, args []string) (reason string, ok bool) {
+	if reason = strings.Join(args, " "); reason == "" {
+		return fmt.Sprintf("please enter reason of reset, usage: `reset %s <reason>`", check), false
+	}
+	return reason, true
+}
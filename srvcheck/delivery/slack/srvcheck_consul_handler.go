@@ -0,0 +1,63 @@
+// in srvcheck_consul_handler.go file, define delivery from slack command to consul check usecase handler
+// registered command is consisted of action & consul target, Ex) status consul, run consul, reset consul <reason>
+
+package slack
+
+import (
+	"context"
+	"log"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// consulCheckHandler is delivered data handler about consul check using usecase layer
+type consulCheckHandler struct {
+	// CUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	CUsecase domain.ConsulCheckUseCase
+}
+
+// NewConsulCheckHandler define consulCheckHandler ptr instance & register handling slack command to usecase
+func NewConsulCheckHandler(r commandRouter, cu domain.ConsulCheckUseCase) {
+	handler := &consulCheckHandler{
+		CUsecase: cu,
+	}
+
+	r.HandleCommand("status", "consul", handler.status)
+	r.HandleCommand("run", "consul", handler.run)
+	r.HandleCommand("reset", "consul", handler.reset)
+	log.Println("START TO HANDLE SLACK COMMAND ABOUT SERVICE CONSUL CHECK")
+}
+
+// status method reply current status of consul check & history produced in last check process
+func (ch *consulCheckHandler) status(_ context.Context, _ string, _ []string) string {
+	if history := ch.CUsecase.GetLastHistory(); history != nil {
+		return statusReply("consul", ch.CUsecase.GetStatus(), history.UUID, history.ProcessLevel.String(), history.Message)
+	}
+	return statusReply("consul", ch.CUsecase.GetStatus(), "", "", "")
+}
+
+// run method run consul check immediately & reply history produced in that process
+func (ch *consulCheckHandler) run(ctx context.Context, _ string, _ []string) string {
+	history, err := ch.CUsecase.CheckConsul(ctx)
+	if err != nil {
+		log.Printf("error occurs in CheckConsul, err: %v", err)
+	}
+	return historyReply("consul", "check", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// reset method reset status of consul check to healthy with reason in args & reply history produced in that reset
+func (ch *consulCheckHandler) reset(ctx context.Context, user string, args []string) string {
+	reason, ok := resetReason("consul", args)
+	if !ok {
+		return reason
+	}
+
+	history, err := ch.CUsecase.ResetStatus(ctx, user, reason)
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+	}
+	if history == nil {
+		return historyReply("consul", "reset", "", "", "", err)
+	}
+	return historyReply("consul", "reset", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
@@ -0,0 +1,63 @@
+// in srvcheck_elasticsearch_handler.go file, define delivery from slack command to elasticsearch check usecase handler
+// registered command is consisted of action & elasticsearch target, Ex) status elasticsearch, run elasticsearch, reset elasticsearch <reason>
+
+package slack
+
+import (
+	"context"
+	"log"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// elasticsearchCheckHandler is delivered data handler about elasticsearch check using usecase layer
+type elasticsearchCheckHandler struct {
+	// EUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	EUsecase domain.ElasticsearchCheckUseCase
+}
+
+// NewElasticsearchCheckHandler define elasticsearchCheckHandler ptr instance & register handling slack command to usecase
+func NewElasticsearchCheckHandler(r commandRouter, eu domain.ElasticsearchCheckUseCase) {
+	handler := &elasticsearchCheckHandler{
+		EUsecase: eu,
+	}
+
+	r.HandleCommand("status", "elasticsearch", handler.status)
+	r.HandleCommand("run", "elasticsearch", handler.run)
+	r.HandleCommand("reset", "elasticsearch", handler.reset)
+	log.Println("START TO HANDLE SLACK COMMAND ABOUT SERVICE ELASTICSEARCH CHECK")
+}
+
+// status method reply current status of elasticsearch check & history produced in last check process
+func (eh *elasticsearchCheckHandler) status(_ context.Context, _ string, _ []string) string {
+	if history := eh.EUsecase.GetLastHistory(); history != nil {
+		return statusReply("elasticsearch", eh.EUsecase.GetStatus(), history.UUID, history.ProcessLevel.String(), history.Message)
+	}
+	return statusReply("elasticsearch", eh.EUsecase.GetStatus(), "", "", "")
+}
+
+// run method run elasticsearch check immediately & reply history produced in that process
+func (eh *elasticsearchCheckHandler) run(ctx context.Context, _ string, _ []string) string {
+	history, err := eh.EUsecase.CheckElasticsearch(ctx)
+	if err != nil {
+		log.Printf("error occurs in CheckElasticsearch, err: %v", err)
+	}
+	return historyReply("elasticsearch", "check", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// reset method reset status of elasticsearch check to healthy with reason in args & reply history produced in that reset
+func (eh *elasticsearchCheckHandler) reset(ctx context.Context, user string, args []string) string {
+	reason, ok := resetReason("elasticsearch", args)
+	if !ok {
+		return reason
+	}
+
+	history, err := eh.EUsecase.ResetStatus(ctx, user, reason)
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+	}
+	if history == nil {
+		return historyReply("elasticsearch", "reset", "", "", "", err)
+	}
+	return historyReply("elasticsearch", "reset", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
@@ -0,0 +1,63 @@
+// in srvcheck_swarmpit_handler.go file, define delivery from slack command to swarmpit check usecase handler
+// registered command is consisted of action & swarmpit target, Ex) status swarmpit, run swarmpit, reset swarmpit <reason>
+
+package slack
+
+import (
+	"context"
+	"log"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// swarmpitCheckHandler is delivered data handler about swarmpit check using usecase layer
+type swarmpitCheckHandler struct {
+	// SUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	SUsecase domain.SwarmpitCheckUseCase
+}
+
+// NewSwarmpitCheckHandler define swarmpitCheckHandler ptr instance & register handling slack command to usecase
+func NewSwarmpitCheckHandler(r commandRouter, su domain.SwarmpitCheckUseCase) {
+	handler := &swarmpitCheckHandler{
+		SUsecase: su,
+	}
+
+	r.HandleCommand("status", "swarmpit", handler.status)
+	r.HandleCommand("run", "swarmpit", handler.run)
+	r.HandleCommand("reset", "swarmpit", handler.reset)
+	log.Println("START TO HANDLE SLACK COMMAND ABOUT SERVICE SWARMPIT CHECK")
+}
+
+// status method reply current status of swarmpit check & history produced in last check process
+func (sh *swarmpitCheckHandler) status(_ context.Context, _ string, _ []string) string {
+	if history := sh.SUsecase.GetLastHistory(); history != nil {
+		return statusReply("swarmpit", sh.SUsecase.GetStatus(), history.UUID, history.ProcessLevel.String(), history.Message)
+	}
+	return statusReply("swarmpit", sh.SUsecase.GetStatus(), "", "", "")
+}
+
+// run method run swarmpit check immediately & reply history produced in that process
+func (sh *swarmpitCheckHandler) run(ctx context.Context, _ string, _ []string) string {
+	history, err := sh.SUsecase.CheckSwarmpit(ctx)
+	if err != nil {
+		log.Printf("error occurs in CheckSwarmpit, err: %v", err)
+	}
+	return historyReply("swarmpit", "check", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// reset method reset status of swarmpit check to healthy with reason in args & reply history produced in that reset
+func (sh *swarmpitCheckHandler) reset(ctx context.Context, user string, args []string) string {
+	reason, ok := resetReason("swarmpit", args)
+	if !ok {
+		return reason
+	}
+
+	history, err := sh.SUsecase.ResetStatus(ctx, user, reason)
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+	}
+	if history == nil {
+		return historyReply("swarmpit", "reset", "", "", "", err)
+	}
+	return historyReply("swarmpit", "reset", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
@@ -14,10 +14,14 @@ import (
 	"fmt"
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/inhies/go-bytesize"
 	"github.com/pkg/errors"
-	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 // esRepositoryComponentConfig is interface contains method to return config value that elasticsearch repository should have
@@ -33,49 +37,624 @@ type esRepositoryComponentConfig interface {
 	IndexReplicaNum() int
 }
 
-// reqBodyWriter is private interface to use as writing []byte for request body
-type reqBodyWriter interface {
-	io.Writer
-	io.WriterTo
+// docEncoder is private interface to use as encoding dotted map of history to nested json for request body
+type docEncoder interface {
+	// Encode encode dotted map to nested json, separating json value step with dot in map key
+	Encode(m map[string]interface{}) ([]byte, error)
+}
+
+// bulkIndexer is private interface to use as indexing history document with bulk API
+type bulkIndexer interface {
+	// Index buffer document to create in index with id, document already existing with same id is not overwritten
+	Index(index, id string, doc []byte) error
+
+	// Update buffer partial document to update document having id in index
+	Update(index, id string, doc []byte) error
+}
+
+// schemaVersion is version of srvcheck index mapping, it should be increased if mapping is changed incompatibly
+// index is created with name having schema version & rollover number as suffix (Ex, {index name}-v1-000001),
+// and index name in config is used as alias of those indices, writing in the last one (write index)
+const schemaVersion = 2
+
+// field mapping used in mapping properties of every history type
+var (
+	keywordField = map[string]interface{}{"type": "keyword"}
+	textField    = map[string]interface{}{"type": "text", "fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256}}}
+	dateField    = map[string]interface{}{"type": "date"}
+	booleanField = map[string]interface{}{"type": "boolean"}
+	longField    = map[string]interface{}{"type": "long"}
+	integerField = map[string]interface{}{"type": "integer"}
+	doubleField  = map[string]interface{}{"type": "double"}
+)
+
+// commonMappingProperties is mapping properties about fields of serviceCheckHistoryComponent, used in every history type
+var commonMappingProperties = map[string]interface{}{
+	"version":            keywordField,
+	"agent":              keywordField,
+	"@timestamp":         dateField,
+	"domain":             keywordField,
+	"type":               keywordField,
+	"uuid":               keywordField,
+	"process_level":      keywordField,
+	"message":            textField,
+	"error":              textField,
+	"alerted":            booleanField,
+	"alarm_text":         textField,
+	"alarm_time":         dateField,
+	"alarm_error":        textField,
+	"reset_by":           keywordField,
+	"reset_reason":       textField,
+	"maintenance_window": keywordField,
+}
+
+// indexMapping return mapping of srvcheck index, having common mapping properties & mapping properties of every history type
+// field not defined in mapping is kept in source but not indexed, so that document is not rejected by type conflict
+// so every field in dotted map of history should be defined in mapping properties of history type to be searched
+func indexMapping() map[string]interface{} {
+	properties := map[string]interface{}{}
+	for _, typeProperties := range []map[string]interface{}{commonMappingProperties, elasticsearchCheckMappingProperties, swarmpitCheckMappingProperties, consulCheckMappingProperties} {
+		for field, property := range typeProperties {
+			properties[field] = property
+		}
+	}
+
+	return map[string]interface{}{
+		"dynamic":    false,
+		"_meta":      map[string]interface{}{"schema_version": schemaVersion},
+		"properties": properties,
+	}
+}
+
+// reindexScript convert field of document indexed in previous schema version into the type defined in mapping
+// byte size formatted with unit (Ex, 2.00GB) is converted into number of bytes, keeping formatted one as human readable companion
+// number part having no digit or more than one dot (Ex, ".", "1.2.3") is converted into null, as it can't be parsed
+const reindexScript = `if (ctx._source.alarm_error instanceof Map) { ctx._source.alarm_error = null; }
+for (String f : params.bytesize_fields) {
+	def v = ctx._source[f];
+	if (!(v instanceof String)) { continue; }
+	String s = v.trim();
+	int i = 0; int digits = 0; int dots = 0;
+	for (; i < s.length() && (Character.isDigit(s.charAt(i)) || s.charAt(i) == (char)'.'); i++) {
+		if (s.charAt(i) == (char)'.') { dots++; } else { digits++; }
+	}
+	def unit = params.bytesize_units[s.substring(i).trim().toUpperCase()];
+	ctx._source[f + '_human'] = s;
+	ctx._source[f] = (digits == 0 || dots > 1 || unit == null) ? null : (long)(Double.parseDouble(s.substring(0, i)) * unit);
+}`
+
+// bytesizeUnits is number of bytes per unit used in formatted byte size, passed to reindexScript as parameter
+var bytesizeUnits = map[string]interface{}{
+	"B":  uint64(bytesize.B),
+	"KB": uint64(bytesize.KB),
+	"MB": uint64(bytesize.MB),
+	"GB": uint64(bytesize.GB),
+	"TB": uint64(bytesize.TB),
+	"PB": uint64(bytesize.PB),
+	"EB": uint64(bytesize.EB),
+}
+
+// bytesizeFields return byte size fields in index mapping, which are fields having human readable companion (Ex, {field}_human)
+func bytesizeFields() (fields []string) {
+	properties := indexMapping()["properties"].(map[string]interface{})
+	for field := range properties {
+		if _, ok := properties[field+"_human"]; ok {
+			fields = append(fields, field)
+		}
+	}
+	return
 }
 
 // esRepositoryMigrator is struct that Migrate es repository using parameter variable
 type esRepositoryMigrator struct {}
 
-// Migrate method, if index doesn't exist, create index with name and shard number in esRepositoryComponentConfig
-func (erm esRepositoryMigrator) Migrate(cfg esRepositoryComponentConfig, cli *elasticsearch.Client, w reqBodyWriter) error {
-	resp, err := (esapi.IndicesExistsRequest{
-		Index: []string{cfg.IndexName()},
+// Migrate method create first index of current schema version with mapping if alias doesn't point index of that version
+// after that, documents in indices which alias points (or legacy index having alias name) are reindexed into that index,
+// and alias is moved to that index atomically. if alias already points indices of current version, mapping is applied
+func (erm esRepositoryMigrator) Migrate(cfg esRepositoryComponentConfig, cli *elasticsearch.Client, e docEncoder) error {
+	alias := cfg.IndexName()
+	prefix := versionedIndexPrefix(alias)
+
+	current, err := aliasIndices(context.Background(), cli, alias)
+	if err != nil {
+		return err
+	}
+
+	for index := range current {
+		if strings.HasPrefix(index, prefix) {
+			return erm.putMapping(cli, prefix+"*")
+		}
+	}
+
+	index := prefix + "000001"
+	exists, err := indexExists(cli, index)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := erm.createIndex(cfg, cli, e, index); err != nil {
+			return err
+		}
+	} else if err := erm.putMapping(cli, index); err != nil {
+		return err
+	}
+
+	return erm.moveAlias(cli, alias, index, current)
+}
+
+// createIndex create index with settings & mapping of index
+func (erm esRepositoryMigrator) createIndex(cfg esRepositoryComponentConfig, cli *elasticsearch.Client, e docEncoder, index string) error {
+	body := map[string]interface{}{}
+	body["settings"] = indexSettings(cfg)
+	body["mappings"] = indexMapping()
+
+	b, err := e.Encode(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode index body")
+	}
+
+	resp, err := (esapi.IndicesCreateRequest{
+		Index:         index,
+		Body:          bytes.NewReader(b),
+		MasterTimeout: time.Second * 5,
+		Timeout:       time.Second * 5,
+	}).Do(context.Background(), cli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesCreate, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("IndicesCreate return error code, resp: %+v", resp)
+	}
+	return nil
+}
+
+// putMapping apply index mapping to index, adding new fields. mapping changed incompatibly needs new schema version
+func (erm esRepositoryMigrator) putMapping(cli *elasticsearch.Client, index string) error {
+	b, _ := json.Marshal(indexMapping())
+
+	resp, err := (esapi.IndicesPutMappingRequest{
+		Index:         []string{index},
+		Body:          bytes.NewReader(b),
+		MasterTimeout: time.Second * 5,
+		Timeout:       time.Second * 5,
+	}).Do(context.Background(), cli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesPutMapping, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("IndicesPutMapping return error code, increase schema version if mapping is incompatible, resp: %+v", resp)
+	}
+	return nil
+}
+
+// moveAlias reindex documents in current indices of alias (or legacy index named alias) into index, and move alias to index
+// legacy index is removed to free its name for alias after cloned into backup index, and indices of alias are kept as backup
+func (erm esRepositoryMigrator) moveAlias(cli *elasticsearch.Client, alias, index string, current map[string]bool) error {
+	var actions []interface{}
+	if len(current) == 0 {
+		legacy, err := indexExists(cli, alias)
+		if err != nil {
+			return err
+		}
+		if legacy {
+			if err := backupLegacy(cli, alias); err != nil {
+				return err
+			}
+			if err := reindex(cli, alias, index); err != nil {
+				return err
+			}
+			actions = append(actions, map[string]interface{}{"remove_index": map[string]interface{}{"index": alias}})
+		}
+	}
+
+	for old := range current {
+		if err := reindex(cli, old, index); err != nil {
+			return err
+		}
+		actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": old, "alias": alias}})
+	}
+	actions = append(actions, map[string]interface{}{"add": map[string]interface{}{"index": index, "alias": alias, "is_write_index": true}})
+
+	b, _ := json.Marshal(map[string]interface{}{"actions": actions})
+	resp, err := (esapi.IndicesUpdateAliasesRequest{
+		Body:          bytes.NewReader(b),
+		MasterTimeout: time.Second * 5,
+		Timeout:       time.Second * 5,
+	}).Do(context.Background(), cli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesUpdateAliases, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("IndicesUpdateAliases return error code, resp: %+v", resp)
+	}
+	return nil
+}
+
+// backupLegacy clone legacy index named alias into backup index with original documents, as reindex drops unparsable values
+// legacy index is blocked from writing first as clone requires, and backup index is left to operator to delete
+func backupLegacy(cli *elasticsearch.Client, alias string) error {
+	backup := legacyBackupIndex(alias)
+	exists, err := indexExists(cli, backup)
+	if err != nil || exists {
+		return err
+	}
+
+	resp, err := (esapi.IndicesPutSettingsRequest{
+		Index:         []string{alias},
+		Body:          strings.NewReader(`{"index.blocks.write": true}`),
+		MasterTimeout: time.Second * 5,
+	}).Do(context.Background(), cli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesPutSettings, resp: %+v", resp))
+	}
+	_ = resp.Body.Close()
+	if resp.IsError() {
+		return errors.Errorf("IndicesPutSettings return error code, resp: %+v", resp)
+	}
+
+	resp, err = (esapi.IndicesCloneRequest{
+		Index:         alias,
+		Target:        backup,
+		MasterTimeout: time.Second * 5,
+		Timeout:       time.Second * 30,
 	}).Do(context.Background(), cli)
 
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesExists, resp: %+v", resp))
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesClone, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("IndicesClone return error code, resp: %+v", resp)
+	}
+
+	log.Printf("cloned legacy index %s into %s as backup", alias, backup)
+	return nil
+}
+
+// legacyBackupIndex return name of index keeping documents of legacy index named alias after migration
+func legacyBackupIndex(alias string) string {
+	return alias + "-legacy"
+}
+
+// versionedIndexPrefix return prefix of indices having current schema version, rollover number is added after prefix
+func versionedIndexPrefix(alias string) string {
+	return fmt.Sprintf("%s-v%d-", alias, schemaVersion)
+}
+
+// isPreviousVersionIndex return if index is versioned index of alias having schema version older than current one
+func isPreviousVersionIndex(alias, index string) bool {
+	if !strings.HasPrefix(index, alias+"-v") {
+		return false
+	}
+	version := strings.SplitN(strings.TrimPrefix(index, alias+"-v"), "-", 2)[0]
+	v, err := strconv.Atoi(version)
+	return err == nil && v < schemaVersion
+}
+
+// indexSettings return settings of index with shard number & replica number in esRepositoryComponentConfig
+// malformed value is ignored instead of rejecting whole document
+func indexSettings(cfg esRepositoryComponentConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"number_of_shards":         cfg.IndexShardNum(),
+		"number_of_replicas":       cfg.IndexReplicaNum(),
+		"mapping.ignore_malformed": true,
+	}
+}
+
+// aliasIndices return indices which alias points with if each index is write index of alias, empty if alias doesn't exist
+func aliasIndices(ctx context.Context, cli *elasticsearch.Client, alias string) (indices map[string]bool, err error) {
+	resp, err := (esapi.IndicesGetAliasRequest{
+		Name: []string{alias},
+	}).Do(ctx, cli)
+
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to call IndicesGetAlias, resp: %+v", resp))
 	}
+	defer func() { _ = resp.Body.Close() }()
 
+	indices = map[string]bool{}
 	if resp.StatusCode == http.StatusNotFound {
-		body := map[string]interface{}{}
-		body["settings.number_of_shards"] = cfg.IndexShardNum()
-		body["settings.number_of_replicas"] = cfg.IndexReplicaNum()
+		return indices, nil
+	} else if resp.IsError() {
+		return nil, errors.Errorf("IndicesGetAlias return error code, resp: %+v", resp)
+	}
+
+	result := map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex *bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode IndicesGetAlias response body")
+	}
+
+	// alias pointing only one index regard that index as write index if is_write_index is not set
+	for index, r := range result {
+		isWriteIndex := r.Aliases[alias].IsWriteIndex
+		indices[index] = (isWriteIndex != nil && *isWriteIndex) || (isWriteIndex == nil && len(result) == 1)
+	}
+	return indices, nil
+}
+
+// indexExists return if index (or alias) exists in elasticsearch
+func indexExists(cli *elasticsearch.Client, index string) (bool, error) {
+	resp, err := (esapi.IndicesExistsRequest{
+		Index: []string{index},
+	}).Do(context.Background(), cli)
+
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("failed to call IndicesExists, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// reindex copy documents from source index to dest index, document already existing in dest index is skipped
+func reindex(cli *elasticsearch.Client, source, dest string) error {
+	body := map[string]interface{}{
+		"conflicts": "proceed",
+		"source":    map[string]interface{}{"index": source},
+		"dest":      map[string]interface{}{"index": dest, "op_type": "create"},
+		"script": map[string]interface{}{
+			"lang":   "painless",
+			"source": reindexScript,
+			"params": map[string]interface{}{"bytesize_fields": bytesizeFields(), "bytesize_units": bytesizeUnits},
+		},
+	}
+	b, _ := json.Marshal(body)
+
+	wait, refresh := true, true
+	resp, err := (esapi.ReindexRequest{
+		Body:              bytes.NewReader(b),
+		WaitForCompletion: &wait,
+		Refresh:           &refresh,
+	}).Do(context.Background(), cli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call Reindex, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("Reindex return error code, resp: %+v", resp)
+	}
+
+	result := struct {
+		Failures []interface{} `json:"failures"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return errors.Wrap(err, "failed to decode Reindex response body")
+	} else if len(result.Failures) != 0 {
+		return errors.Errorf("failed to reindex %d documents from %s to %s, failures: %v", len(result.Failures), source, dest, result.Failures)
+	}
+
+	log.Printf("reindexed documents from %s to %s", source, dest)
+	return nil
+}
+
+// updateAlarmResult update alarm result fields of history document having uuid as document ID in index
+// update is buffered in bulk indexer behind history document, so it is applied even if document is not indexed yet
+// update is sent to index including document if found, as document in index rolled over can't be updated through alias
+func updateAlarmResult(cli *elasticsearch.Client, bi bulkIndexer, alias, uuid string, t time.Time, text string, alarmErr error) error {
+	doc := map[string]interface{}{
+		"alerted":     true,
+		"alarm_error": nil,
+	}
+	if alarmErr != nil {
+		doc["alarm_error"] = alarmErr.Error()
+	}
+	// alarm time & text are kept if text is empty, as alert is sent to another notifier than one recorded in history
+	if text != "" {
+		doc["alarm_time"], doc["alarm_text"] = t, text
+	}
+	b, _ := json.Marshal(doc)
+
+	index, err := indexOfDocument(cli, alias, uuid)
+	if err != nil {
+		log.Printf("failed to find index of history document, update it through alias, uuid: %s, err: %v", uuid, err)
+	}
+	if index == "" {
+		index = alias
+	}
+
+	if err := bi.Update(index, uuid, b); err != nil {
+		return errors.Wrap(err, "failed to buffer alarm result update in bulk indexer")
+	}
+	return nil
+}
+
+// indexOfDocument return name of index including document having id among indices which alias points, empty if not found
+func indexOfDocument(cli *elasticsearch.Client, alias, id string) (index string, err error) {
+	body := map[string]interface{}{
+		"query":   map[string]interface{}{"ids": map[string]interface{}{"values": []string{id}}},
+		"_source": false,
+	}
+	b, _ := json.Marshal(body)
+
+	size := 1
+	resp, err := (esapi.SearchRequest{
+		Index: []string{alias},
+		Body:  bytes.NewReader(b),
+		Size:  &size,
+	}).Do(context.Background(), cli)
+
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to call SearchRequest, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return "", errors.Errorf("SearchRequest return error code, resp: %+v", resp)
+	}
+
+	result := struct {
+		Hits struct {
+			Hits []struct {
+				Index string `json:"_index"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", errors.Wrap(err, "failed to decode search response body")
+	} else if len(result.Hits.Hits) == 0 {
+		return "", nil
+	}
+	return result.Hits.Hits[0].Index, nil
+}
+
+// searchScrollSize is number of history document fetched in one search or scroll request
+const searchScrollSize = 1000
+
+// searchResult is struct used for decoding response body of search or scroll request
+type searchResult struct {
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Hits []struct {
+			Source map[string]interface{} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// searchByTimeRange return source of history documents having type & timestamp between start & end in order of time
+func searchByTimeRange(ctx context.Context, cli *elasticsearch.Client, index, _type string, start, end time.Time) (sources []map[string]interface{}, err error) {
+	return searchByFilters(ctx, cli, index, typeFilter(_type), timeRangeFilter(start, end))
+}
+
+// searchByProcessLevel return source of history documents having type, process level & timestamp between start & end in order of time
+func searchByProcessLevel(ctx context.Context, cli *elasticsearch.Client, index, _type, level string, start, end time.Time) (sources []map[string]interface{}, err error) {
+	return searchByFilters(ctx, cli, index, typeFilter(_type), timeRangeFilter(start, end), processLevelFilter(level))
+}
+
+// searchLatest return source of latest n history documents matched with every filter in order of newest first
+func searchLatest(ctx context.Context, cli *elasticsearch.Client, index string, n int, filters ...interface{}) (sources []map[string]interface{}, err error) {
+	body := map[string]interface{}{
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": filters}},
+		"sort":  []interface{}{map[string]interface{}{"@timestamp": "desc"}},
+	}
+	b, _ := json.Marshal(body)
+
+	resp, err := (esapi.SearchRequest{
+		Index: []string{index},
+		Body:  bytes.NewReader(b),
+		Size:  &n,
+	}).Do(ctx, cli)
+
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to call SearchRequest, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return nil, errors.Errorf("SearchRequest return error code, resp: %+v", resp)
+	}
+
+	result := searchResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode search response body")
+	}
+	for _, hit := range result.Hits.Hits {
+		sources = append(sources, hit.Source)
+	}
+	return
+}
+
+// typeFilter return filter matching history document having type
+func typeFilter(_type string) interface{} {
+	return map[string]interface{}{"match": map[string]interface{}{"type": _type}}
+}
+
+// timeRangeFilter return filter matching history document having timestamp between start (inclusive) & end (exclusive)
+func timeRangeFilter(start, end time.Time) interface{} {
+	return map[string]interface{}{"range": map[string]interface{}{"@timestamp": map[string]interface{}{"gte": start, "lt": end}}}
+}
+
+// idFilter return filter matching history document having id (uuid)
+func idFilter(id string) interface{} {
+	return map[string]interface{}{"ids": map[string]interface{}{"values": []string{id}}}
+}
+
+// processLevelFilter return filter matching history document having level in process level
+// process level is stored as levels joined with " | ", so regexp matching one of joined levels is used
+func processLevelFilter(level string) interface{} {
+	escaped := &strings.Builder{}
+	for _, r := range level {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return map[string]interface{}{"regexp": map[string]interface{}{"process_level": `(.* \| )?` + escaped.String() + `( \| .*)?`}}
+}
+
+// searchByFilters return source of history documents matched with every filter in order of time
+// documents are fetched with scroll API, so that result is not limited by max result window of index
+func searchByFilters(ctx context.Context, cli *elasticsearch.Client, index string, filters ...interface{}) (sources []map[string]interface{}, err error) {
+	body := map[string]interface{}{
+		"query": map[string]interface{}{"bool": map[string]interface{}{"filter": filters}},
+		"sort":  []interface{}{map[string]interface{}{"@timestamp": "asc"}},
+	}
+	b, _ := json.Marshal(body)
+
+	size := searchScrollSize
+	var req esapi.Request = esapi.SearchRequest{
+		Index:  []string{index},
+		Body:   bytes.NewReader(b),
+		Size:   &size,
+		Scroll: time.Minute,
+	}
+
+	var scrollID string
+	defer func() {
+		if scrollID == "" {
+			return
+		}
+		resp, err := (esapi.ClearScrollRequest{ScrollID: []string{scrollID}}).Do(context.Background(), cli)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
 
-		b, _ := json.Marshal(body)
-		if _, err := w.Write(b); err != nil {
-			return errors.Wrap(err, "failed to write map to body writer")
+	for {
+		result, err := searchPage(ctx, cli, req)
+		if err != nil {
+			return nil, err
 		}
 
-		buf := &bytes.Buffer{}
-		if _, err := w.WriteTo(buf); err != nil {
-			return errors.Wrap(err, "failed to body writer WriteTo method")
+		scrollID = result.ScrollID
+		if len(result.Hits.Hits) == 0 {
+			return sources, nil
+		}
+		for _, hit := range result.Hits.Hits {
+			sources = append(sources, hit.Source)
 		}
 
-		if resp, err := (esapi.IndicesCreateRequest{
-			Index:         cfg.IndexName(),
-			Body:          bytes.NewReader(buf.Bytes()),
-			MasterTimeout: time.Second * 5,
-			Timeout:       time.Second * 5,
-		}).Do(context.Background(), cli); err != nil {
-			return errors.Wrap(err, fmt.Sprintf("failed to call IndicesCreate, resp: %+v", resp))
+		req = esapi.ScrollRequest{
+			ScrollID: scrollID,
+			Scroll:   time.Minute,
 		}
 	}
+}
+
+// searchPage call search or scroll request & return decoded page of hits, response body is closed in any case
+func searchPage(ctx context.Context, cli *elasticsearch.Client, req esapi.Request) (result searchResult, err error) {
+	resp, err := req.Do(ctx, cli)
+	if err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("failed to call %T, resp: %+v", req, resp))
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
 
-	return nil
+	if resp.IsError() {
+		err = errors.Errorf("%T return error code, resp: %+v", req, resp)
+		return
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		err = errors.Wrap(err, "failed to decode search response body")
+	}
+	return
 }
@@ -5,12 +5,8 @@
 package elasticsearch
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/pkg/errors"
 	"log"
 	"time"
@@ -29,8 +25,11 @@ type esConsulCheckHistoryRepository struct {
 	// esCli is elasticsearch client connection injected from the outside package
 	esCli *elasticsearch.Client
 
-	// reqBodyWriter is implementation of reqBodyWriter interface to write []byte for request body
-	reqBodyWriter reqBodyWriter
+	// bulkIndexer is used for indexing history document with bulk API, injected from the outside package
+	bulkIndexer bulkIndexer
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to request body
+	docEncoder docEncoder
 }
 
 // esConsulCheckHistoryRepoConfig is the config for consul check history repository using elasticsearch
@@ -39,16 +38,28 @@ type esConsulCheckHistoryRepoConfig interface {
 	esRepositoryComponentConfig
 }
 
+// consulCheckMappingProperties is mapping properties about fields of ConsulCheckHistory, merged into index mapping
+var consulCheckMappingProperties = map[string]interface{}{
+	"instances_per_service":       map[string]interface{}{"type": "object", "dynamic": true},
+	"if_instance_deregistered":    booleanField,
+	"deregistered_instances":      keywordField,
+	"deregister_failed_instances": keywordField,
+	"if_container_restarted":      booleanField,
+	"restarted_containers":        keywordField,
+}
+
 // NewESConsulCheckHistoryRepository return new object that implement ConsulCheckHistoryRepository interface
 func NewESConsulCheckHistoryRepository(
 	cfg esConsulCheckHistoryRepoConfig,
 	cli *elasticsearch.Client,
-	w reqBodyWriter,
+	bi bulkIndexer,
+	e docEncoder,
 ) domain.ConsulCheckHistoryRepository {
 	repo := &esConsulCheckHistoryRepository{
-		myCfg:         cfg,
-		esCli:         cli,
-		reqBodyWriter: w,
+		myCfg:       cfg,
+		esCli:       cli,
+		bulkIndexer: bi,
+		docEncoder:  e,
 	}
 
 	if err := repo.Migrate(); err != nil {
@@ -60,39 +71,76 @@ func NewESConsulCheckHistoryRepository(
 
 // Implement Migrate method of ConsulCheckHistoryRepository interface
 func (ecr *esConsulCheckHistoryRepository) Migrate() error {
-	return ecr.esMigrator.Migrate(ecr.myCfg, ecr.esCli, ecr.reqBodyWriter)
+	return ecr.esMigrator.Migrate(ecr.myCfg, ecr.esCli, ecr.docEncoder)
 }
 
 // Implement Store method of ConsulCheckHistoryRepository interface
 func (ecr *esConsulCheckHistoryRepository) Store(history *domain.ConsulCheckHistory) (b []byte, err error) {
-	body, _ := json.Marshal(history.DottedMapWithPrefix(""))
-	if _, err = ecr.reqBodyWriter.Write(body); err != nil {
-		err = errors.Wrap(err, "failed to write map to body writer")
+	body, err := ecr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
 		return
 	}
 
-	buf := &bytes.Buffer{}
-	if _, err = ecr.reqBodyWriter.WriteTo(buf); err != nil {
-		err = errors.Wrap(err, "failed to body writer WriteTo method")
+	// history document is buffered & indexed later with bulk API, and spooled in disk if elasticsearch is unavailable
+	if err = ecr.bulkIndexer.Index(ecr.myCfg.IndexName(), history.UUID, body); err != nil {
+		err = errors.Wrap(err, "failed to buffer history document in bulk indexer")
+	}
+	return
+}
+
+// Implement UpdateAlarmResult method of ConsulCheckHistoryRepository interface
+func (ecr *esConsulCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return updateAlarmResult(ecr.esCli, ecr.bulkIndexer, ecr.myCfg.IndexName(), uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of ConsulCheckHistoryRepository interface
+func (ecr *esConsulCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.ConsulCheckHistory, err error) {
+	sources, err := searchByTimeRange(ctx, ecr.esCli, ecr.myCfg.IndexName(), "ConsulCheck", start, end)
+	if err != nil {
 		return
 	}
+	histories = consulCheckHistories(sources)
+	return
+}
 
-	resp, err := (esapi.IndexRequest{
-		Index:        ecr.myCfg.IndexName(),
-		Body:         bytes.NewReader(buf.Bytes()),
-		Timeout:      time.Second * 5,
-	}).Do(context.Background(), ecr.esCli)
+// Implement GetByUUID method of ConsulCheckHistoryRepository interface
+func (ecr *esConsulCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.ConsulCheckHistory, err error) {
+	sources, err := searchLatest(ctx, ecr.esCli, ecr.myCfg.IndexName(), 1, typeFilter("ConsulCheck"), idFilter(uuid))
+	if err != nil || len(sources) == 0 {
+		return
+	}
+	history = consulCheckHistories(sources)[0]
+	return
+}
 
+// Implement FindByProcessLevel method of ConsulCheckHistoryRepository interface
+func (ecr *esConsulCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.ConsulCheckHistory, err error) {
+	sources, err := searchByProcessLevel(ctx, ecr.esCli, ecr.myCfg.IndexName(), "ConsulCheck", level, start, end)
 	if err != nil {
-		err = errors.Wrap(err, fmt.Sprintf("failed to call IndexRequest, resp: %+v", resp))
 		return
-	} else if resp.IsError() {
-		err = errors.Errorf("IndexRequest return error code, resp: %+v", resp)
+	}
+	histories = consulCheckHistories(sources)
+	return
+}
+
+// Implement FindLatest method of ConsulCheckHistoryRepository interface
+func (ecr *esConsulCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.ConsulCheckHistory, err error) {
+	sources, err := searchLatest(ctx, ecr.esCli, ecr.myCfg.IndexName(), n, typeFilter("ConsulCheck"))
+	if err != nil {
 		return
 	}
+	histories = consulCheckHistories(sources)
+	return
+}
 
-	result := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&result)
-	b, _ = json.Marshal(result)
+// consulCheckHistories return ConsulCheckHistory slice restored from sources of history documents
+func consulCheckHistories(sources []map[string]interface{}) (histories []*domain.ConsulCheckHistory) {
+	histories = make([]*domain.ConsulCheckHistory, 0, len(sources))
+	for _, source := range sources {
+		history := new(domain.ConsulCheckHistory)
+		history.FillFromDottedMap(source)
+		histories = append(histories, history)
+	}
 	return
 }
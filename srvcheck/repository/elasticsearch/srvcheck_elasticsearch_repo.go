@@ -5,12 +5,8 @@
 package elasticsearch
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/pkg/errors"
 	"log"
 	"time"
@@ -29,8 +25,11 @@ type esElasticsearchCheckHistoryRepository struct {
 	// esCli is elasticsearch client connection injected from the outside package
 	esCli *elasticsearch.Client
 
-	// reqBodyWriter is implementation of reqBodyWriter interface to write []byte for request body
-	reqBodyWriter reqBodyWriter
+	// bulkIndexer is used for indexing history document with bulk API, injected from the outside package
+	bulkIndexer bulkIndexer
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to request body
+	docEncoder docEncoder
 }
 
 // esElasticsearchCheckHistoryRepoConfig is the config for elasticsearch check history repository using elasticsearch
@@ -39,16 +38,28 @@ type esElasticsearchCheckHistoryRepoConfig interface {
 	esRepositoryComponentConfig
 }
 
+// elasticsearchCheckMappingProperties is mapping properties about fields of ElasticsearchCheckHistory, merged into index mapping
+var elasticsearchCheckMappingProperties = map[string]interface{}{
+	"active_primary_shards":   integerField,
+	"active_shards":           integerField,
+	"unassigned_shards":       integerField,
+	"active_shards_percent":   doubleField,
+	"if_jaeger_index_deleted": booleanField,
+	"deleted_jaeger_indices":  keywordField,
+}
+
 // NewESElasticsearchCheckHistoryRepository return new object that implement ElasticsearchCheckHistoryRepository interface
 func NewESElasticsearchCheckHistoryRepository(
 	cfg esElasticsearchCheckHistoryRepoConfig,
 	cli *elasticsearch.Client,
-	w reqBodyWriter,
+	bi bulkIndexer,
+	e docEncoder,
 ) domain.ElasticsearchCheckHistoryRepository {
 	repo := &esElasticsearchCheckHistoryRepository{
-		myCfg:         cfg,
-		esCli:         cli,
-		reqBodyWriter: w,
+		myCfg:       cfg,
+		esCli:       cli,
+		bulkIndexer: bi,
+		docEncoder:  e,
 	}
 
 	if err := repo.Migrate(); err != nil {
@@ -60,39 +71,76 @@ func NewESElasticsearchCheckHistoryRepository(
 
 // Implement Migrate method of ElasticsearchCheckHistoryRepository interface
 func (eer *esElasticsearchCheckHistoryRepository) Migrate() error {
-	return eer.esMigrator.Migrate(eer.myCfg, eer.esCli, eer.reqBodyWriter)
+	return eer.esMigrator.Migrate(eer.myCfg, eer.esCli, eer.docEncoder)
 }
 
 // Implement Store method of ElasticsearchCheckHistoryRepository interface
 func (eer *esElasticsearchCheckHistoryRepository) Store(history *domain.ElasticsearchCheckHistory) (b []byte, err error) {
-	body, _ := json.Marshal(history.DottedMapWithPrefix(""))
-	if _, err = eer.reqBodyWriter.Write(body); err != nil {
-		err = errors.Wrap(err, "failed to write map to body writer")
+	body, err := eer.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
 		return
 	}
 
-	buf := &bytes.Buffer{}
-	if _, err = eer.reqBodyWriter.WriteTo(buf); err != nil {
-		err = errors.Wrap(err, "failed to body writer WriteTo method")
+	// history document is buffered & indexed later with bulk API, and spooled in disk if elasticsearch is unavailable
+	if err = eer.bulkIndexer.Index(eer.myCfg.IndexName(), history.UUID, body); err != nil {
+		err = errors.Wrap(err, "failed to buffer history document in bulk indexer")
+	}
+	return
+}
+
+// Implement UpdateAlarmResult method of ElasticsearchCheckHistoryRepository interface
+func (eer *esElasticsearchCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return updateAlarmResult(eer.esCli, eer.bulkIndexer, eer.myCfg.IndexName(), uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of ElasticsearchCheckHistoryRepository interface
+func (eer *esElasticsearchCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	sources, err := searchByTimeRange(ctx, eer.esCli, eer.myCfg.IndexName(), "ElasticsearchCheck", start, end)
+	if err != nil {
 		return
 	}
+	histories = elasticsearchCheckHistories(sources)
+	return
+}
 
-	resp, err := (esapi.IndexRequest{
-		Index:        eer.myCfg.IndexName(),
-		Body:         bytes.NewReader(buf.Bytes()),
-		Timeout:      time.Second * 5,
-	}).Do(context.Background(), eer.esCli)
+// Implement GetByUUID method of ElasticsearchCheckHistoryRepository interface
+func (eer *esElasticsearchCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.ElasticsearchCheckHistory, err error) {
+	sources, err := searchLatest(ctx, eer.esCli, eer.myCfg.IndexName(), 1, typeFilter("ElasticsearchCheck"), idFilter(uuid))
+	if err != nil || len(sources) == 0 {
+		return
+	}
+	history = elasticsearchCheckHistories(sources)[0]
+	return
+}
 
+// Implement FindByProcessLevel method of ElasticsearchCheckHistoryRepository interface
+func (eer *esElasticsearchCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	sources, err := searchByProcessLevel(ctx, eer.esCli, eer.myCfg.IndexName(), "ElasticsearchCheck", level, start, end)
 	if err != nil {
-		err = errors.Wrap(err, fmt.Sprintf("failed to call IndexRequest, resp: %+v", resp))
 		return
-	} else if resp.IsError() {
-		err = errors.Errorf("IndexRequest return error code, resp: %+v", resp)
+	}
+	histories = elasticsearchCheckHistories(sources)
+	return
+}
+
+// Implement FindLatest method of ElasticsearchCheckHistoryRepository interface
+func (eer *esElasticsearchCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	sources, err := searchLatest(ctx, eer.esCli, eer.myCfg.IndexName(), n, typeFilter("ElasticsearchCheck"))
+	if err != nil {
 		return
 	}
+	histories = elasticsearchCheckHistories(sources)
+	return
+}
 
-	result := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&result)
-	b, _ = json.Marshal(result)
+// elasticsearchCheckHistories return ElasticsearchCheckHistory slice restored from sources of history documents
+func elasticsearchCheckHistories(sources []map[string]interface{}) (histories []*domain.ElasticsearchCheckHistory) {
+	histories = make([]*domain.ElasticsearchCheckHistory, 0, len(sources))
+	for _, source := range sources {
+		history := new(domain.ElasticsearchCheckHistory)
+		history.FillFromDottedMap(source)
+		histories = append(histories, history)
+	}
 	return
 }
@@ -5,12 +5,8 @@
 package elasticsearch
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/pkg/errors"
 	"log"
 	"time"
@@ -29,8 +25,11 @@ type esSwarmpitCheckHistoryRepository struct {
 	// esCli is elasticsearch client connection injected from the outside package
 	esCli *elasticsearch.Client
 
-	// reqBodyWriter is implementation of reqBodyWriter interface to write []byte for request body
-	reqBodyWriter reqBodyWriter
+	// bulkIndexer is used for indexing history document with bulk API, injected from the outside package
+	bulkIndexer bulkIndexer
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to request body
+	docEncoder docEncoder
 }
 
 // esSwarmpitCheckHistoryRepoConfig is the config for swarmpit check history repository using elasticsearch
@@ -39,16 +38,26 @@ type esSwarmpitCheckHistoryRepoConfig interface {
 	esRepositoryComponentConfig
 }
 
+// swarmpitCheckMappingProperties is mapping properties about fields of SwarmpitCheckHistory, merged into index mapping
+// capacity & memory usage are mapped as number of bytes, with human readable companion formatted with unit (Ex, 3.00GB)
+var swarmpitCheckMappingProperties = map[string]interface{}{
+	"swarmpit_app_memory_usage":       longField,
+	"swarmpit_app_memory_usage_human": keywordField,
+	"if_swarmpit_app_restarted":       booleanField,
+}
+
 // NewESSwarmpitCheckHistoryRepository return new object that implement SwarmpitCheckHistoryRepository interface
 func NewESSwarmpitCheckHistoryRepository(
 	cfg esSwarmpitCheckHistoryRepoConfig,
 	cli *elasticsearch.Client,
-	w reqBodyWriter,
+	bi bulkIndexer,
+	e docEncoder,
 ) domain.SwarmpitCheckHistoryRepository {
 	repo := &esSwarmpitCheckHistoryRepository{
-		myCfg:         cfg,
-		esCli:         cli,
-		reqBodyWriter: w,
+		myCfg:       cfg,
+		esCli:       cli,
+		bulkIndexer: bi,
+		docEncoder:  e,
 	}
 
 	if err := repo.Migrate(); err != nil {
@@ -60,39 +69,76 @@ func NewESSwarmpitCheckHistoryRepository(
 
 // Implement Migrate method of SwarmpitCheckHistoryRepository interface
 func (esr *esSwarmpitCheckHistoryRepository) Migrate() error {
-	return esr.esMigrator.Migrate(esr.myCfg, esr.esCli, esr.reqBodyWriter)
+	return esr.esMigrator.Migrate(esr.myCfg, esr.esCli, esr.docEncoder)
 }
 
 // Implement Store method of SwarmpitCheckHistoryRepository interface
 func (esr *esSwarmpitCheckHistoryRepository) Store(history *domain.SwarmpitCheckHistory) (b []byte, err error) {
-	body, _ := json.Marshal(history.DottedMapWithPrefix(""))
-	if _, err = esr.reqBodyWriter.Write(body); err != nil {
-		err = errors.Wrap(err, "failed to write map to body writer")
+	body, err := esr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
 		return
 	}
 
-	buf := &bytes.Buffer{}
-	if _, err = esr.reqBodyWriter.WriteTo(buf); err != nil {
-		err = errors.Wrap(err, "failed to body writer WriteTo method")
+	// history document is buffered & indexed later with bulk API, and spooled in disk if elasticsearch is unavailable
+	if err = esr.bulkIndexer.Index(esr.myCfg.IndexName(), history.UUID, body); err != nil {
+		err = errors.Wrap(err, "failed to buffer history document in bulk indexer")
+	}
+	return
+}
+
+// Implement UpdateAlarmResult method of SwarmpitCheckHistoryRepository interface
+func (esr *esSwarmpitCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return updateAlarmResult(esr.esCli, esr.bulkIndexer, esr.myCfg.IndexName(), uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of SwarmpitCheckHistoryRepository interface
+func (esr *esSwarmpitCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.SwarmpitCheckHistory, err error) {
+	sources, err := searchByTimeRange(ctx, esr.esCli, esr.myCfg.IndexName(), "SwarmpitCheck", start, end)
+	if err != nil {
 		return
 	}
+	histories = swarmpitCheckHistories(sources)
+	return
+}
 
-	resp, err := (esapi.IndexRequest{
-		Index:        esr.myCfg.IndexName(),
-		Body:         bytes.NewReader(buf.Bytes()),
-		Timeout:      time.Second * 5,
-	}).Do(context.Background(), esr.esCli)
+// Implement GetByUUID method of SwarmpitCheckHistoryRepository interface
+func (esr *esSwarmpitCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.SwarmpitCheckHistory, err error) {
+	sources, err := searchLatest(ctx, esr.esCli, esr.myCfg.IndexName(), 1, typeFilter("SwarmpitCheck"), idFilter(uuid))
+	if err != nil || len(sources) == 0 {
+		return
+	}
+	history = swarmpitCheckHistories(sources)[0]
+	return
+}
 
+// Implement FindByProcessLevel method of SwarmpitCheckHistoryRepository interface
+func (esr *esSwarmpitCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.SwarmpitCheckHistory, err error) {
+	sources, err := searchByProcessLevel(ctx, esr.esCli, esr.myCfg.IndexName(), "SwarmpitCheck", level, start, end)
 	if err != nil {
-		err = errors.Wrap(err, fmt.Sprintf("failed to call IndexRequest, resp: %+v", resp))
 		return
-	} else if resp.IsError() {
-		err = errors.Errorf("IndexRequest return error code, resp: %+v", resp)
+	}
+	histories = swarmpitCheckHistories(sources)
+	return
+}
+
+// Implement FindLatest method of SwarmpitCheckHistoryRepository interface
+func (esr *esSwarmpitCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.SwarmpitCheckHistory, err error) {
+	sources, err := searchLatest(ctx, esr.esCli, esr.myCfg.IndexName(), n, typeFilter("SwarmpitCheck"))
+	if err != nil {
 		return
 	}
+	histories = swarmpitCheckHistories(sources)
+	return
+}
 
-	result := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&result)
-	b, _ = json.Marshal(result)
+// swarmpitCheckHistories return SwarmpitCheckHistory slice restored from sources of history documents
+func swarmpitCheckHistories(sources []map[string]interface{}) (histories []*domain.SwarmpitCheckHistory) {
+	histories = make([]*domain.SwarmpitCheckHistory, 0, len(sources))
+	for _, source := range sources {
+		history := new(domain.SwarmpitCheckHistory)
+		history.FillFromDottedMap(source)
+		histories = append(histories, history)
+	}
 	return
 }
@@ -0,0 +1,49 @@
+package elasticsearch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// dottedMapper is interface implemented in every history type of srvcheck domain
+type dottedMapper interface {
+	FillPrivateComponent()
+	DottedMapWithPrefix(prefix string) map[string]interface{}
+}
+
+func TestIndexMappingCoversHistoryFields(t *testing.T) {
+	histories := map[string]dottedMapper{
+		"ConsulCheck":        &domain.ConsulCheckHistory{},
+		"ElasticsearchCheck": &domain.ElasticsearchCheckHistory{},
+		"SwarmpitCheck":      &domain.SwarmpitCheckHistory{},
+	}
+
+	properties := indexMapping()["properties"].(map[string]interface{})
+	for name, history := range histories {
+		history.FillPrivateComponent()
+		for key := range history.DottedMapWithPrefix("") {
+			property := map[string]interface{}{"properties": properties}
+			for _, step := range strings.Split(key, ".") {
+				nested, _ := property["properties"].(map[string]interface{})
+				if property, _ = nested[step].(map[string]interface{}); property == nil {
+					break
+				}
+			}
+			if property == nil {
+				t.Errorf("field of %s is not defined in index mapping, field: %s", name, key)
+			}
+		}
+	}
+}
+
+func TestIndexMappingIsNotDynamic(t *testing.T) {
+	mapping := indexMapping()
+	if mapping["dynamic"] != false {
+		t.Errorf("dynamic of index mapping should be false, actual: %v", mapping["dynamic"])
+	}
+	if _, ok := mapping["dynamic_templates"]; ok {
+		t.Error("dynamic templates are never applied in index mapping which is not dynamic")
+	}
+}
@@ -0,0 +1,268 @@
+// file package is for implementations of srvcheck domain repository using local file system
+// history is appended to JSON-lines file having same document shape with elasticsearch, rotated by size
+
+// srvcheck.go is file that define structure to embed from another structures.
+// It also defines interface or function used jointly in the package as private.
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileRepositoryComponentConfig is interface contains method to return config value that file repository should have
+// It can be externally set as Config object that implements that interface.
+type fileRepositoryComponentConfig interface {
+	// IndexName method returns the index name about srvcheck, used as prefix of history file name
+	IndexName() string
+
+	// HistoryFileDir method returns path of directory to store history file in
+	HistoryFileDir() string
+
+	// HistoryFileMaxSize method returns max size of history file in bytes, file is rotated when exceeding it
+	HistoryFileMaxSize() int64
+
+	// HistoryFileMaxBackups method returns max number of rotated history file to keep
+	HistoryFileMaxBackups() int
+}
+
+// docEncoder is private interface to use as encoding dotted map of history to nested json for document
+type docEncoder interface {
+	// Encode encode dotted map to nested json, separating json value step with dot in map key
+	Encode(m map[string]interface{}) ([]byte, error)
+}
+
+// maxLineSize is max size of one line in history file, which is one history document
+const maxLineSize = 1024 * 1024
+
+// historyFile is struct that append, read & update history document in JSON-lines file rotated by size
+type historyFile struct {
+	// path is path of history file being appended now, rotated file has number suffix (Ex, path.1)
+	path string
+
+	// maxSize is max size of history file in bytes
+	maxSize int64
+
+	// maxBackups is max number of rotated history file to keep
+	maxBackups int
+
+	// mutex help to prevent race condition when access history file
+	mutex sync.Mutex
+}
+
+// newHistoryFile return new historyFile ptr instance having path made of config & check name
+func newHistoryFile(cfg fileRepositoryComponentConfig, check string) *historyFile {
+	return &historyFile{
+		path:       filepath.Join(cfg.HistoryFileDir(), fmt.Sprintf("%s-%s.jsonl", cfg.IndexName(), check)),
+		maxSize:    cfg.HistoryFileMaxSize(),
+		maxBackups: cfg.HistoryFileMaxBackups(),
+	}
+}
+
+// migrate create directory of history file if not exist
+func (hf *historyFile) migrate() error {
+	if err := os.MkdirAll(filepath.Dir(hf.path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directory of history file")
+	}
+	return nil
+}
+
+// storeResult return bytes of result about document stored in history file, like response body of elasticsearch
+func (hf *historyFile) storeResult(uuid string) []byte {
+	b, _ := json.Marshal(map[string]interface{}{"_id": uuid, "_file": hf.path, "result": "created"})
+	return b
+}
+
+// append append document as a line in history file, rotating history file before if it exceeds max size
+func (hf *historyFile) append(doc []byte) error {
+	hf.mutex.Lock()
+	defer hf.mutex.Unlock()
+
+	if info, err := os.Stat(hf.path); err == nil && info.Size() > 0 && info.Size()+int64(len(doc))+1 > hf.maxSize {
+		if err := hf.rotate(); err != nil {
+			return errors.Wrap(err, "failed to rotate history file")
+		}
+	}
+
+	f, err := os.OpenFile(hf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to open history file")
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(append(doc, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write document in history file")
+	}
+	return nil
+}
+
+// rotate shift rotated history files & rename history file to first rotated file, must be called with mutex locked
+// the oldest file exceeding max backups is removed
+func (hf *historyFile) rotate() error {
+	if hf.maxBackups == 0 {
+		return os.Remove(hf.path)
+	}
+	if err := os.Remove(hf.backupPath(hf.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := hf.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(hf.backupPath(i), hf.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(hf.path, hf.backupPath(1))
+}
+
+// backupPath return path of rotated history file having number (0 means history file being appended now)
+func (hf *historyFile) backupPath(n int) string {
+	if n == 0 {
+		return hf.path
+	}
+	return fmt.Sprintf("%s.%d", hf.path, n)
+}
+
+// readDocuments call fn with every document in history files from the oldest, until fn return false
+func (hf *historyFile) readDocuments(fn func(doc map[string]interface{}) bool) error {
+	hf.mutex.Lock()
+	defer hf.mutex.Unlock()
+
+	for i := hf.maxBackups; i >= 0; i-- {
+		f, err := os.Open(hf.backupPath(i))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return errors.Wrap(err, "failed to open history file")
+		}
+
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		for scanner.Scan() {
+			doc := map[string]interface{}{}
+			if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+				continue
+			}
+			if !fn(doc) {
+				_ = f.Close()
+				return nil
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return errors.Wrap(err, "failed to scan history file")
+		}
+	}
+	return nil
+}
+
+// findByTimeRange return documents having @timestamp between start (inclusive) & end (exclusive) in order of time
+func (hf *historyFile) findByTimeRange(start, end time.Time) (docs []map[string]interface{}, err error) {
+	err = hf.readDocuments(func(doc map[string]interface{}) bool {
+		s, _ := doc["@timestamp"].(string)
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil && !t.Before(start) && t.Before(end) {
+			docs = append(docs, doc)
+		}
+		return true
+	})
+	return
+}
+
+// getByUUID return the last document having uuid (nil if not exist)
+func (hf *historyFile) getByUUID(uuid string) (doc map[string]interface{}, err error) {
+	err = hf.readDocuments(func(d map[string]interface{}) bool {
+		if d["uuid"] == uuid {
+			doc = d
+		}
+		return true
+	})
+	return
+}
+
+// findByProcessLevel return documents having level in process level & @timestamp between start & end in order of time
+// process level is stored as levels joined with " | ", so document having level as one of joined levels is returned
+func (hf *historyFile) findByProcessLevel(level string, start, end time.Time) (docs []map[string]interface{}, err error) {
+	found, err := hf.findByTimeRange(start, end)
+	for _, doc := range found {
+		s, _ := doc["process_level"].(string)
+		for _, l := range strings.Split(s, " | ") {
+			if l == level {
+				docs = append(docs, doc)
+				break
+			}
+		}
+	}
+	return
+}
+
+// findLatest return latest n documents in history files in order of newest first
+func (hf *historyFile) findLatest(n int) (docs []map[string]interface{}, err error) {
+	var latest []map[string]interface{}
+	err = hf.readDocuments(func(doc map[string]interface{}) bool {
+		if latest = append(latest, doc); len(latest) > n {
+			latest = latest[1:]
+		}
+		return true
+	})
+
+	for i := len(latest) - 1; i >= 0; i-- {
+		docs = append(docs, latest[i])
+	}
+	return
+}
+
+// updateAlarmResult update alarm result fields of document having uuid, rewriting history file including that document
+// alarm time & text are kept if text is empty, as alert is sent to another notifier than one recorded in history
+func (hf *historyFile) updateAlarmResult(uuid string, t time.Time, text string, alarmErr error) error {
+	hf.mutex.Lock()
+	defer hf.mutex.Unlock()
+
+	needle := []byte(fmt.Sprintf(`"uuid":%q`, uuid))
+	for i := 0; i <= hf.maxBackups; i++ {
+		b, err := ioutil.ReadFile(hf.backupPath(i))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return errors.Wrap(err, "failed to read history file")
+		}
+
+		lines := bytes.Split(b, []byte{'\n'})
+		for j, line := range lines {
+			if !bytes.Contains(line, needle) {
+				continue
+			}
+
+			doc := map[string]interface{}{}
+			if err := json.Unmarshal(line, &doc); err != nil || doc["uuid"] != uuid {
+				continue
+			}
+			doc["alerted"], doc["alarm_error"] = true, nil
+			if alarmErr != nil {
+				doc["alarm_error"] = alarmErr.Error()
+			}
+			if text != "" {
+				doc["alarm_time"], doc["alarm_text"] = t, text
+			}
+			lines[j], _ = json.Marshal(doc)
+
+			tmp := hf.backupPath(i) + ".tmp"
+			if err := ioutil.WriteFile(tmp, bytes.Join(lines, []byte{'\n'}), 0644); err != nil {
+				return errors.Wrap(err, "failed to write temporary history file")
+			}
+			if err := os.Rename(tmp, hf.backupPath(i)); err != nil {
+				return errors.Wrap(err, "failed to replace history file")
+			}
+			return nil
+		}
+	}
+	return errors.Errorf("history document having uuid %s is not found in history file", uuid)
+}
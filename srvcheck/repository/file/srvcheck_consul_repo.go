@@ -0,0 +1,125 @@
+// srvcheck_consul_repo.go is file that define implement consul history repository using local file system
+// this consul repository struct use historyFile struct in ./srvcheck.go file
+
+package file
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fileConsulCheckHistoryRepository is to handle ConsulCheckHistory model using JSON-lines file as data store
+type fileConsulCheckHistoryRepository struct {
+	// myCfg is used for get consul check history repository config about file
+	myCfg fileConsulCheckHistoryRepoConfig
+
+	// historyFile is used for appending & reading consul check history document in JSON-lines file
+	historyFile *historyFile
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to document
+	docEncoder docEncoder
+}
+
+// fileConsulCheckHistoryRepoConfig is the config for consul check history repository using file
+type fileConsulCheckHistoryRepoConfig interface {
+	// get common method from embedding fileRepositoryComponentConfig
+	fileRepositoryComponentConfig
+}
+
+// NewFileConsulCheckHistoryRepository return new object that implement ConsulCheckHistoryRepository interface
+func NewFileConsulCheckHistoryRepository(cfg fileConsulCheckHistoryRepoConfig, e docEncoder) domain.ConsulCheckHistoryRepository {
+	repo := &fileConsulCheckHistoryRepository{
+		myCfg:       cfg,
+		historyFile: newHistoryFile(cfg, "consul"),
+		docEncoder:  e,
+	}
+
+	if err := repo.Migrate(); err != nil {
+		log.Fatal(errors.Wrap(err, "could not migrate repository").Error())
+	}
+
+	return repo
+}
+
+// Implement Migrate method of ConsulCheckHistoryRepository interface
+func (fcr *fileConsulCheckHistoryRepository) Migrate() error {
+	return fcr.historyFile.migrate()
+}
+
+// Implement Store method of ConsulCheckHistoryRepository interface
+func (fcr *fileConsulCheckHistoryRepository) Store(history *domain.ConsulCheckHistory) (b []byte, err error) {
+	doc, err := fcr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
+		return
+	}
+
+	if err = fcr.historyFile.append(doc); err != nil {
+		err = errors.Wrap(err, "failed to append document in history file")
+		return
+	}
+
+	b = fcr.historyFile.storeResult(history.UUID)
+	return
+}
+
+// Implement UpdateAlarmResult method of ConsulCheckHistoryRepository interface
+func (fcr *fileConsulCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return fcr.historyFile.updateAlarmResult(uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of ConsulCheckHistoryRepository interface
+func (fcr *fileConsulCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.ConsulCheckHistory, err error) {
+	docs, err := fcr.historyFile.findByTimeRange(start, end)
+	if err != nil {
+		return
+	}
+	histories = consulCheckHistories(docs)
+	return
+}
+
+// Implement GetByUUID method of ConsulCheckHistoryRepository interface
+func (fcr *fileConsulCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.ConsulCheckHistory, err error) {
+	doc, err := fcr.historyFile.getByUUID(uuid)
+	if err != nil || doc == nil {
+		return
+	}
+	history = new(domain.ConsulCheckHistory)
+	history.FillFromDottedMap(doc)
+	return
+}
+
+// Implement FindByProcessLevel method of ConsulCheckHistoryRepository interface
+func (fcr *fileConsulCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.ConsulCheckHistory, err error) {
+	docs, err := fcr.historyFile.findByProcessLevel(level, start, end)
+	if err != nil {
+		return
+	}
+	histories = consulCheckHistories(docs)
+	return
+}
+
+// Implement FindLatest method of ConsulCheckHistoryRepository interface
+func (fcr *fileConsulCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.ConsulCheckHistory, err error) {
+	docs, err := fcr.historyFile.findLatest(n)
+	if err != nil {
+		return
+	}
+	histories = consulCheckHistories(docs)
+	return
+}
+
+// consulCheckHistories return ConsulCheckHistory slice restored from history documents
+func consulCheckHistories(docs []map[string]interface{}) (histories []*domain.ConsulCheckHistory) {
+	histories = make([]*domain.ConsulCheckHistory, 0, len(docs))
+	for _, doc := range docs {
+		history := new(domain.ConsulCheckHistory)
+		history.FillFromDottedMap(doc)
+		histories = append(histories, history)
+	}
+	return
+}
@@ -0,0 +1,125 @@
+// srvcheck_elasticsearch_repo.go is file that define implement elasticsearch history repository using local file system
+// this elasticsearch repository struct use historyFile struct in ./srvcheck.go file
+
+package file
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fileElasticsearchCheckHistoryRepository is to handle ElasticsearchCheckHistory model using JSON-lines file as data store
+type fileElasticsearchCheckHistoryRepository struct {
+	// myCfg is used for get elasticsearch check history repository config about file
+	myCfg fileElasticsearchCheckHistoryRepoConfig
+
+	// historyFile is used for appending & reading elasticsearch check history document in JSON-lines file
+	historyFile *historyFile
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to document
+	docEncoder docEncoder
+}
+
+// fileElasticsearchCheckHistoryRepoConfig is the config for elasticsearch check history repository using file
+type fileElasticsearchCheckHistoryRepoConfig interface {
+	// get common method from embedding fileRepositoryComponentConfig
+	fileRepositoryComponentConfig
+}
+
+// NewFileElasticsearchCheckHistoryRepository return new object that implement ElasticsearchCheckHistoryRepository interface
+func NewFileElasticsearchCheckHistoryRepository(cfg fileElasticsearchCheckHistoryRepoConfig, e docEncoder) domain.ElasticsearchCheckHistoryRepository {
+	repo := &fileElasticsearchCheckHistoryRepository{
+		myCfg:       cfg,
+		historyFile: newHistoryFile(cfg, "elasticsearch"),
+		docEncoder:  e,
+	}
+
+	if err := repo.Migrate(); err != nil {
+		log.Fatal(errors.Wrap(err, "could not migrate repository").Error())
+	}
+
+	return repo
+}
+
+// Implement Migrate method of ElasticsearchCheckHistoryRepository interface
+func (fer *fileElasticsearchCheckHistoryRepository) Migrate() error {
+	return fer.historyFile.migrate()
+}
+
+// Implement Store method of ElasticsearchCheckHistoryRepository interface
+func (fer *fileElasticsearchCheckHistoryRepository) Store(history *domain.ElasticsearchCheckHistory) (b []byte, err error) {
+	doc, err := fer.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
+		return
+	}
+
+	if err = fer.historyFile.append(doc); err != nil {
+		err = errors.Wrap(err, "failed to append document in history file")
+		return
+	}
+
+	b = fer.historyFile.storeResult(history.UUID)
+	return
+}
+
+// Implement UpdateAlarmResult method of ElasticsearchCheckHistoryRepository interface
+func (fer *fileElasticsearchCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return fer.historyFile.updateAlarmResult(uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of ElasticsearchCheckHistoryRepository interface
+func (fer *fileElasticsearchCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	docs, err := fer.historyFile.findByTimeRange(start, end)
+	if err != nil {
+		return
+	}
+	histories = elasticsearchCheckHistories(docs)
+	return
+}
+
+// Implement GetByUUID method of ElasticsearchCheckHistoryRepository interface
+func (fer *fileElasticsearchCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.ElasticsearchCheckHistory, err error) {
+	doc, err := fer.historyFile.getByUUID(uuid)
+	if err != nil || doc == nil {
+		return
+	}
+	history = new(domain.ElasticsearchCheckHistory)
+	history.FillFromDottedMap(doc)
+	return
+}
+
+// Implement FindByProcessLevel method of ElasticsearchCheckHistoryRepository interface
+func (fer *fileElasticsearchCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	docs, err := fer.historyFile.findByProcessLevel(level, start, end)
+	if err != nil {
+		return
+	}
+	histories = elasticsearchCheckHistories(docs)
+	return
+}
+
+// Implement FindLatest method of ElasticsearchCheckHistoryRepository interface
+func (fer *fileElasticsearchCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	docs, err := fer.historyFile.findLatest(n)
+	if err != nil {
+		return
+	}
+	histories = elasticsearchCheckHistories(docs)
+	return
+}
+
+// elasticsearchCheckHistories return ElasticsearchCheckHistory slice restored from history documents
+func elasticsearchCheckHistories(docs []map[string]interface{}) (histories []*domain.ElasticsearchCheckHistory) {
+	histories = make([]*domain.ElasticsearchCheckHistory, 0, len(docs))
+	for _, doc := range docs {
+		history := new(domain.ElasticsearchCheckHistory)
+		history.FillFromDottedMap(doc)
+		histories = append(histories, history)
+	}
+	return
+}
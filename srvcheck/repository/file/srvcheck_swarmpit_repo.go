@@ -0,0 +1,125 @@
+// srvcheck_swarmpit_repo.go is file that define implement swarmpit history repository using local file system
+// this swarmpit repository struct use historyFile struct in ./srvcheck.go file
+
+package file
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fileSwarmpitCheckHistoryRepository is to handle SwarmpitCheckHistory model using JSON-lines file as data store
+type fileSwarmpitCheckHistoryRepository struct {
+	// myCfg is used for get swarmpit check history repository config about file
+	myCfg fileSwarmpitCheckHistoryRepoConfig
+
+	// historyFile is used for appending & reading swarmpit check history document in JSON-lines file
+	historyFile *historyFile
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to document
+	docEncoder docEncoder
+}
+
+// fileSwarmpitCheckHistoryRepoConfig is the config for swarmpit check history repository using file
+type fileSwarmpitCheckHistoryRepoConfig interface {
+	// get common method from embedding fileRepositoryComponentConfig
+	fileRepositoryComponentConfig
+}
+
+// NewFileSwarmpitCheckHistoryRepository return new object that implement SwarmpitCheckHistoryRepository interface
+func NewFileSwarmpitCheckHistoryRepository(cfg fileSwarmpitCheckHistoryRepoConfig, e docEncoder) domain.SwarmpitCheckHistoryRepository {
+	repo := &fileSwarmpitCheckHistoryRepository{
+		myCfg:       cfg,
+		historyFile: newHistoryFile(cfg, "swarmpit"),
+		docEncoder:  e,
+	}
+
+	if err := repo.Migrate(); err != nil {
+		log.Fatal(errors.Wrap(err, "could not migrate repository").Error())
+	}
+
+	return repo
+}
+
+// Implement Migrate method of SwarmpitCheckHistoryRepository interface
+func (fsr *fileSwarmpitCheckHistoryRepository) Migrate() error {
+	return fsr.historyFile.migrate()
+}
+
+// Implement Store method of SwarmpitCheckHistoryRepository interface
+func (fsr *fileSwarmpitCheckHistoryRepository) Store(history *domain.SwarmpitCheckHistory) (b []byte, err error) {
+	doc, err := fsr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
+		return
+	}
+
+	if err = fsr.historyFile.append(doc); err != nil {
+		err = errors.Wrap(err, "failed to append document in history file")
+		return
+	}
+
+	b = fsr.historyFile.storeResult(history.UUID)
+	return
+}
+
+// Implement UpdateAlarmResult method of SwarmpitCheckHistoryRepository interface
+func (fsr *fileSwarmpitCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return fsr.historyFile.updateAlarmResult(uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of SwarmpitCheckHistoryRepository interface
+func (fsr *fileSwarmpitCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.SwarmpitCheckHistory, err error) {
+	docs, err := fsr.historyFile.findByTimeRange(start, end)
+	if err != nil {
+		return
+	}
+	histories = swarmpitCheckHistories(docs)
+	return
+}
+
+// Implement GetByUUID method of SwarmpitCheckHistoryRepository interface
+func (fsr *fileSwarmpitCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.SwarmpitCheckHistory, err error) {
+	doc, err := fsr.historyFile.getByUUID(uuid)
+	if err != nil || doc == nil {
+		return
+	}
+	history = new(domain.SwarmpitCheckHistory)
+	history.FillFromDottedMap(doc)
+	return
+}
+
+// Implement FindByProcessLevel method of SwarmpitCheckHistoryRepository interface
+func (fsr *fileSwarmpitCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.SwarmpitCheckHistory, err error) {
+	docs, err := fsr.historyFile.findByProcessLevel(level, start, end)
+	if err != nil {
+		return
+	}
+	histories = swarmpitCheckHistories(docs)
+	return
+}
+
+// Implement FindLatest method of SwarmpitCheckHistoryRepository interface
+func (fsr *fileSwarmpitCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.SwarmpitCheckHistory, err error) {
+	docs, err := fsr.historyFile.findLatest(n)
+	if err != nil {
+		return
+	}
+	histories = swarmpitCheckHistories(docs)
+	return
+}
+
+// swarmpitCheckHistories return SwarmpitCheckHistory slice restored from history documents
+func swarmpitCheckHistories(docs []map[string]interface{}) (histories []*domain.SwarmpitCheckHistory) {
+	histories = make([]*domain.SwarmpitCheckHistory, 0, len(docs))
+	for _, doc := range docs {
+		history := new(domain.SwarmpitCheckHistory)
+		history.FillFromDottedMap(doc)
+		histories = append(histories, history)
+	}
+	return
+}
@@ -9,10 +9,12 @@
 package usecase
 
 import (
+	"context"
 	"github.com/docker/docker/api/types"
 	"github.com/inhies/go-bytesize"
-	"github.com/slack-go/slack"
 	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
 )
 
 // global variable used in usecase to represent process level
@@ -24,28 +26,101 @@ const (
 	recoveredLevel    = "RECOVERED"     // represent that succeed to recover service status
 	unhealthyLevel    = "UNHEALTHY"     // represent that service status is unhealthy now (not recovered)
 	errorLevel        = "ERROR"         // represent that error occurs while checking service status
+	skippedLevel      = "SKIPPED"       // represent that check is skipped as same check process is already running
+	resetLevel        = "RESET"         // represent that check status is reset to healthy by administrator
+	maintenanceLevel  = "MAINTENANCE"   // represent that remediation & alarm are suppressed by maintenance window
+)
+
+// overlap policy used in usecase to decide how to handle check process requested while same check process is running
+const (
+	overlapPolicySkip  = "skip"  // skip requested check process & store history with skipped level
+	overlapPolicyQueue = "queue" // wait until running check process is finished & run requested check process
 )
 
 // serviceCheckUsecaseComponentConfig contains required component to service usecase implementation as field
-type serviceCheckUsecaseComponentConfig interface {}
+type serviceCheckUsecaseComponentConfig interface {
+	// CheckOverlapPolicy method returns policy about check process requested while same check process is running
+	CheckOverlapPolicy() string
+}
+
+// notifierAgency is interface that agent notifiers delivering alert produced in check process (Ex, slack, webhook)
+// you can see implementation in notifier package
+type notifierAgency interface {
+	// Notify deliver alert to notifiers and return notified time & text & error
+	Notify(alert *domain.Alert) (t time.Time, text string, err error)
+}
+
+// statusStoreAgency is agency that store & load status of check process state machine to survive restart
+type statusStoreAgency interface {
+	// LoadStatus load status & last transition time of check saved with key (empty status if not saved yet)
+	LoadStatus(ctx context.Context, key string) (status string, transitedAt time.Time, err error)
+
+	// StoreStatus store status & last transition time of check with key
+	StoreStatus(ctx context.Context, key, status string, transitedAt time.Time) (err error)
+}
+
+// statusStoreTimeout is deadline of calling status store agency, so that unavailable store doesn't block check process
+const statusStoreTimeout = time.Second * 5
 
-// slackChatAgency is interface that agent the slack api about chatting
+// maintenanceAgency is agency that manage maintenance window suppressing remediation & alarm of check process
+// you can see implementation in maintenance package
+type maintenanceAgency interface {
+	// GetActiveWindow return id of maintenance window active now & covering check or container (empty if not exist)
+	GetActiveWindow(check, container string) (id string)
+
+	// GetActiveContainers return names of container covered by maintenance window active now
+	GetActiveContainers() (containers []string)
+}
+
+// escalationAgency is agency that escalate incident of check staying unhealthy long according to escalation rule
+// you can see implementation in escalation package
+type escalationAgency interface {
+	// Escalate deliver alert with mentions if incident of check unhealthy since startedAt for cycles satisfy rule
+	// it return zero value if alert is not escalated (Ex, rule not satisfied yet or already escalated)
+	Escalate(check string, startedAt time.Time, cycles int, alert *domain.Alert) (t time.Time, text string, err error)
+
+	// CloseEscalation close escalation of check with recovered or reset alert
+	CloseEscalation(check string, alert *domain.Alert)
+}
+
+// reportAgency is agency that post report having title & lines of text (Ex, digest of check histories)
 // you can see implementation in slack package
-type slackChatAgency interface {
-	// SendMessage send message with text & emoji using slack API and return send time & text & error
-	SendMessage(emoji, text, uuid string, opts ...slack.MsgOption) (t time.Time, _text string, err error)
+type reportAgency interface {
+	// PostReport post report with title & lines and return posted time & error
+	PostReport(title string, lines []string) (t time.Time, err error)
 }
 
+// metricAgency is interface that agent metric collector to expose numbers computed in usecase
+// you can see implementation in prometheus package
+type metricAgency interface {
+	// SetGauge set gauge metric value having name & labels received from param
+	SetGauge(name string, labels map[string]string, value float64)
+
+	// AddCounter add value to counter metric having name & labels received from param
+	AddCounter(name string, labels map[string]string, value float64)
+
+	// ObserveHistogram add value observed to histogram metric having name & labels received from param
+	ObserveHistogram(name string, labels map[string]string, value float64)
+}
+
+// metric names used in common in every service check usecase
+const (
+	checkRunsMetric         = "health_check_runs_total"         // counter of check process run per process level
+	checkDurationMetric     = "health_check_duration_seconds"   // histogram of duration of check process run
+	checkStatusMetric       = "health_check_status"             // gauge set to 1 in current status, 0 in another status
+	checkRemediationsMetric = "health_check_remediations_total" // counter of remediation per action (Ex, container_removed)
+)
+
 // dockerAgency is agency that agent various command about docker engine API
 type dockerAgency interface {
 	// GetContainerWithServiceName return container which is instance of received service name
-	GetContainerWithServiceName(srv string) (container interface {
+	GetContainerWithServiceName(ctx context.Context, srv string) (container interface {
 		ID() string                     // get id of container
 		MemoryUsage() bytesize.ByteSize // get memory usage of container
 	}, err error)
 
 	// RemoveContainer remove container with id & option (auto created from docker swarm if exists)
-	RemoveContainer(containerID string, options types.ContainerRemoveOptions) error
+	RemoveContainer(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error
 }
 
 // intComparator is struct type having int type field which is used for compare with another int
@@ -65,3 +140,87 @@ func (comparator bytesizeComparator) isMoreThan(target bytesize.ByteSize) bool {
 
 // isMoreThan return boolean if size of instance which call this method is less than parameter's size
 func (comparator bytesizeComparator) isLessThan(target bytesize.ByteSize) bool { return comparator.V < target }
+
+// checkLabels return metric labels having srvcheck domain & check name received from param
+func checkLabels(check string) map[string]string {
+	return map[string]string{"domain": "srvcheck", "check": check}
+}
+
+// remediationLabels return metric labels about remediation action in check process
+func remediationLabels(check, action string) map[string]string {
+	labels := checkLabels(check)
+	labels["action"] = action
+	return labels
+}
+
+// recordCommonMetrics record metrics which every check process has in common (run count, duration, status)
+func recordCommonMetrics(ma metricAgency, check, level string, elapsed time.Duration, current string, statuses []string) {
+	levelLabels := checkLabels(check)
+	levelLabels["process_level"] = level
+	ma.AddCounter(checkRunsMetric, levelLabels, 1)
+
+	ma.ObserveHistogram(checkDurationMetric, checkLabels(check), elapsed.Seconds())
+
+	for _, status := range statuses {
+		statusLabels := checkLabels(check)
+		statusLabels["status"] = status
+		if status == current {
+			ma.SetGauge(checkStatusMetric, statusLabels, 1)
+		} else {
+			ma.SetGauge(checkStatusMetric, statusLabels, 0)
+		}
+	}
+}
+
+// maxQueuedChecks is max number of check process waiting running one in queue policy, the rest are skipped
+const maxQueuedChecks = 1
+
+// checkRunLocker is used for preventing same check process from running at the same time (single-flight)
+type checkRunLocker struct {
+	// running is channel having token while check process is running
+	running chan struct{}
+
+	// waiting is channel having token per check process waiting running one in queue policy
+	waiting chan struct{}
+}
+
+// newCheckRunLocker return new checkRunLocker which allow only one check process to run at the same time
+func newCheckRunLocker() checkRunLocker {
+	return checkRunLocker{
+		running: make(chan struct{}, 1),
+		waiting: make(chan struct{}, maxQueuedChecks),
+	}
+}
+
+// lock acquire right to run check process according to overlap policy & return if acquired
+// in skip policy, return false immediately if already running. in queue policy, wait until released or ctx is done,
+// but return false immediately if max number of check process are already waiting, so that waiters don't pile up
+func (l checkRunLocker) lock(ctx context.Context, policy string) bool {
+	select {
+	case l.running <- struct{}{}:
+		return true
+	default:
+		if policy != overlapPolicyQueue {
+			return false
+		}
+	}
+
+	select {
+	case l.waiting <- struct{}{}:
+		defer func() { <-l.waiting }()
+	default:
+		return false
+	}
+
+	select {
+	case l.running <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// unlock release right to run check process acquired in lock method
+func (l checkRunLocker) unlock() {
+	<-l.running
+}
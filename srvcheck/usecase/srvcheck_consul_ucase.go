@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"log"
 	"sync"
 	"time"
 
@@ -25,6 +26,22 @@ const (
 	consulStatusUnhealthy                           // represent consul check status is unhealthy
 )
 
+// String method return string represent consul check status, which is used in delivery layer
+func (s consulCheckStatus) String() string {
+	switch s {
+	case consulStatusHealthy:
+		return "HEALTHY"
+	case consulStatusRecovering:
+		return "RECOVERING"
+	case consulStatusUnhealthy:
+		return "UNHEALTHY"
+	}
+	return "UNKNOWN"
+}
+
+// consulCheckKey is key identifying consul check in status store agency & maintenance window
+const consulCheckKey = "srvcheck.consul"
+
 // consulCheckUsecase implement ConsulCheckUsecase interface in domain and used in delivery layer
 type consulCheckUsecase struct {
 	// myCfg is used for getting consul check usecase config
@@ -33,8 +50,20 @@ type consulCheckUsecase struct {
 	// historyRepo is used for store consul check history and injected from outside
 	historyRepo domain.ConsulCheckHistoryRepository
 
-	// slackChat is used for agent slack API about chatting
-	slackChatAgency slackChatAgency
+	// notifierAgency is used for agent notifiers delivering alert
+	notifierAgency notifierAgency
+
+	// metricAgency is used as agency about metric collector to expose numbers computed in check process
+	metricAgency metricAgency
+
+	// statusStoreAgency is used as agency about status store to keep status of state machine across restart
+	statusStoreAgency statusStoreAgency
+
+	// maintenanceAgency is used for checking if check or container is in maintenance window
+	maintenanceAgency maintenanceAgency
+
+	// escalationAgency is used for escalating incident of consul check staying unhealthy long
+	escalationAgency escalationAgency
 
 	// consulAgency is used as agency about consul API
 	consulAgency consulAgency
@@ -48,20 +77,32 @@ type consulCheckUsecase struct {
 	// status represent current process status of consul health check
 	status consulCheckStatus
 
+	// statusChangedAt represent time when status field value was changed last
+	statusChangedAt time.Time
+
+	// unhealthyCycles represent how many check cycles have run since status was changed to unhealthy
+	unhealthyCycles int
+
+	// lastHistory represent consul check history produced in last check process
+	lastHistory *domain.ConsulCheckHistory
+
 	// mutex help to prevent race condition when set status field value
 	mutex sync.Mutex
+
+	// runLocker help to prevent consul check process from running at the same time
+	runLocker checkRunLocker
 }
 
 // consulAgency is agency that agent various command about consul API
 type consulAgency interface {
 	// GetServices method get services in consul & return services interface implement
-	GetServices(srv string) (srvIter interface {
+	GetServices(ctx context.Context, srv string) (srvIter interface {
 		HasNext() bool           // HasNext method return if srvIter has next element
 		Next() (id, addr string) // Next method return next service id, address
 	}, err error)
 
 	// DeregisterInstance method deregister service in consul with received id
-	DeregisterInstance(id string) (err error)
+	DeregisterInstance(ctx context.Context, id string) (err error)
 }
 
 // gRPCAgency is agency that agent various command about gRPC
@@ -75,6 +116,9 @@ type consulCheckUsecaseConfig interface {
 	// get common config method from embedding serviceCheckUsecaseComponentConfig
 	serviceCheckUsecaseComponentConfig
 
+	// ConsulCheckTimeOut method returns time.Duration represent deadline of one consul check process
+	ConsulCheckTimeOut() time.Duration
+
 	// CheckTargetServices method returns string slice containing target services to check in usecase
 	CheckTargetServices() []string
 
@@ -92,38 +136,103 @@ type consulCheckUsecaseConfig interface {
 func NewConsulCheckUsecase(
 	cfg consulCheckUsecaseConfig,
 	shr domain.ConsulCheckHistoryRepository,
-	sca slackChatAgency,
+	na notifierAgency,
+	ma metricAgency,
+	ssa statusStoreAgency,
+	mta maintenanceAgency,
+	eca escalationAgency,
 	ca consulAgency,
 	ga gRPCAgency,
 	da dockerAgency,
 ) domain.ConsulCheckUseCase {
-	return &consulCheckUsecase{
+	usecase := &consulCheckUsecase{
 		// initialize field with parameter received from caller
-		myCfg:           cfg,
-		historyRepo:     shr,
-		slackChatAgency: sca,
-		consulAgency:    ca,
-		gRPCAgency:      ga,
-		dockerAgency:    da,
+		myCfg:             cfg,
+		historyRepo:       shr,
+		notifierAgency:    na,
+		metricAgency:      ma,
+		statusStoreAgency: ssa,
+		maintenanceAgency: mta,
+		escalationAgency:  eca,
+		consulAgency:      ca,
+		gRPCAgency:        ga,
+		dockerAgency:      da,
 
 		// initialize field with default value
-		status: consulStatusHealthy,
-		mutex:  sync.Mutex{},
+		status:    consulStatusHealthy,
+		mutex:     sync.Mutex{},
+		runLocker: newCheckRunLocker(),
 	}
+
+	usecase.loadStatus()
+	return usecase
 }
 
 // CheckConsul check consul health with checkConsul method & store check history in repository
 // Implement CheckConsul method of ConsulCheckUseCase interface
-func (ccu *consulCheckUsecase) CheckConsul(ctx context.Context) (err error) {
-	history := ccu.checkConsul(ctx)
+func (ccu *consulCheckUsecase) CheckConsul(ctx context.Context) (history *domain.ConsulCheckHistory, err error) {
+	start := time.Now()
+	if ccu.runLocker.lock(ctx, ccu.myCfg.CheckOverlapPolicy()) {
+		toCtx, cancel := context.WithTimeout(ctx, ccu.myCfg.ConsulCheckTimeOut())
+		history = ccu.checkConsul(toCtx)
+		if toCtx.Err() != nil {
+			if history.ProcessLevel.Last() != errorLevel {
+				history.ProcessLevel.Append(errorLevel)
+			}
+			switch {
+			case history.Error != nil:
+			case toCtx.Err() == context.DeadlineExceeded:
+				history.SetError(errors.Wrapf(toCtx.Err(), "consul check process is timed out (%s)", ccu.myCfg.ConsulCheckTimeOut()))
+			default:
+				history.SetError(errors.Wrap(toCtx.Err(), "consul check process is canceled"))
+			}
+		}
+		cancel()
+		ccu.runLocker.unlock()
+		ccu.setLastHistory(history)
+	} else {
+		history = ccu.skippedHistory()
+	}
+	ccu.recordMetrics(history, time.Since(start))
 
 	if b, err := ccu.historyRepo.Store(history); err != nil {
-		return errors.Wrapf(err, "failed to store consul check history, response: %s", string(b))
+		return history, errors.Wrapf(err, "failed to store consul check history, response: %s", string(b))
 	}
 
+	return history, nil
+}
+
+// skippedHistory return consul check history represent that check process is skipped as same process is already running
+func (ccu *consulCheckUsecase) skippedHistory() (history *domain.ConsulCheckHistory) {
+	history = new(domain.ConsulCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = uuid.New().String()
+	history.ProcessLevel.Set(skippedLevel)
+	history.Message = "consul check is skipped as previous consul check process is still running"
+	return
+}
+
+// newAlert return alert about consul check history with thresholds configured in consul check config
+func (ccu *consulCheckUsecase) newAlert(history *domain.ConsulCheckHistory, level domain.AlertLevel, text string) (alert *domain.Alert) {
+	alert = history.NewAlert(level, text)
+	alert.Thresholds["check_target_services"] = ccu.myCfg.CheckTargetServices()
+	alert.Thresholds["conn_check_ping_timeout"] = ccu.myCfg.ConnCheckPingTimeOut()
 	return
 }
 
+// notify deliver alert about consul check history to notifier agency, except when consul check is in maintenance window
+// if alert is suppressed by maintenance window, id of that window is set in history
+// reason is stable code of why alert is produced (Ex, container_remove_failed), used for deduplicating it in notifier
+func (ccu *consulCheckUsecase) notify(history *domain.ConsulCheckHistory, level domain.AlertLevel, reason, text string) (t time.Time, _text string, err error) {
+	if id := ccu.maintenanceAgency.GetActiveWindow(consulCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+	alert := ccu.newAlert(history, level, text)
+	alert.Reason = reason
+	return ccu.notifierAgency.Notify(alert)
+}
+
 // method processed with below logic about consul health check according to current check status
 // 0 : 정상적으로 인지된 상태 (상태 확인 수행) (모든 등록된 Service 정상 작동 & 서비스별 인스턴스 최소 1개 존재)
 // 0 -> 1 : Consul 상태 회복(작동X 노드 삭제 or 특정 서비스 재실행) 실행 (Consul 상태 회복 실행 알림 발행)
@@ -149,18 +258,20 @@ func (ccu *consulCheckUsecase) checkConsul(ctx context.Context) (history *domain
 	case consulStatusUnhealthy:
 		history.ProcessLevel.Set(unhealthyLevel)
 		history.Message = "consul check is unhealthy now"
+		ccu.escalate(history)
 		return
 	}
 
 	srvM := map[string][]struct{ id, addr string }{}
+	containerOf := map[string]string{} // name of docker service running instance per instance id
 	for _, srv := range ccu.myCfg.CheckTargetServices() {
 		cslSrv := ccu.myCfg.ConsulServiceNameSpace() + srv
-		iter, err := ccu.consulAgency.GetServices(cslSrv)
+		iter, err := ccu.consulAgency.GetServices(ctx, cslSrv)
 		if err != nil {
 			history.ProcessLevel.Set(errorLevel)
 			history.SetError(errors.Wrap(err, "failed to get services in consul"))
 			msg := "!consul check error occurred! unable to get services in consul"
-			history.SetAlarmResult(ccu.slackChatAgency.SendMessage("x", msg, _uuid))
+			history.SetAlarmResult(ccu.notify(history, domain.AlertLevelError, "services_unavailable", msg))
 			return
 		}
 
@@ -175,6 +286,7 @@ func (ccu *consulCheckUsecase) checkConsul(ctx context.Context) (history *domain
 			id, addr := iter.Next()
 			history.InstancesPerService[cslSrv] = append(history.InstancesPerService[cslSrv], id)
 			srvM[cslSrv] = append(srvM[cslSrv], struct{ id, addr string }{id: id, addr: addr})
+			containerOf[id] = ccu.myCfg.DockerServiceNameSpace() + srv
 		}
 	}
 
@@ -182,9 +294,15 @@ func (ccu *consulCheckUsecase) checkConsul(ctx context.Context) (history *domain
 	var unableSrvIDs []string
 	for _, srvs := range srvM {
 		for _, srv := range srvs {
-			toCtx, _ := context.WithTimeout(context.Background(), ccu.myCfg.ConnCheckPingTimeOut())
+			toCtx, cancel := context.WithTimeout(ctx, ccu.myCfg.ConnCheckPingTimeOut())
 			err := ccu.gRPCAgency.PingToCheckConn(toCtx, srv.addr, grpc.WithInsecure(), grpc.WithBlock())
-			if toCtx.Err() != nil {
+			toErr := toCtx.Err()
+			cancel()
+			if ctx.Err() != nil {
+				history.ProcessLevel.Set(errorLevel)
+				history.SetError(errors.Wrapf(ctx.Err(), "consul check process is done while ping connection check, id: %s", srv.id))
+				return
+			} else if toErr != nil {
 				unableSrvIDs = append(unableSrvIDs, srv.id)
 			} else if err != nil {
 				history.ProcessLevel.Set(errorLevel)
@@ -194,28 +312,57 @@ func (ccu *consulCheckUsecase) checkConsul(ctx context.Context) (history *domain
 		}
 	}
 
+	// suppress remediation & alarm if consul check is in maintenance window, history is still stored with measured value
+	if id := ccu.maintenanceAgency.GetActiveWindow(consulCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		history.ProcessLevel.Set(maintenanceLevel)
+		history.Message = "consul check is in maintenance window, so remediation & alarm are suppressed"
+		return
+	}
+
+	// instance of service whose container is in maintenance window is not deregistered, so filter it out before alerting
+	if len(unableSrvIDs) > 0 {
+		var deregisterIDs []string
+		for _, srvID := range unableSrvIDs {
+			if window := ccu.maintenanceAgency.GetActiveWindow("", containerOf[srvID]); window != "" {
+				history.SetMaintenanceWindow(window)
+				continue
+			}
+			deregisterIDs = append(deregisterIDs, srvID)
+		}
+
+		if len(deregisterIDs) == 0 {
+			history.ProcessLevel.Set(maintenanceLevel)
+			history.Message = "every instance to deregister is in maintenance window, so remediation & alarm are suppressed"
+			return
+		}
+		unableSrvIDs = deregisterIDs
+	}
+
 	// recover(deregister) if any connection unable service is exist
 	if len(unableSrvIDs) > 0 {
 		ccu.setStatus(consulStatusRecovering)
 		history.ProcessLevel.Set(weakDetectedLevel)
 		history.Message = "deregistered services in consul which is unable to check connection pick"
 		msg := "!consul check weak detected! start to deregister unable services"
-		history.SetAlarmResult(ccu.slackChatAgency.SendMessage("pill", msg, _uuid))
+		history.SetAlarmResult(ccu.notify(history, domain.AlertLevelWeakDetected, "deregister_started", msg))
 		history.IfInstanceDeregistered = true
 
 		var successIDs, failIDs []string
 		for _, srvID := range unableSrvIDs {
-			if err := ccu.consulAgency.DeregisterInstance(srvID); err != nil {
+			if err := ccu.consulAgency.DeregisterInstance(ctx, srvID); err != nil {
 				failIDs = append(failIDs, srvID)
 				history.ProcessLevel.Append(errorLevel)
 				msg := fmt.Sprintf("!consul check error occurred! failed to deregister service, id: %s, err: %v", srvID, err)
-				_, _, _ = ccu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+				_, _, _ = ccu.notify(history, domain.AlertLevelUnhealthy, "deregister_failed", msg)
 				history.SetError(errors.Wrap(err, "failed to deregister service"))
 			} else {
 				successIDs = append(successIDs, srvID)
 			}
 		}
 
+		deregistered := float64(len(successIDs))
+		ccu.metricAgency.AddCounter(checkRemediationsMetric, remediationLabels("consul", "instance_deregistered"), deregistered)
 		history.DeregisteredInstances = successIDs
 		history.DeregisterFailedInstances = failIDs
 		ccu.setStatus(consulStatusHealthy)
@@ -230,39 +377,60 @@ func (ccu *consulCheckUsecase) checkConsul(ctx context.Context) (history *domain
 		}
 	}
 
+	// container of service in maintenance window is not restarted, so filter it out before alerting restart
+	if len(unableSrvs) > 0 {
+		var restartSrvs []string
+		for _, srv := range unableSrvs {
+			if window := ccu.maintenanceAgency.GetActiveWindow("", srv); window != "" {
+				history.SetMaintenanceWindow(window)
+				continue
+			}
+			restartSrvs = append(restartSrvs, srv)
+		}
+
+		if len(restartSrvs) == 0 {
+			history.ProcessLevel.Set(maintenanceLevel)
+			history.Message = "every container to restart is in maintenance window, so remediation & alarm are suppressed"
+			return
+		}
+		unableSrvs = restartSrvs
+	}
+
 	// restart(registered when start) if any service don't have any instances
 	if len(unableSrvs) > 0 {
 		ccu.setStatus(consulStatusRecovering)
 		history.ProcessLevel.Set(weakDetectedLevel)
 		history.Message = "restart container in docker which is don't have any instances in consul"
 		msg := "!consul check weak detected! start to restart container"
-		history.SetAlarmResult(ccu.slackChatAgency.SendMessage("pill", msg, _uuid))
+		history.SetAlarmResult(ccu.notify(history, domain.AlertLevelWeakDetected, "container_restart_started", msg))
 		history.IfContainerRestarted = true
 
 		var successSrvs, failSrvs []string
 		for _, srv := range unableSrvs {
-			container, err := ccu.dockerAgency.GetContainerWithServiceName(srv)
+			container, err := ccu.dockerAgency.GetContainerWithServiceName(ctx, srv)
 			if err != nil {
 				failSrvs = append(failSrvs, srv)
 				history.ProcessLevel.Append(errorLevel)
 				msg := fmt.Sprintf("!consul check error occurred! failed to get container, srv: %s, err: %v", srv, err)
-				_, _, _ = ccu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+				_, _, _ = ccu.notify(history, domain.AlertLevelUnhealthy, "container_unavailable", msg)
 				history.SetError(errors.Wrap(err, "failed to get container"))
 				continue
 			}
 
-			if err := ccu.dockerAgency.RemoveContainer(container.ID(), types.ContainerRemoveOptions{Force: true}); err != nil {
+			if err := ccu.dockerAgency.RemoveContainer(ctx, container.ID(), types.ContainerRemoveOptions{Force: true}); err != nil {
 				failSrvs = append(failSrvs, srv)
 				history.ProcessLevel.Append(errorLevel)
 				msg := fmt.Sprintf("!consul check error occurred! failed to restart container, id: %s, err: %v", container.ID(), err)
-				_, _, _ = ccu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+				_, _, _ = ccu.notify(history, domain.AlertLevelUnhealthy, "container_restart_failed", msg)
 				history.SetError(errors.Wrap(err, "failed to restart container"))
 			} else {
 				successSrvs = append(successSrvs, srv)
 			}
 		}
 
-		history.DeregisteredInstances = successSrvs
+		restarted := float64(len(successSrvs))
+		ccu.metricAgency.AddCounter(checkRemediationsMetric, remediationLabels("consul", "container_restarted"), restarted)
+		history.RestartedContainers = successSrvs
 		history.DeregisterFailedInstances = failSrvs
 		ccu.setStatus(consulStatusHealthy)
 	}
@@ -271,9 +439,169 @@ func (ccu *consulCheckUsecase) checkConsul(ctx context.Context) (history *domain
 	return
 }
 
+// ResetStatus reset status of consul check to healthy after administrator fixed problem & store history about that reset
+// it waits until running consul check process is finished, so that reset status is not overwritten by that process
+// Implement ResetStatus method of domain.ConsulCheckUseCase interface
+func (ccu *consulCheckUsecase) ResetStatus(ctx context.Context, by, reason string) (history *domain.ConsulCheckHistory, err error) {
+	if !ccu.runLocker.lock(ctx, overlapPolicyQueue) {
+		err = errors.Wrap(ctx.Err(), "context done while waiting running consul check process")
+		return
+	}
+	defer ccu.runLocker.unlock()
+
+	_uuid := uuid.New().String()
+	history = new(domain.ConsulCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = _uuid
+	history.ProcessLevel.Set(resetLevel)
+	history.SetResetResult(by, reason)
+	history.Message = fmt.Sprintf("consul check status is reset to healthy from %s", ccu.GetStatus())
+
+	ccu.setStatus(consulStatusHealthy)
+	msg := fmt.Sprintf("!consul check status reset! reset to healthy by %s (reason - %s)", by, reason)
+	alert := ccu.newAlert(history, domain.AlertLevelReset, msg)
+	history.SetAlarmResult(ccu.notifierAgency.Notify(alert))
+	ccu.escalationAgency.CloseEscalation(consulCheckKey, alert)
+
+	if b, err := ccu.historyRepo.Store(history); err != nil {
+		return history, errors.Wrapf(err, "failed to store consul check history, response: %s", string(b))
+	}
+
+	return history, nil
+}
+
+// escalate count cycle of consul check staying unhealthy & escalate incident with escalation agency if rule is satisfied
+func (ccu *consulCheckUsecase) escalate(history *domain.ConsulCheckHistory) {
+	ccu.mutex.Lock()
+	ccu.unhealthyCycles++
+	cycles, since := ccu.unhealthyCycles, ccu.statusChangedAt
+	ccu.mutex.Unlock()
+
+	// incident in maintenance window is not escalated, cycles are still counted to escalate after window ends
+	if id := ccu.maintenanceAgency.GetActiveWindow(consulCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+
+	msg := fmt.Sprintf("!consul check escalated! still unhealthy after %d cycles, please check for yourself", cycles)
+	history.SetAlarmResult(ccu.escalationAgency.Escalate(consulCheckKey, since, cycles, ccu.newAlert(history, domain.AlertLevelEscalated, msg)))
+}
+
 // setStatus set status field value using mutex Lock & Unlock
+// status is stored in status store only if changed, after unlocking mutex so that slow store doesn't block GetStatus
 func (ccu *consulCheckUsecase) setStatus(status consulCheckStatus) {
 	ccu.mutex.Lock()
-	defer ccu.mutex.Unlock()
+	changed := ccu.status != status
+	if changed {
+		ccu.statusChangedAt = domain.Now()
+		ccu.unhealthyCycles = 0
+	}
 	ccu.status = status
+	transitedAt := ccu.statusChangedAt
+	ccu.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+	if err := ccu.statusStoreAgency.StoreStatus(ctx, consulCheckKey, status.String(), transitedAt); err != nil {
+		log.Printf("failed to store consul check status in status store, err: %v", err)
+	}
+}
+
+// loadStatus load status of consul check saved in status store, so that status survives restart
+// recovering status is loaded as unhealthy, because recovering process was stopped by restart & should be checked
+func (ccu *consulCheckUsecase) loadStatus() {
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+
+	saved, transitedAt, err := ccu.statusStoreAgency.LoadStatus(ctx, consulCheckKey)
+	if err != nil {
+		log.Printf("failed to load consul check status from status store, err: %v", err)
+		return
+	}
+
+	for status := consulStatusHealthy; status <= consulStatusUnhealthy; status++ {
+		if status.String() != saved {
+			continue
+		}
+		if status == consulStatusRecovering {
+			status = consulStatusUnhealthy
+		}
+		ccu.status, ccu.statusChangedAt = status, transitedAt
+		return
+	}
+}
+
+// GetStatus return current status of consul check process as string
+// Implement GetStatus method of domain.ConsulCheckUseCase interface
+func (ccu *consulCheckUsecase) GetStatus() string {
+	ccu.mutex.Lock()
+	defer ccu.mutex.Unlock()
+	return ccu.status.String()
+}
+
+// GetLastHistory return consul check history produced in last check process
+// Implement GetLastHistory method of domain.ConsulCheckUseCase interface
+func (ccu *consulCheckUsecase) GetLastHistory() *domain.ConsulCheckHistory {
+	ccu.mutex.Lock()
+	defer ccu.mutex.Unlock()
+	return ccu.lastHistory
+}
+
+// setLastHistory set lastHistory field value using mutex Lock & Unlock
+func (ccu *consulCheckUsecase) setLastHistory(history *domain.ConsulCheckHistory) {
+	ccu.mutex.Lock()
+	defer ccu.mutex.Unlock()
+	ccu.lastHistory = history
+}
+
+// GetHistory return consul check history having uuid in history repository (nil if not exist)
+// Implement GetHistory method of domain.ConsulCheckUseCase interface
+func (ccu *consulCheckUsecase) GetHistory(ctx context.Context, uuid string) (history *domain.ConsulCheckHistory, err error) {
+	if history, err = ccu.historyRepo.GetByUUID(ctx, uuid); err != nil {
+		err = errors.Wrapf(err, "failed to get consul check history, uuid: %s", uuid)
+	}
+	return
+}
+
+// FindHistories return consul check histories stored between start & end, filtered by process level if level is not empty
+// Implement FindHistories method of domain.ConsulCheckUseCase interface
+func (ccu *consulCheckUsecase) FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*domain.ConsulCheckHistory, err error) {
+	if level == "" {
+		histories, err = ccu.historyRepo.FindByTimeRange(ctx, start, end)
+	} else {
+		histories, err = ccu.historyRepo.FindByProcessLevel(ctx, level, start, end)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to find consul check histories")
+	}
+	return
+}
+
+// FindLatestHistories return latest n consul check histories in history repository in order of newest first
+// Implement FindLatestHistories method of domain.ConsulCheckUseCase interface
+func (ccu *consulCheckUsecase) FindLatestHistories(ctx context.Context, n int) (histories []*domain.ConsulCheckHistory, err error) {
+	if histories, err = ccu.historyRepo.FindLatest(ctx, n); err != nil {
+		err = errors.Wrap(err, "failed to find latest consul check histories")
+	}
+	return
+}
+
+// recordMetrics record metrics about consul check process & instances per service in history using metric agency
+func (ccu *consulCheckUsecase) recordMetrics(history *domain.ConsulCheckHistory, elapsed time.Duration) {
+	var statuses []string
+	for s := consulStatusHealthy; s <= consulStatusUnhealthy; s++ {
+		statuses = append(statuses, s.String())
+	}
+	recordCommonMetrics(ccu.metricAgency, "consul", history.ProcessLevel.String(), elapsed, ccu.GetStatus(), statuses)
+
+	if level := history.ProcessLevel.String(); level == errorLevel || level == skippedLevel {
+		return
+	}
+	for srv, instances := range history.InstancesPerService {
+		ccu.metricAgency.SetGauge("srvcheck_consul_instances", map[string]string{"service": srv}, float64(len(instances)))
+	}
 }
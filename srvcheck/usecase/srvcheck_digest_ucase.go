@@ -0,0 +1,137 @@
+// srvcheck_digest_ucase.go is file that define usecase implementation about digest of srvcheck domain
+// digest usecase summarize histories read from elasticsearch, swarmpit, consul check history repository & post it as report
+
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// digestUsecase implement ServiceCheckDigestUseCase interface in domain and used in delivery layer
+type digestUsecase struct {
+	// elasticsearchHistoryRepo is used for reading elasticsearch check history stored in period
+	elasticsearchHistoryRepo domain.ElasticsearchCheckHistoryRepository
+
+	// swarmpitHistoryRepo is used for reading swarmpit check history stored in period
+	swarmpitHistoryRepo domain.SwarmpitCheckHistoryRepository
+
+	// consulHistoryRepo is used for reading consul check history stored in period
+	consulHistoryRepo domain.ConsulCheckHistoryRepository
+
+	// reportAgency is used as agency about report to post digest
+	reportAgency reportAgency
+}
+
+// NewDigestUsecase function return digestUsecase ptr instance after initializing
+func NewDigestUsecase(
+	ehr domain.ElasticsearchCheckHistoryRepository,
+	shr domain.SwarmpitCheckHistoryRepository,
+	chr domain.ConsulCheckHistoryRepository,
+	ra reportAgency,
+) domain.ServiceCheckDigestUseCase {
+	return &digestUsecase{
+		elasticsearchHistoryRepo: ehr,
+		swarmpitHistoryRepo:      shr,
+		consulHistoryRepo:        chr,
+		reportAgency:             ra,
+	}
+}
+
+// Digest summarize service check histories stored between start & end, and post it as report with report agency
+// Implement Digest method of domain.ServiceCheckDigestUseCase interface
+func (dgu *digestUsecase) Digest(ctx context.Context, period string, start, end time.Time) (digest *domain.ServiceCheckDigest, err error) {
+	digest = &domain.ServiceCheckDigest{
+		Period:      period,
+		Start:       start,
+		End:         end,
+		LevelCounts: map[string]map[string]int{},
+	}
+
+	elasticsearchHistories, err := dgu.elasticsearchHistoryRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		err = errors.Wrap(err, "failed to find elasticsearch check histories")
+		return
+	}
+	digest.LevelCounts["elasticsearch"] = map[string]int{}
+	for _, history := range elasticsearchHistories {
+		countLevels(digest.LevelCounts["elasticsearch"], history.ProcessLevel)
+		digest.DeletedJaegerIndices += len(history.DeletedJaegerIndices)
+	}
+
+	swarmpitHistories, err := dgu.swarmpitHistoryRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		err = errors.Wrap(err, "failed to find swarmpit check histories")
+		return
+	}
+	digest.LevelCounts["swarmpit"] = map[string]int{}
+	for _, history := range swarmpitHistories {
+		countLevels(digest.LevelCounts["swarmpit"], history.ProcessLevel)
+		if history.IfSwarmpitAppRestarted {
+			digest.RestartedContainers++
+		}
+	}
+
+	consulHistories, err := dgu.consulHistoryRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		err = errors.Wrap(err, "failed to find consul check histories")
+		return
+	}
+	digest.LevelCounts["consul"] = map[string]int{}
+	for _, history := range consulHistories {
+		countLevels(digest.LevelCounts["consul"], history.ProcessLevel)
+		digest.DeregisteredInstances += len(history.DeregisteredInstances)
+		digest.RestartedContainers += len(history.RestartedContainers)
+	}
+
+	title := fmt.Sprintf("service check %s digest (%s ~ %s)", period, start.Format(digestTimeLayout), end.Format(digestTimeLayout))
+	lines := levelCountLines(digest.LevelCounts, []string{"elasticsearch", "swarmpit", "consul"})
+	lines = append(lines,
+		fmt.Sprintf("jaeger indices deleted - %d", digest.DeletedJaegerIndices),
+		fmt.Sprintf("consul instances deregistered - %d", digest.DeregisteredInstances),
+		fmt.Sprintf("containers restarted - %d", digest.RestartedContainers),
+	)
+
+	if _, err = dgu.reportAgency.PostReport(title, lines); err != nil {
+		err = errors.Wrap(err, "failed to post service check digest")
+	}
+	return
+}
+
+// digestTimeLayout is layout of time printed in title of digest report
+const digestTimeLayout = "2006-01-02 15:04"
+
+// countLevels add count of each process level in history to counts, history having several level is counted per level
+func countLevels(counts map[string]int, levels []string) {
+	for _, level := range levels {
+		counts[level]++
+	}
+}
+
+// levelCountLines return lines of process level count per check in order of checks, level is sorted by name
+func levelCountLines(levelCounts map[string]map[string]int, checks []string) (lines []string) {
+	for _, check := range checks {
+		counts := levelCounts[check]
+		levels := make([]string, 0, len(counts))
+		for level := range counts {
+			levels = append(levels, level)
+		}
+		sort.Strings(levels)
+
+		parts := make([]string, 0, len(levels))
+		for _, level := range levels {
+			parts = append(parts, fmt.Sprintf("%s %d", level, counts[level]))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, "no history")
+		}
+		lines = append(lines, fmt.Sprintf("%s check - %s", check, strings.Join(parts, ", ")))
+	}
+	return
+}
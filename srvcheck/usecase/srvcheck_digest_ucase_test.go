@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fakeReportAgency is reportAgency recording lines of posted report
+type fakeReportAgency struct {
+	lines []string
+}
+
+func (ra *fakeReportAgency) PostReport(title string, lines []string) (time.Time, error) {
+	ra.lines = lines
+	return time.Now(), nil
+}
+
+// fake repositories return histories restored from dotted map, same as histories read from real repository
+type fakeElasticsearchRepo struct {
+	domain.ElasticsearchCheckHistoryRepository
+	histories []*domain.ElasticsearchCheckHistory
+}
+
+func (r fakeElasticsearchRepo) FindByTimeRange(context.Context, time.Time, time.Time) (histories []*domain.ElasticsearchCheckHistory, _ error) {
+	for _, history := range r.histories {
+		restored := new(domain.ElasticsearchCheckHistory)
+		restored.FillFromDottedMap(history.DottedMapWithPrefix(""))
+		histories = append(histories, restored)
+	}
+	return
+}
+
+type fakeSwarmpitRepo struct {
+	domain.SwarmpitCheckHistoryRepository
+	histories []*domain.SwarmpitCheckHistory
+}
+
+func (r fakeSwarmpitRepo) FindByTimeRange(context.Context, time.Time, time.Time) (histories []*domain.SwarmpitCheckHistory, _ error) {
+	for _, history := range r.histories {
+		restored := new(domain.SwarmpitCheckHistory)
+		restored.FillFromDottedMap(history.DottedMapWithPrefix(""))
+		histories = append(histories, restored)
+	}
+	return
+}
+
+type fakeConsulRepo struct {
+	domain.ConsulCheckHistoryRepository
+	histories []*domain.ConsulCheckHistory
+}
+
+func (r fakeConsulRepo) FindByTimeRange(context.Context, time.Time, time.Time) (histories []*domain.ConsulCheckHistory, _ error) {
+	for _, history := range r.histories {
+		restored := new(domain.ConsulCheckHistory)
+		restored.FillFromDottedMap(history.DottedMapWithPrefix(""))
+		histories = append(histories, restored)
+	}
+	return
+}
+
+func TestDigestCountsRestartedContainers(t *testing.T) {
+	restartedSwarmpit := &domain.SwarmpitCheckHistory{IfSwarmpitAppRestarted: true}
+	restartedSwarmpit.FillPrivateComponent()
+	restartedSwarmpit.ProcessLevel.Set(weakDetectedLevel)
+
+	deregistered := &domain.ConsulCheckHistory{DeregisteredInstances: []string{"auth-1"}}
+	deregistered.FillPrivateComponent()
+	deregistered.ProcessLevel.Set(weakDetectedLevel)
+
+	restarted := &domain.ConsulCheckHistory{RestartedContainers: []string{"club-1", "outing-1"}}
+	restarted.FillPrivateComponent()
+	restarted.ProcessLevel.Set(unhealthyLevel)
+	restarted.ProcessLevel.Append(recoveringLevel)
+
+	ra := &fakeReportAgency{}
+	du := NewDigestUsecase(
+		fakeElasticsearchRepo{},
+		fakeSwarmpitRepo{histories: []*domain.SwarmpitCheckHistory{restartedSwarmpit}},
+		fakeConsulRepo{histories: []*domain.ConsulCheckHistory{deregistered, restarted}},
+		ra,
+	)
+
+	end := time.Now()
+	digest, err := du.Digest(context.Background(), "daily", end.AddDate(0, 0, -1), end)
+	if err != nil {
+		t.Fatalf("digest should succeed, err: %v", err)
+	}
+
+	if digest.RestartedContainers != 3 {
+		t.Errorf("containers restarted in swarmpit & consul check should be counted, got: %d", digest.RestartedContainers)
+	}
+	if digest.DeregisteredInstances != 1 {
+		t.Errorf("restarted containers should not be counted as deregistered instances, got: %d", digest.DeregisteredInstances)
+	}
+	for _, line := range []string{"containers restarted - 3", "consul instances deregistered - 1"} {
+		if !containsLine(ra.lines, line) {
+			t.Errorf("report should have line %q, got: %v", line, ra.lines)
+		}
+	}
+}
+
+func TestDigestCountsLevelsAndDeletedIndices(t *testing.T) {
+	healthy := &domain.ElasticsearchCheckHistory{}
+	healthy.FillPrivateComponent()
+	healthy.ProcessLevel.Set(healthyLevel)
+
+	deleted := &domain.ElasticsearchCheckHistory{DeletedJaegerIndices: []string{"jaeger-span-2021-03-01", "jaeger-span-2021-03-02"}}
+	deleted.FillPrivateComponent()
+	deleted.ProcessLevel.Set(weakDetectedLevel)
+	deleted.ProcessLevel.Append(recoveringLevel)
+	deleted.ProcessLevel.Append(recoveredLevel)
+
+	failed := &domain.ConsulCheckHistory{}
+	failed.FillPrivateComponent()
+	failed.ProcessLevel.Set(errorLevel)
+
+	ra := &fakeReportAgency{}
+	du := NewDigestUsecase(
+		fakeElasticsearchRepo{histories: []*domain.ElasticsearchCheckHistory{healthy, healthy, deleted}},
+		fakeSwarmpitRepo{},
+		fakeConsulRepo{histories: []*domain.ConsulCheckHistory{failed}},
+		ra,
+	)
+
+	end := time.Now()
+	digest, err := du.Digest(context.Background(), "daily", end.AddDate(0, 0, -1), end)
+	if err != nil {
+		t.Fatalf("digest should succeed, err: %v", err)
+	}
+
+	wantCounts := map[string]map[string]int{
+		"elasticsearch": {healthyLevel: 2, weakDetectedLevel: 1, recoveringLevel: 1, recoveredLevel: 1},
+		"swarmpit":      {},
+		"consul":        {errorLevel: 1},
+	}
+	if !reflect.DeepEqual(digest.LevelCounts, wantCounts) {
+		t.Errorf("level counts should be %v, got: %v", wantCounts, digest.LevelCounts)
+	}
+	if digest.DeletedJaegerIndices != 2 {
+		t.Errorf("jaeger indices deleted in elasticsearch check should be counted, got: %d", digest.DeletedJaegerIndices)
+	}
+	for _, line := range []string{
+		"elasticsearch check - HEALTHY 2, RECOVERED 1, RECOVERING 1, WEAK_DETECTED 1",
+		"swarmpit check - no history",
+		"consul check - ERROR 1",
+		"jaeger indices deleted - 2",
+	} {
+		if !containsLine(ra.lines, line) {
+			t.Errorf("report should have line %q, got: %v", line, ra.lines)
+		}
+	}
+}
+
+// containsLine return if lines has line same with target
+func containsLine(lines []string, target string) bool {
+	for _, line := range lines {
+		if line == target {
+			return true
+		}
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"log"
 	"sync"
 	"time"
 
@@ -23,6 +24,22 @@ const (
 	elasticsearchStatusUnhealthy                                  // represent elasticsearch check status is unhealthy
 )
 
+// String method return string represent elasticsearch check status, which is used in delivery layer
+func (s elasticsearchCheckStatus) String() string {
+	switch s {
+	case elasticsearchStatusHealthy:
+		return "HEALTHY"
+	case elasticsearchStatusRecovering:
+		return "RECOVERING"
+	case elasticsearchStatusUnhealthy:
+		return "UNHEALTHY"
+	}
+	return "UNKNOWN"
+}
+
+// elasticsearchCheckKey is key identifying elasticsearch check in status store agency & maintenance window
+const elasticsearchCheckKey = "srvcheck.elasticsearch"
+
 // elasticsearchCheckUsecase implement ElasticsearchCheckUsecase interface in domain and used in delivery layer
 type elasticsearchCheckUsecase struct {
 	// myCfg is used for getting elasticsearch check usecase config
@@ -31,8 +48,20 @@ type elasticsearchCheckUsecase struct {
 	// historyRepo is used for store elasticsearch check history and injected from outside
 	historyRepo domain.ElasticsearchCheckHistoryRepository
 
-	// slackChat is used for agent slack API about chatting
-	slackChatAgency slackChatAgency
+	// notifierAgency is used for agent notifiers delivering alert
+	notifierAgency notifierAgency
+
+	// metricAgency is used as agency about metric collector to expose numbers computed in check process
+	metricAgency metricAgency
+
+	// statusStoreAgency is used as agency about status store to keep status of state machine across restart
+	statusStoreAgency statusStoreAgency
+
+	// maintenanceAgency is used for checking if check or container is in maintenance window
+	maintenanceAgency maintenanceAgency
+
+	// escalationAgency is used for escalating incident of elasticsearch check staying unhealthy long
+	escalationAgency escalationAgency
 
 	// elasticsearchAgency is used as agency about elasticsearch API
 	elasticsearchAgency elasticsearchAgency
@@ -40,8 +69,20 @@ type elasticsearchCheckUsecase struct {
 	// status represent current process status of elasticsearch health check
 	status elasticsearchCheckStatus
 
+	// statusChangedAt represent time when status field value was changed last
+	statusChangedAt time.Time
+
+	// unhealthyCycles represent how many check cycles have run since status was changed to unhealthy
+	unhealthyCycles int
+
+	// lastHistory represent elasticsearch check history produced in last check process
+	lastHistory *domain.ElasticsearchCheckHistory
+
 	// mutex help to prevent race condition when set status field value
 	mutex sync.Mutex
+
+	// runLocker help to prevent elasticsearch check process from running at the same time
+	runLocker checkRunLocker
 }
 
 // elasticsearchCheckUsecaseConfig is the config getter interface for elasticsearch check usecase
@@ -49,6 +90,9 @@ type elasticsearchCheckUsecaseConfig interface {
 	// get common config method from embedding serviceCheckUsecaseComponentConfig
 	serviceCheckUsecaseComponentConfig
 
+	// ElasticsearchCheckTimeOut method returns time.Duration represent deadline of one elasticsearch check process
+	ElasticsearchCheckTimeOut() time.Duration
+
 	// MaximumShardsNumber method returns int represent maximum shards number
 	MaximumShardsNumber() int
 
@@ -62,7 +106,7 @@ type elasticsearchCheckUsecaseConfig interface {
 // elasticsearchAgency is interface that agent elasticsearch with HTTP API
 type elasticsearchAgency interface {
 	// GetClusterHealth return interface have various get method about cluster health inform
-	GetClusterHealth() (cluster interface {
+	GetClusterHealth(ctx context.Context) (cluster interface {
 		ActivePrimaryShards() int     // get active primary shards number of cluster
 		ActiveShards() int            // get active shards number of cluster
 		UnassignedShards() int        // get unassigned shards number of cluster
@@ -70,47 +114,113 @@ type elasticsearchAgency interface {
 	}, err error)
 
 	// GetIndicesWithRegexp return indices list with regexp pattern
-	GetIndicesWithPatterns(patterns []string) (indices interface {
+	GetIndicesWithPatterns(ctx context.Context, patterns []string) (indices interface {
 		SetMinLifeCycle(cycle time.Duration) // set min life cycle of index of indices
 		IndexNames() []string                // get index name list of indices
 	}, err error)
 
 	// DeleteIndices method delete indices in list received from parameter
-	DeleteIndices(indices []string) (err error)
+	DeleteIndices(ctx context.Context, indices []string) (err error)
 }
 
 // NewElasticsearchCheckUsecase function return elasticsearchCheckUseCase ptr instance after initializing
 func NewElasticsearchCheckUsecase(
 	cfg elasticsearchCheckUsecaseConfig,
 	chr domain.ElasticsearchCheckHistoryRepository,
-	sca slackChatAgency,
+	na notifierAgency,
+	ma metricAgency,
+	ssa statusStoreAgency,
+	mta maintenanceAgency,
+	eca escalationAgency,
 	ea elasticsearchAgency,
 ) domain.ElasticsearchCheckUseCase {
-	return &elasticsearchCheckUsecase{
+	usecase := &elasticsearchCheckUsecase{
 		// initialize field with parameter received from caller
 		myCfg:               cfg,
 		historyRepo:         chr,
-		slackChatAgency:     sca,
+		notifierAgency:      na,
+		metricAgency:        ma,
+		statusStoreAgency:   ssa,
+		maintenanceAgency:   mta,
+		escalationAgency:    eca,
 		elasticsearchAgency: ea,
 
 		// initialize field with default value
-		status: elasticsearchStatusHealthy,
-		mutex:  sync.Mutex{},
+		status:    elasticsearchStatusHealthy,
+		mutex:     sync.Mutex{},
+		runLocker: newCheckRunLocker(),
 	}
+
+	usecase.loadStatus()
+	return usecase
 }
 
 // CheckElasticsearch check elasticsearch health with checkElasticsearch method & store check history in repository
 // Implement CheckElasticsearch method of ElasticsearchCheckUseCase interface
-func (ecu *elasticsearchCheckUsecase) CheckElasticsearch(ctx context.Context) (err error) {
-	history := ecu.checkElasticsearch(ctx)
+func (ecu *elasticsearchCheckUsecase) CheckElasticsearch(ctx context.Context) (history *domain.ElasticsearchCheckHistory, err error) {
+	start := time.Now()
+	if ecu.runLocker.lock(ctx, ecu.myCfg.CheckOverlapPolicy()) {
+		toCtx, cancel := context.WithTimeout(ctx, ecu.myCfg.ElasticsearchCheckTimeOut())
+		history = ecu.checkElasticsearch(toCtx)
+		if toCtx.Err() != nil {
+			if history.ProcessLevel.Last() != errorLevel {
+				history.ProcessLevel.Append(errorLevel)
+			}
+			switch {
+			case history.Error != nil:
+			case toCtx.Err() == context.DeadlineExceeded:
+				history.SetError(errors.Wrapf(toCtx.Err(), "elasticsearch check process is timed out (%s)", ecu.myCfg.ElasticsearchCheckTimeOut()))
+			default:
+				history.SetError(errors.Wrap(toCtx.Err(), "elasticsearch check process is canceled"))
+			}
+		}
+		cancel()
+		ecu.runLocker.unlock()
+		ecu.setLastHistory(history)
+	} else {
+		history = ecu.skippedHistory()
+	}
+	ecu.recordMetrics(history, time.Since(start))
 
 	if b, err := ecu.historyRepo.Store(history); err != nil {
-		return errors.Wrapf(err, "failed to store elasticsearch check history, response: %s", string(b))
+		return history, errors.Wrapf(err, "failed to store elasticsearch check history, response: %s", string(b))
 	}
 
+	return history, nil
+}
+
+// skippedHistory return elasticsearch check history represent that check process is skipped as same process is already running
+func (ecu *elasticsearchCheckUsecase) skippedHistory() (history *domain.ElasticsearchCheckHistory) {
+	history = new(domain.ElasticsearchCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = uuid.New().String()
+	history.ProcessLevel.Set(skippedLevel)
+	history.Message = "elasticsearch check is skipped as previous elasticsearch check process is still running"
+	return
+}
+
+// newAlert return alert about elasticsearch check history with thresholds configured in elasticsearch check config
+func (ecu *elasticsearchCheckUsecase) newAlert(history *domain.ElasticsearchCheckHistory, level domain.AlertLevel, text string) (alert *domain.Alert) {
+	alert = history.NewAlert(level, text)
+	alert.Thresholds["maximum_shards_number"] = ecu.myCfg.MaximumShardsNumber()
+	alert.Thresholds["jaeger_index_pattern"] = ecu.myCfg.JaegerIndexPattern()
+	alert.Thresholds["jaeger_index_min_life_cycle"] = ecu.myCfg.JaegerIndexMinLifeCycle()
 	return
 }
 
+// notify deliver alert about elasticsearch check history to notifier agency, except when elasticsearch check is in maintenance window
+// if alert is suppressed by maintenance window, id of that window is set in history
+// reason is stable code of why alert is produced (Ex, container_remove_failed), used for deduplicating it in notifier
+func (ecu *elasticsearchCheckUsecase) notify(history *domain.ElasticsearchCheckHistory, level domain.AlertLevel, reason, text string) (t time.Time, _text string, err error) {
+	if id := ecu.maintenanceAgency.GetActiveWindow(elasticsearchCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+	alert := ecu.newAlert(history, level, text)
+	alert.Reason = reason
+	return ecu.notifierAgency.Notify(alert)
+}
+
 // method processed with below logic about elasticsearch health check according to current check status
 // 0 : 정상적으로 인지된 상태 (상태 확인 수행)
 // 0 -> 1 : Jaeger Index 삭제 실행 (Jaeger Index 삭제 알림 발행)
@@ -125,12 +235,12 @@ func (ecu *elasticsearchCheckUsecase) checkElasticsearch(ctx context.Context) (h
 	history.FillPrivateComponent()
 	history.UUID = _uuid
 
-	cluster, err := ecu.elasticsearchAgency.GetClusterHealth()
+	cluster, err := ecu.elasticsearchAgency.GetClusterHealth(ctx)
 	if err != nil {
 		history.ProcessLevel.Set(errorLevel)
 		history.SetError(errors.Wrap(err, "failed to get cluster health"))
 		msg := "!elasticsearch check error occurred! unable to get cluster health"
-		history.SetAlarmResult(ecu.slackChatAgency.SendMessage("x", msg, _uuid))
+		history.SetAlarmResult(ecu.notify(history, domain.AlertLevelError, "cluster_health_unavailable", msg))
 		return
 	}
 	history.SetClusterHealth(cluster)
@@ -149,50 +259,62 @@ func (ecu *elasticsearchCheckUsecase) checkElasticsearch(ctx context.Context) (h
 			history.ProcessLevel.Set(recoveredLevel)
 			history.Message = "elasticsearch check is recovered to be healthy"
 			msg := fmt.Sprintf("!elasticsearch check recovered to health! total shards - %d", totalShards.V)
-			_, _, _ = ecu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = ecu.notify(history, domain.AlertLevelRecovered, "recovered", msg)
+			ecu.escalationAgency.CloseEscalation(elasticsearchCheckKey, ecu.newAlert(history, domain.AlertLevelRecovered, msg))
 		} else {
 			history.ProcessLevel.Set(unhealthyLevel)
 			history.Message = "elasticsearch check is unhealthy now"
+			ecu.escalate(history)
 		}
 		return
 	}
 
+	// suppress remediation & alarm if elasticsearch check is in maintenance window, history is still stored with measured value
+	if id := ecu.maintenanceAgency.GetActiveWindow(elasticsearchCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		history.ProcessLevel.Set(maintenanceLevel)
+		history.Message = "elasticsearch check is in maintenance window, so remediation & alarm are suppressed"
+		return
+	}
+
 	if totalShards.isMoreThan(ecu.myCfg.MaximumShardsNumber()) {
 		ecu.setStatus(elasticsearchStatusRecovering)
 		history.ProcessLevel.Set(weakDetectedLevel)
 		msg := "!elasticsearch check weak detected! start to delete jaeger index"
-		history.SetAlarmResult(ecu.slackChatAgency.SendMessage("pill", msg, _uuid))
+		history.SetAlarmResult(ecu.notify(history, domain.AlertLevelWeakDetected, "index_delete_started", msg))
 
-		indices, err := ecu.elasticsearchAgency.GetIndicesWithPatterns([]string{ecu.myCfg.JaegerIndexPattern()})
+		indices, err := ecu.elasticsearchAgency.GetIndicesWithPatterns(ctx, []string{ecu.myCfg.JaegerIndexPattern()})
 		if err != nil {
 			ecu.setStatus(elasticsearchStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!elasticsearch check error occurred! failed to get indices, please check for yourself"
-			_, _, _ = ecu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = ecu.notify(history, domain.AlertLevelUnhealthy, "indices_unavailable", msg)
 			history.SetError(errors.Wrap(err, "failed to get indices with pattern"))
 			return
 		}
 		indices.SetMinLifeCycle(ecu.myCfg.JaegerIndexMinLifeCycle())
 
-		if err := ecu.elasticsearchAgency.DeleteIndices(indices.IndexNames()); err != nil {
+		if err := ecu.elasticsearchAgency.DeleteIndices(ctx, indices.IndexNames()); err != nil {
 			ecu.setStatus(elasticsearchStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!elasticsearch check error occurred! failed to delete indices, please check for yourself"
-			_, _, _ = ecu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = ecu.notify(history, domain.AlertLevelRecoveryError, "index_delete_failed", msg)
 			history.SetError(errors.Wrap(err, "failed to delete indices"))
 			return
 		} else {
+			deleted := float64(len(indices.IndexNames()))
+			ecu.metricAgency.AddCounter(checkRemediationsMetric, remediationLabels("elasticsearch", "index_deleted"), deleted)
 			history.IfJaegerIndexDeleted = true
 			history.DeletedJaegerIndices = indices.IndexNames()
 			history.Message = "pruned docker system as current disk capacity is less than the minimum"
 		}
 
-		againCluster, err := ecu.elasticsearchAgency.GetClusterHealth()
+		againCluster, err := ecu.elasticsearchAgency.GetClusterHealth(ctx)
 		if err != nil {
 			ecu.setStatus(elasticsearchStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!elasticsearch check error occurred! failed to again get cluster health, please check for yourself"
-			_, _, _ = ecu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = ecu.notify(history, domain.AlertLevelUnhealthy, "cluster_health_unavailable_after_remediation", msg)
 			history.SetError(errors.Wrap(err, "failed to again get cluster health again"))
 			return
 		}
@@ -202,11 +324,11 @@ func (ecu *elasticsearchCheckUsecase) checkElasticsearch(ctx context.Context) (h
 		if againTotalShards.isLessThan(ecu.myCfg.MaximumShardsNumber()) {
 			ecu.setStatus(elasticsearchStatusHealthy)
 			msg := fmt.Sprintf("!elasticsearch check is recovered! total shards - %d", againTotalShards.V)
-			_, _, _ = ecu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = ecu.notify(history, domain.AlertLevelRecovered, "recovered_by_remediation", msg)
 		} else {
 			ecu.setStatus(elasticsearchStatusUnhealthy)
 			msg := "!elasticsearch check has deteriorated! please check for yourself"
-			_, _, _ = ecu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = ecu.notify(history, domain.AlertLevelUnhealthy, "deteriorated", msg)
 		}
 	} else {
 		history.ProcessLevel.Set(healthyLevel)
@@ -216,9 +338,170 @@ func (ecu *elasticsearchCheckUsecase) checkElasticsearch(ctx context.Context) (h
 	return
 }
 
+// ResetStatus reset status of elasticsearch check to healthy after administrator fixed problem & store history about that reset
+// it waits until running elasticsearch check process is finished, so that reset status is not overwritten by that process
+// Implement ResetStatus method of domain.ElasticsearchCheckUseCase interface
+func (ecu *elasticsearchCheckUsecase) ResetStatus(ctx context.Context, by, reason string) (history *domain.ElasticsearchCheckHistory, err error) {
+	if !ecu.runLocker.lock(ctx, overlapPolicyQueue) {
+		err = errors.Wrap(ctx.Err(), "context done while waiting running elasticsearch check process")
+		return
+	}
+	defer ecu.runLocker.unlock()
+
+	_uuid := uuid.New().String()
+	history = new(domain.ElasticsearchCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = _uuid
+	history.ProcessLevel.Set(resetLevel)
+	history.SetResetResult(by, reason)
+	history.Message = fmt.Sprintf("elasticsearch check status is reset to healthy from %s", ecu.GetStatus())
+
+	ecu.setStatus(elasticsearchStatusHealthy)
+	msg := fmt.Sprintf("!elasticsearch check status reset! reset to healthy by %s (reason - %s)", by, reason)
+	alert := ecu.newAlert(history, domain.AlertLevelReset, msg)
+	history.SetAlarmResult(ecu.notifierAgency.Notify(alert))
+	ecu.escalationAgency.CloseEscalation(elasticsearchCheckKey, alert)
+
+	if b, err := ecu.historyRepo.Store(history); err != nil {
+		return history, errors.Wrapf(err, "failed to store elasticsearch check history, response: %s", string(b))
+	}
+
+	return history, nil
+}
+
+// escalate count cycle of elasticsearch check staying unhealthy & escalate incident with escalation agency if rule is satisfied
+func (ecu *elasticsearchCheckUsecase) escalate(history *domain.ElasticsearchCheckHistory) {
+	ecu.mutex.Lock()
+	ecu.unhealthyCycles++
+	cycles, since := ecu.unhealthyCycles, ecu.statusChangedAt
+	ecu.mutex.Unlock()
+
+	// incident in maintenance window is not escalated, cycles are still counted to escalate after window ends
+	if id := ecu.maintenanceAgency.GetActiveWindow(elasticsearchCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+
+	msg := fmt.Sprintf("!elasticsearch check escalated! still unhealthy after %d cycles, please check for yourself", cycles)
+	history.SetAlarmResult(ecu.escalationAgency.Escalate(elasticsearchCheckKey, since, cycles, ecu.newAlert(history, domain.AlertLevelEscalated, msg)))
+}
+
 // setStatus set status field value using mutex Lock & Unlock
+// status is stored in status store only if changed, after unlocking mutex so that slow store doesn't block GetStatus
 func (ecu *elasticsearchCheckUsecase) setStatus(status elasticsearchCheckStatus) {
 	ecu.mutex.Lock()
-	defer ecu.mutex.Unlock()
+	changed := ecu.status != status
+	if changed {
+		ecu.statusChangedAt = domain.Now()
+		ecu.unhealthyCycles = 0
+	}
 	ecu.status = status
+	transitedAt := ecu.statusChangedAt
+	ecu.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+	if err := ecu.statusStoreAgency.StoreStatus(ctx, elasticsearchCheckKey, status.String(), transitedAt); err != nil {
+		log.Printf("failed to store elasticsearch check status in status store, err: %v", err)
+	}
+}
+
+// loadStatus load status of elasticsearch check saved in status store, so that status survives restart
+// recovering status is loaded as unhealthy, because recovering process was stopped by restart & should be checked
+func (ecu *elasticsearchCheckUsecase) loadStatus() {
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+
+	saved, transitedAt, err := ecu.statusStoreAgency.LoadStatus(ctx, elasticsearchCheckKey)
+	if err != nil {
+		log.Printf("failed to load elasticsearch check status from status store, err: %v", err)
+		return
+	}
+
+	for status := elasticsearchStatusHealthy; status <= elasticsearchStatusUnhealthy; status++ {
+		if status.String() != saved {
+			continue
+		}
+		if status == elasticsearchStatusRecovering {
+			status = elasticsearchStatusUnhealthy
+		}
+		ecu.status, ecu.statusChangedAt = status, transitedAt
+		return
+	}
+}
+
+// GetStatus return current status of elasticsearch check process as string
+// Implement GetStatus method of domain.ElasticsearchCheckUseCase interface
+func (ecu *elasticsearchCheckUsecase) GetStatus() string {
+	ecu.mutex.Lock()
+	defer ecu.mutex.Unlock()
+	return ecu.status.String()
+}
+
+// GetLastHistory return elasticsearch check history produced in last check process
+// Implement GetLastHistory method of domain.ElasticsearchCheckUseCase interface
+func (ecu *elasticsearchCheckUsecase) GetLastHistory() *domain.ElasticsearchCheckHistory {
+	ecu.mutex.Lock()
+	defer ecu.mutex.Unlock()
+	return ecu.lastHistory
+}
+
+// setLastHistory set lastHistory field value using mutex Lock & Unlock
+func (ecu *elasticsearchCheckUsecase) setLastHistory(history *domain.ElasticsearchCheckHistory) {
+	ecu.mutex.Lock()
+	defer ecu.mutex.Unlock()
+	ecu.lastHistory = history
+}
+
+// GetHistory return elasticsearch check history having uuid in history repository (nil if not exist)
+// Implement GetHistory method of domain.ElasticsearchCheckUseCase interface
+func (ecu *elasticsearchCheckUsecase) GetHistory(ctx context.Context, uuid string) (history *domain.ElasticsearchCheckHistory, err error) {
+	if history, err = ecu.historyRepo.GetByUUID(ctx, uuid); err != nil {
+		err = errors.Wrapf(err, "failed to get elasticsearch check history, uuid: %s", uuid)
+	}
+	return
+}
+
+// FindHistories return elasticsearch check histories stored between start & end, filtered by process level if level is not empty
+// Implement FindHistories method of domain.ElasticsearchCheckUseCase interface
+func (ecu *elasticsearchCheckUsecase) FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	if level == "" {
+		histories, err = ecu.historyRepo.FindByTimeRange(ctx, start, end)
+	} else {
+		histories, err = ecu.historyRepo.FindByProcessLevel(ctx, level, start, end)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to find elasticsearch check histories")
+	}
+	return
+}
+
+// FindLatestHistories return latest n elasticsearch check histories in history repository in order of newest first
+// Implement FindLatestHistories method of domain.ElasticsearchCheckUseCase interface
+func (ecu *elasticsearchCheckUsecase) FindLatestHistories(ctx context.Context, n int) (histories []*domain.ElasticsearchCheckHistory, err error) {
+	if histories, err = ecu.historyRepo.FindLatest(ctx, n); err != nil {
+		err = errors.Wrap(err, "failed to find latest elasticsearch check histories")
+	}
+	return
+}
+
+// recordMetrics record metrics about elasticsearch check process & cluster health in history using metric agency
+func (ecu *elasticsearchCheckUsecase) recordMetrics(history *domain.ElasticsearchCheckHistory, elapsed time.Duration) {
+	var statuses []string
+	for s := elasticsearchStatusHealthy; s <= elasticsearchStatusUnhealthy; s++ {
+		statuses = append(statuses, s.String())
+	}
+	recordCommonMetrics(ecu.metricAgency, "elasticsearch", history.ProcessLevel.String(), elapsed, ecu.GetStatus(), statuses)
+
+	if level := history.ProcessLevel.String(); level == errorLevel || level == skippedLevel {
+		return
+	}
+	ecu.metricAgency.SetGauge("srvcheck_elasticsearch_active_primary_shards", nil, float64(history.ActivePrimaryShards))
+	ecu.metricAgency.SetGauge("srvcheck_elasticsearch_active_shards", nil, float64(history.ActiveShards))
+	ecu.metricAgency.SetGauge("srvcheck_elasticsearch_unassigned_shards", nil, float64(history.UnassignedShards))
+	ecu.metricAgency.SetGauge("srvcheck_elasticsearch_active_shards_percent", nil, history.ActiveShardsPercent)
 }
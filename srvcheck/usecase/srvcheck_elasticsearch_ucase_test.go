@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fakeElasticsearchCheckConfig is elasticsearchCheckUsecaseConfig returning fixed values
+type fakeElasticsearchCheckConfig struct{}
+
+func (fakeElasticsearchCheckConfig) CheckOverlapPolicy() string               { return overlapPolicySkip }
+func (fakeElasticsearchCheckConfig) ElasticsearchCheckTimeOut() time.Duration { return time.Minute }
+func (fakeElasticsearchCheckConfig) MaximumShardsNumber() int                 { return 1000 }
+func (fakeElasticsearchCheckConfig) JaegerIndexPattern() string               { return "jaeger-*" }
+func (fakeElasticsearchCheckConfig) JaegerIndexMinLifeCycle() time.Duration   { return time.Hour }
+
+// storingElasticsearchRepo is elasticsearch check history repository recording stored histories
+type storingElasticsearchRepo struct {
+	domain.ElasticsearchCheckHistoryRepository
+	stored []*domain.ElasticsearchCheckHistory
+}
+
+func (r *storingElasticsearchRepo) Store(history *domain.ElasticsearchCheckHistory) ([]byte, error) {
+	r.stored = append(r.stored, history)
+	return nil, nil
+}
+
+// cancelingElasticsearchAgency is elasticsearchAgency canceling check context while getting cluster health
+type cancelingElasticsearchAgency struct {
+	elasticsearchAgency
+	cancel context.CancelFunc
+}
+
+func (a cancelingElasticsearchAgency) GetClusterHealth(ctx context.Context) (cluster interface {
+	ActivePrimaryShards() int
+	ActiveShards() int
+	UnassignedShards() int
+	ActiveShardsPercent() float64
+}, err error) {
+	a.cancel()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// fake agencies doing nothing, used for agency not related to test
+type nopNotifierAgency struct{}
+
+func (nopNotifierAgency) Notify(*domain.Alert) (time.Time, string, error) { return time.Now(), "", nil }
+
+type nopMetricAgency struct{}
+
+func (nopMetricAgency) SetGauge(string, map[string]string, float64)         {}
+func (nopMetricAgency) AddCounter(string, map[string]string, float64)       {}
+func (nopMetricAgency) ObserveHistogram(string, map[string]string, float64) {}
+
+type nopStatusStoreAgency struct{}
+
+func (nopStatusStoreAgency) LoadStatus(context.Context, string) (string, time.Time, error) {
+	return "", time.Time{}, nil
+}
+func (nopStatusStoreAgency) StoreStatus(context.Context, string, string, time.Time) error { return nil }
+
+type nopMaintenanceAgency struct{}
+
+func (nopMaintenanceAgency) GetActiveWindow(string, string) string { return "" }
+func (nopMaintenanceAgency) GetActiveContainers() []string         { return nil }
+
+type nopEscalationAgency struct{}
+
+func (nopEscalationAgency) Escalate(string, time.Time, int, *domain.Alert) (time.Time, string, error) {
+	return time.Time{}, "", nil
+}
+func (nopEscalationAgency) CloseEscalation(string, *domain.Alert) {}
+
+func TestCheckElasticsearchCanceledAppendsErrorLevelOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := &storingElasticsearchRepo{}
+	ecu := NewElasticsearchCheckUsecase(
+		fakeElasticsearchCheckConfig{},
+		repo,
+		nopNotifierAgency{},
+		nopMetricAgency{},
+		nopStatusStoreAgency{},
+		nopMaintenanceAgency{},
+		nopEscalationAgency{},
+		cancelingElasticsearchAgency{cancel: cancel},
+	)
+
+	history, err := ecu.CheckElasticsearch(ctx)
+	if err != nil {
+		t.Fatalf("check history should be stored, err: %v", err)
+	}
+
+	count := 0
+	for _, level := range history.ProcessLevel {
+		if level == errorLevel {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("process level of canceled check should have exactly one ERROR, got: %s", history.ProcessLevel.String())
+	}
+	if !errors.Is(history.Error, context.Canceled) {
+		t.Errorf("error of canceled check should wrap context.Canceled, got: %v", history.Error)
+	}
+	if len(repo.stored) != 1 {
+		t.Errorf("history of canceled check should be stored once, got: %d", len(repo.stored))
+	}
+}
+
+// fixedClusterAgency is elasticsearchAgency returning cluster health having fixed number of active shards
+type fixedClusterAgency struct {
+	elasticsearchAgency
+	shards int
+}
+
+type fixedCluster struct{ shards int }
+
+func (c fixedCluster) ActivePrimaryShards() int     { return c.shards }
+func (c fixedCluster) ActiveShards() int            { return c.shards }
+func (c fixedCluster) UnassignedShards() int        { return 0 }
+func (c fixedCluster) ActiveShardsPercent() float64 { return 100 }
+
+func (a fixedClusterAgency) GetClusterHealth(context.Context) (cluster interface {
+	ActivePrimaryShards() int
+	ActiveShards() int
+	UnassignedShards() int
+	ActiveShardsPercent() float64
+}, err error) {
+	return fixedCluster{shards: a.shards}, nil
+}
+
+// activeMaintenanceAgency is maintenanceAgency in which every check & container is in window having id
+type activeMaintenanceAgency struct{ id string }
+
+func (a activeMaintenanceAgency) GetActiveWindow(string, string) string { return a.id }
+func (activeMaintenanceAgency) GetActiveContainers() []string           { return nil }
+
+// countingNotifierAgency is notifierAgency counting delivered alerts
+type countingNotifierAgency struct{ notified int }
+
+func (a *countingNotifierAgency) Notify(*domain.Alert) (time.Time, string, error) {
+	a.notified++
+	return time.Now(), "", nil
+}
+
+// recordingEscalationAgency is escalationAgency recording escalated & closed incidents
+type recordingEscalationAgency struct{ escalated, closed int }
+
+func (a *recordingEscalationAgency) Escalate(string, time.Time, int, *domain.Alert) (time.Time, string, error) {
+	a.escalated++
+	return time.Time{}, "", nil
+}
+func (a *recordingEscalationAgency) CloseEscalation(string, *domain.Alert) { a.closed++ }
+
+func TestCheckElasticsearchInMaintenanceWindow(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		status     elasticsearchCheckStatus
+		shards     int
+		level      string
+		wantStatus elasticsearchCheckStatus
+		cycles     int
+		closed     int
+	}{
+		{
+			name:       "unhealthy check recovers & closes escalation",
+			status:     elasticsearchStatusUnhealthy,
+			shards:     10,
+			level:      recoveredLevel,
+			wantStatus: elasticsearchStatusHealthy,
+			closed:     1,
+		}, {
+			name:       "unhealthy check counts cycles without escalating",
+			status:     elasticsearchStatusUnhealthy,
+			shards:     2000,
+			level:      unhealthyLevel,
+			wantStatus: elasticsearchStatusUnhealthy,
+			cycles:     1,
+		}, {
+			name:       "healthy check suppresses remediation",
+			status:     elasticsearchStatusHealthy,
+			shards:     2000,
+			level:      maintenanceLevel,
+			wantStatus: elasticsearchStatusHealthy,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			notifier, escalation := &countingNotifierAgency{}, &recordingEscalationAgency{}
+			ecu := NewElasticsearchCheckUsecase(
+				fakeElasticsearchCheckConfig{},
+				&storingElasticsearchRepo{},
+				notifier,
+				nopMetricAgency{},
+				nopStatusStoreAgency{},
+				activeMaintenanceAgency{id: "window-1"},
+				escalation,
+				fixedClusterAgency{shards: tc.shards},
+			).(*elasticsearchCheckUsecase)
+			ecu.status = tc.status
+
+			history, err := ecu.CheckElasticsearch(context.Background())
+			if err != nil {
+				t.Fatalf("check history should be stored, err: %v", err)
+			}
+			if history.ProcessLevel.String() != tc.level {
+				t.Errorf("process level should be %s, got: %s", tc.level, history.ProcessLevel.String())
+			}
+			if ecu.status != tc.wantStatus {
+				t.Errorf("status should be %s, got: %s", tc.wantStatus, ecu.status)
+			}
+			if ecu.unhealthyCycles != tc.cycles {
+				t.Errorf("unhealthy cycles should be %d, got: %d", tc.cycles, ecu.unhealthyCycles)
+			}
+			if escalation.closed != tc.closed || escalation.escalated != 0 {
+				t.Errorf("escalation should be closed %d & escalated 0 times, got: %d, %d", tc.closed, escalation.closed, escalation.escalated)
+			}
+			if notifier.notified != 0 {
+				t.Errorf("alert should be suppressed in maintenance window, got: %d notified", notifier.notified)
+			}
+			if history.MaintenanceWindow() != "window-1" {
+				t.Errorf("maintenance window should be set in history, got: %q", history.MaintenanceWindow())
+			}
+		})
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/inhies/go-bytesize"
 	"github.com/pkg/errors"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
 )
@@ -24,6 +26,22 @@ const (
 	swarmpitStatusUnhealthy                             // represent swarmpit check status is unhealthy
 )
 
+// String method return string represent swarmpit check status, which is used in delivery layer
+func (s swarmpitCheckStatus) String() string {
+	switch s {
+	case swarmpitStatusHealthy:
+		return "HEALTHY"
+	case swarmpitStatusRecovering:
+		return "RECOVERING"
+	case swarmpitStatusUnhealthy:
+		return "UNHEALTHY"
+	}
+	return "UNKNOWN"
+}
+
+// swarmpitCheckKey is key identifying swarmpit check in status store agency & maintenance window
+const swarmpitCheckKey = "srvcheck.swarmpit"
+
 // swarmpitCheckUsecase implement SwarmpitCheckUsecase interface in domain and used in delivery layer
 type swarmpitCheckUsecase struct {
 	// myCfg is used for getting swarmpit check usecase config
@@ -32,8 +50,20 @@ type swarmpitCheckUsecase struct {
 	// historyRepo is used for store swarmpit check history and injected from outside
 	historyRepo domain.SwarmpitCheckHistoryRepository
 
-	// slackChat is used for agent slack API about chatting
-	slackChatAgency slackChatAgency
+	// notifierAgency is used for agent notifiers delivering alert
+	notifierAgency notifierAgency
+
+	// metricAgency is used as agency about metric collector to expose numbers computed in check process
+	metricAgency metricAgency
+
+	// statusStoreAgency is used as agency about status store to keep status of state machine across restart
+	statusStoreAgency statusStoreAgency
+
+	// maintenanceAgency is used for checking if check or container is in maintenance window
+	maintenanceAgency maintenanceAgency
+
+	// escalationAgency is used for escalating incident of swarmpit check staying unhealthy long
+	escalationAgency escalationAgency
 
 	// dockerAgency is used as agency about docker engine API
 	dockerAgency dockerAgency
@@ -41,8 +71,20 @@ type swarmpitCheckUsecase struct {
 	// status represent current process status of swarmpit health check
 	status swarmpitCheckStatus
 
+	// statusChangedAt represent time when status field value was changed last
+	statusChangedAt time.Time
+
+	// unhealthyCycles represent how many check cycles have run since status was changed to unhealthy
+	unhealthyCycles int
+
+	// lastHistory represent swarmpit check history produced in last check process
+	lastHistory *domain.SwarmpitCheckHistory
+
 	// mutex help to prevent race condition when set status field value
 	mutex sync.Mutex
+
+	// runLocker help to prevent swarmpit check process from running at the same time
+	runLocker checkRunLocker
 }
 
 // swarmpitCheckUsecaseConfig is the config getter interface for swarmpit check usecase
@@ -50,6 +92,9 @@ type swarmpitCheckUsecaseConfig interface {
 	// get common config method from embedding serviceCheckUsecaseComponentConfig
 	serviceCheckUsecaseComponentConfig
 
+	// SwarmpitCheckTimeOut method returns time.Duration represent deadline of one swarmpit check process
+	SwarmpitCheckTimeOut() time.Duration
+
 	// SwarmpitAppServiceName method returns string represent swarmpit app service name
 	SwarmpitAppServiceName() string
 
@@ -61,34 +106,98 @@ type swarmpitCheckUsecaseConfig interface {
 func NewSwarmpitCheckUsecase(
 	cfg swarmpitCheckUsecaseConfig,
 	shr domain.SwarmpitCheckHistoryRepository,
-	sca slackChatAgency,
+	na notifierAgency,
+	ma metricAgency,
+	ssa statusStoreAgency,
+	mta maintenanceAgency,
+	eca escalationAgency,
 	da dockerAgency,
 ) domain.SwarmpitCheckUseCase {
-	return &swarmpitCheckUsecase{
+	usecase := &swarmpitCheckUsecase{
 		// initialize field with parameter received from caller
-		myCfg:           cfg,
-		historyRepo:     shr,
-		slackChatAgency: sca,
-		dockerAgency:    da,
+		myCfg:             cfg,
+		historyRepo:       shr,
+		notifierAgency:    na,
+		metricAgency:      ma,
+		statusStoreAgency: ssa,
+		maintenanceAgency: mta,
+		escalationAgency:  eca,
+		dockerAgency:      da,
 
 		// initialize field with default value
-		status: swarmpitStatusHealthy,
-		mutex:  sync.Mutex{},
+		status:    swarmpitStatusHealthy,
+		mutex:     sync.Mutex{},
+		runLocker: newCheckRunLocker(),
 	}
+
+	usecase.loadStatus()
+	return usecase
 }
 
 // CheckSwarmpit check swarmpit health with checkSwarmpit method & store check history in repository
 // Implement CheckSwarmpit method of SwarmpitCheckUseCase interface
-func (scu *swarmpitCheckUsecase) CheckSwarmpit(ctx context.Context) (err error) {
-	history := scu.checkSwarmpit(ctx)
+func (scu *swarmpitCheckUsecase) CheckSwarmpit(ctx context.Context) (history *domain.SwarmpitCheckHistory, err error) {
+	start := time.Now()
+	if scu.runLocker.lock(ctx, scu.myCfg.CheckOverlapPolicy()) {
+		toCtx, cancel := context.WithTimeout(ctx, scu.myCfg.SwarmpitCheckTimeOut())
+		history = scu.checkSwarmpit(toCtx)
+		if toCtx.Err() != nil {
+			if history.ProcessLevel.Last() != errorLevel {
+				history.ProcessLevel.Append(errorLevel)
+			}
+			switch {
+			case history.Error != nil:
+			case toCtx.Err() == context.DeadlineExceeded:
+				history.SetError(errors.Wrapf(toCtx.Err(), "swarmpit check process is timed out (%s)", scu.myCfg.SwarmpitCheckTimeOut()))
+			default:
+				history.SetError(errors.Wrap(toCtx.Err(), "swarmpit check process is canceled"))
+			}
+		}
+		cancel()
+		scu.runLocker.unlock()
+		scu.setLastHistory(history)
+	} else {
+		history = scu.skippedHistory()
+	}
+	scu.recordMetrics(history, time.Since(start))
 
 	if b, err := scu.historyRepo.Store(history); err != nil {
-		return errors.Wrapf(err, "failed to store swarmpit check history, response: %s", string(b))
+		return history, errors.Wrapf(err, "failed to store swarmpit check history, response: %s", string(b))
 	}
 
+	return history, nil
+}
+
+// skippedHistory return swarmpit check history represent that check process is skipped as same process is already running
+func (scu *swarmpitCheckUsecase) skippedHistory() (history *domain.SwarmpitCheckHistory) {
+	history = new(domain.SwarmpitCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = uuid.New().String()
+	history.ProcessLevel.Set(skippedLevel)
+	history.Message = "swarmpit check is skipped as previous swarmpit check process is still running"
+	return
+}
+
+// newAlert return alert about swarmpit check history with thresholds configured in swarmpit check config
+func (scu *swarmpitCheckUsecase) newAlert(history *domain.SwarmpitCheckHistory, level domain.AlertLevel, text string) (alert *domain.Alert) {
+	alert = history.NewAlert(level, text)
+	alert.Thresholds["swarmpit_app_max_memory_usage"] = scu.myCfg.SwarmpitAppMaxMemoryUsage()
 	return
 }
 
+// notify deliver alert about swarmpit check history to notifier agency, except when swarmpit check is in maintenance window
+// if alert is suppressed by maintenance window, id of that window is set in history
+// reason is stable code of why alert is produced (Ex, container_remove_failed), used for deduplicating it in notifier
+func (scu *swarmpitCheckUsecase) notify(history *domain.SwarmpitCheckHistory, level domain.AlertLevel, reason, text string) (t time.Time, _text string, err error) {
+	if id := scu.maintenanceAgency.GetActiveWindow(swarmpitCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+	alert := scu.newAlert(history, level, text)
+	alert.Reason = reason
+	return scu.notifierAgency.Notify(alert)
+}
+
 // method processed with below logic about swarmpit health check according to current check status
 // 0 : 정상적으로 인지된 상태 (상태 확인 수행) (SwarmpitApp 컨테이너 메모리 사용량 기준)
 // 0 -> 1 : SwarmpitApp 재시작 실행 (SwarmpitApp 재시동 알림 발행)
@@ -103,12 +212,12 @@ func (scu *swarmpitCheckUsecase) checkSwarmpit(ctx context.Context) (history *do
 	history.FillPrivateComponent()
 	history.UUID = _uuid
 
-	ctn, err := scu.dockerAgency.GetContainerWithServiceName(scu.myCfg.SwarmpitAppServiceName())
+	ctn, err := scu.dockerAgency.GetContainerWithServiceName(ctx, scu.myCfg.SwarmpitAppServiceName())
 	if err != nil {
 		history.ProcessLevel.Set(errorLevel)
 		history.SetError(errors.Wrap(err, "failed to get swarmpit app docker container"))
 		msg := "!swarmpit check error occurred! unable to get swarmpit app container"
-		history.SetAlarmResult(scu.slackChatAgency.SendMessage("x", msg, _uuid))
+		history.SetAlarmResult(scu.notify(history, domain.AlertLevelError, "app_container_unavailable", msg))
 		return
 	}
 	history.SwarmpitAppMemoryUsage = ctn.MemoryUsage()
@@ -127,33 +236,44 @@ func (scu *swarmpitCheckUsecase) checkSwarmpit(ctx context.Context) (history *do
 			history.ProcessLevel.Set(recoveredLevel)
 			history.Message = "swarmpit check is recovered to be healthy"
 			msg := fmt.Sprintf("!swarmpit check recovered to health! memory usage - %s", memoryUsage.V)
-			_, _, _ = scu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = scu.notify(history, domain.AlertLevelRecovered, "recovered", msg)
+			scu.escalationAgency.CloseEscalation(swarmpitCheckKey, scu.newAlert(history, domain.AlertLevelRecovered, msg))
 		} else {
 			history.ProcessLevel.Set(unhealthyLevel)
 			history.Message = "swarmpit check is unhealthy now"
+			scu.escalate(history)
 		}
 		return
 	}
 
+	// suppress remediation & alarm if swarmpit check is in maintenance window, history is still stored with measured value
+	if id := scu.maintenanceAgency.GetActiveWindow(swarmpitCheckKey, scu.myCfg.SwarmpitAppServiceName()); id != "" {
+		history.SetMaintenanceWindow(id)
+		history.ProcessLevel.Set(maintenanceLevel)
+		history.Message = "swarmpit check is in maintenance window, so remediation & alarm are suppressed"
+		return
+	}
+
 	if memoryUsage.isMoreThan(scu.myCfg.SwarmpitAppMaxMemoryUsage()) {
 		scu.setStatus(swarmpitStatusRecovering)
 		history.ProcessLevel.Set(weakDetectedLevel)
 		msg := "!swarmpit check weak detected! start to restart swarmpit app"
-		history.SetAlarmResult(scu.slackChatAgency.SendMessage("pill", msg, _uuid))
+		history.SetAlarmResult(scu.notify(history, domain.AlertLevelWeakDetected, "app_restart_started", msg))
 
-		if err := scu.dockerAgency.RemoveContainer(ctn.ID(), types.ContainerRemoveOptions{Force: true}); err != nil {
+		if err := scu.dockerAgency.RemoveContainer(ctx, ctn.ID(), types.ContainerRemoveOptions{Force: true}); err != nil {
 			scu.setStatus(swarmpitStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!swarmpit check error occurred! failed to remove swarmpit app, please check for yourself"
-			_, _, _ = scu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = scu.notify(history, domain.AlertLevelRecoveryError, "app_restart_failed", msg)
 			history.SetError(errors.Wrap(err, "failed to remove swarmpit app"))
 			return
 		} else {
 			scu.setStatus(swarmpitStatusHealthy)
+			scu.metricAgency.AddCounter(checkRemediationsMetric, remediationLabels("swarmpit", "container_restarted"), 1)
 			history.IfSwarmpitAppRestarted = true
 			history.Message = "restart swarmpit app as swarmpit app memory usage is more than the maximum"
 			msg := "!swarmpit check is recovered! succeed to restart swarmpit app"
-			_, _, _ = scu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = scu.notify(history, domain.AlertLevelRecovered, "recovered_by_remediation", msg)
 		}
 	} else {
 		history.ProcessLevel.Set(healthyLevel)
@@ -163,9 +283,167 @@ func (scu *swarmpitCheckUsecase) checkSwarmpit(ctx context.Context) (history *do
 	return
 }
 
+// ResetStatus reset status of swarmpit check to healthy after administrator fixed problem & store history about that reset
+// it waits until running swarmpit check process is finished, so that reset status is not overwritten by that process
+// Implement ResetStatus method of domain.SwarmpitCheckUseCase interface
+func (scu *swarmpitCheckUsecase) ResetStatus(ctx context.Context, by, reason string) (history *domain.SwarmpitCheckHistory, err error) {
+	if !scu.runLocker.lock(ctx, overlapPolicyQueue) {
+		err = errors.Wrap(ctx.Err(), "context done while waiting running swarmpit check process")
+		return
+	}
+	defer scu.runLocker.unlock()
+
+	_uuid := uuid.New().String()
+	history = new(domain.SwarmpitCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = _uuid
+	history.ProcessLevel.Set(resetLevel)
+	history.SetResetResult(by, reason)
+	history.Message = fmt.Sprintf("swarmpit check status is reset to healthy from %s", scu.GetStatus())
+
+	scu.setStatus(swarmpitStatusHealthy)
+	msg := fmt.Sprintf("!swarmpit check status reset! reset to healthy by %s (reason - %s)", by, reason)
+	alert := scu.newAlert(history, domain.AlertLevelReset, msg)
+	history.SetAlarmResult(scu.notifierAgency.Notify(alert))
+	scu.escalationAgency.CloseEscalation(swarmpitCheckKey, alert)
+
+	if b, err := scu.historyRepo.Store(history); err != nil {
+		return history, errors.Wrapf(err, "failed to store swarmpit check history, response: %s", string(b))
+	}
+
+	return history, nil
+}
+
+// escalate count cycle of swarmpit check staying unhealthy & escalate incident with escalation agency if rule is satisfied
+func (scu *swarmpitCheckUsecase) escalate(history *domain.SwarmpitCheckHistory) {
+	scu.mutex.Lock()
+	scu.unhealthyCycles++
+	cycles, since := scu.unhealthyCycles, scu.statusChangedAt
+	scu.mutex.Unlock()
+
+	// incident in maintenance window is not escalated, cycles are still counted to escalate after window ends
+	if id := scu.maintenanceAgency.GetActiveWindow(swarmpitCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+
+	msg := fmt.Sprintf("!swarmpit check escalated! still unhealthy after %d cycles, please check for yourself", cycles)
+	history.SetAlarmResult(scu.escalationAgency.Escalate(swarmpitCheckKey, since, cycles, scu.newAlert(history, domain.AlertLevelEscalated, msg)))
+}
+
 // setStatus set status field value using mutex Lock & Unlock
+// status is stored in status store only if changed, after unlocking mutex so that slow store doesn't block GetStatus
 func (scu *swarmpitCheckUsecase) setStatus(status swarmpitCheckStatus) {
 	scu.mutex.Lock()
-	defer scu.mutex.Unlock()
+	changed := scu.status != status
+	if changed {
+		scu.statusChangedAt = domain.Now()
+		scu.unhealthyCycles = 0
+	}
 	scu.status = status
+	transitedAt := scu.statusChangedAt
+	scu.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+	if err := scu.statusStoreAgency.StoreStatus(ctx, swarmpitCheckKey, status.String(), transitedAt); err != nil {
+		log.Printf("failed to store swarmpit check status in status store, err: %v", err)
+	}
+}
+
+// loadStatus load status of swarmpit check saved in status store, so that status survives restart
+// recovering status is loaded as unhealthy, because recovering process was stopped by restart & should be checked
+func (scu *swarmpitCheckUsecase) loadStatus() {
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+
+	saved, transitedAt, err := scu.statusStoreAgency.LoadStatus(ctx, swarmpitCheckKey)
+	if err != nil {
+		log.Printf("failed to load swarmpit check status from status store, err: %v", err)
+		return
+	}
+
+	for status := swarmpitStatusHealthy; status <= swarmpitStatusUnhealthy; status++ {
+		if status.String() != saved {
+			continue
+		}
+		if status == swarmpitStatusRecovering {
+			status = swarmpitStatusUnhealthy
+		}
+		scu.status, scu.statusChangedAt = status, transitedAt
+		return
+	}
+}
+
+// GetStatus return current status of swarmpit check process as string
+// Implement GetStatus method of domain.SwarmpitCheckUseCase interface
+func (scu *swarmpitCheckUsecase) GetStatus() string {
+	scu.mutex.Lock()
+	defer scu.mutex.Unlock()
+	return scu.status.String()
+}
+
+// GetLastHistory return swarmpit check history produced in last check process
+// Implement GetLastHistory method of domain.SwarmpitCheckUseCase interface
+func (scu *swarmpitCheckUsecase) GetLastHistory() *domain.SwarmpitCheckHistory {
+	scu.mutex.Lock()
+	defer scu.mutex.Unlock()
+	return scu.lastHistory
+}
+
+// setLastHistory set lastHistory field value using mutex Lock & Unlock
+func (scu *swarmpitCheckUsecase) setLastHistory(history *domain.SwarmpitCheckHistory) {
+	scu.mutex.Lock()
+	defer scu.mutex.Unlock()
+	scu.lastHistory = history
+}
+
+// GetHistory return swarmpit check history having uuid in history repository (nil if not exist)
+// Implement GetHistory method of domain.SwarmpitCheckUseCase interface
+func (scu *swarmpitCheckUsecase) GetHistory(ctx context.Context, uuid string) (history *domain.SwarmpitCheckHistory, err error) {
+	if history, err = scu.historyRepo.GetByUUID(ctx, uuid); err != nil {
+		err = errors.Wrapf(err, "failed to get swarmpit check history, uuid: %s", uuid)
+	}
+	return
+}
+
+// FindHistories return swarmpit check histories stored between start & end, filtered by process level if level is not empty
+// Implement FindHistories method of domain.SwarmpitCheckUseCase interface
+func (scu *swarmpitCheckUsecase) FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*domain.SwarmpitCheckHistory, err error) {
+	if level == "" {
+		histories, err = scu.historyRepo.FindByTimeRange(ctx, start, end)
+	} else {
+		histories, err = scu.historyRepo.FindByProcessLevel(ctx, level, start, end)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to find swarmpit check histories")
+	}
+	return
+}
+
+// FindLatestHistories return latest n swarmpit check histories in history repository in order of newest first
+// Implement FindLatestHistories method of domain.SwarmpitCheckUseCase interface
+func (scu *swarmpitCheckUsecase) FindLatestHistories(ctx context.Context, n int) (histories []*domain.SwarmpitCheckHistory, err error) {
+	if histories, err = scu.historyRepo.FindLatest(ctx, n); err != nil {
+		err = errors.Wrap(err, "failed to find latest swarmpit check histories")
+	}
+	return
+}
+
+// recordMetrics record metrics about swarmpit check process & swarmpit app memory usage in history using metric agency
+func (scu *swarmpitCheckUsecase) recordMetrics(history *domain.SwarmpitCheckHistory, elapsed time.Duration) {
+	var statuses []string
+	for s := swarmpitStatusHealthy; s <= swarmpitStatusUnhealthy; s++ {
+		statuses = append(statuses, s.String())
+	}
+	recordCommonMetrics(scu.metricAgency, "swarmpit", history.ProcessLevel.String(), elapsed, scu.GetStatus(), statuses)
+
+	if level := history.ProcessLevel.String(); level == errorLevel || level == skippedLevel {
+		return
+	}
+	scu.metricAgency.SetGauge("srvcheck_swarmpit_app_memory_usage_bytes", nil, float64(history.SwarmpitAppMemoryUsage))
 }
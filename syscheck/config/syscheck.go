@@ -28,6 +28,40 @@ type syscheckConfig struct {
 	// indexReplicaNum represent replica number of elasticsearch index to replace index when node become unable
 	indexReplicaNum *int
 
+	// indexRolloverPeriod represent max age of write index, new write index is created by rollover after that
+	indexRolloverPeriod *time.Duration
+
+	// indexRetentionPeriod represent period to keep syscheck history, index having only older history is deleted
+	indexRetentionPeriod *time.Duration
+
+	// fields about history file information (implement fileRepositoryComponentConfig)
+	// repositoryType represent type of repository storing syscheck history (elasticsearch or file)
+	repositoryType *string
+
+	// historyFileDir represent path of directory to store syscheck history file in if repository type is file
+	historyFileDir *string
+
+	// historyFileMaxSize represent max size of history file, file is rotated when exceeding it
+	historyFileMaxSize *bytesize.ByteSize
+
+	// historyFileMaxBackups represent max number of rotated history file to keep
+	historyFileMaxBackups *int
+
+	// ---
+
+	// fields using in all check usecase in common (implement systemCheckUsecaseComponentConfig)
+	// checkOverlapPolicy represent policy about check requested while same check is running (skip or queue)
+	checkOverlapPolicy *string
+
+	// cpuCheckTimeOut represent deadline of one cpu check process, applied to context delivered to agency
+	cpuCheckTimeOut *time.Duration
+
+	// diskCheckTimeOut represent deadline of one disk check process, applied to context delivered to agency
+	diskCheckTimeOut *time.Duration
+
+	// memoryCheckTimeOut represent deadline of one memory check process, applied to context delivered to agency
+	memoryCheckTimeOut *time.Duration
+
 	// ---
 
 	// fields using in disk health checking (implement diskCheckUsecaseConfig)
@@ -77,6 +111,20 @@ const (
 	defaultIndexShardNum   = 2                  // default const int for indexShardNum
 	defaultIndexReplicaNum = 0                  // default const int for indexReplicaNum
 
+	defaultIndexRolloverPeriod  = time.Hour * 24      // default const Duration for indexRolloverPeriod
+	defaultIndexRetentionPeriod = time.Hour * 24 * 30 // default const Duration for indexRetentionPeriod
+
+	defaultRepositoryType        = "elasticsearch"                   // default const string for repositoryType
+	defaultHistoryFileDir        = "/usr/share/health-check/history" // default const string for historyFileDir
+	defaultHistoryFileMaxSize    = bytesize.MB * 100                 // default const byte size for historyFileMaxSize
+	defaultHistoryFileMaxBackups = 5                                 // default const int for historyFileMaxBackups
+
+	defaultCheckOverlapPolicy = "skip" // default const string for checkOverlapPolicy
+
+	defaultCPUCheckTimeOut    = time.Minute * 1 // default const Duration for cpuCheckTimeOut
+	defaultDiskCheckTimeOut   = time.Minute * 3 // default const Duration for diskCheckTimeOut
+	defaultMemoryCheckTimeOut = time.Minute * 1 // default const Duration for memoryCheckTimeOut
+
 	defaultDiskMinCapacity = bytesize.GB * 2 // default const byte size for diskMinCapacity
 
 	defaultCPUWarningUsage         = float64(1.0) // default const float64 for cpuWarningUsage
@@ -92,6 +140,73 @@ const (
 	defaultMemoryCheckDeliveryPingCycle = time.Minute * 5 // default const Duration for diskCheckDeliveryPingCycle
 )
 
+// implement CheckOverlapPolicy method of systemCheckUsecaseComponentConfig interface
+func (sc *syscheckConfig) CheckOverlapPolicy() string {
+	var key = "syscheck.usecase.overlapPolicy"
+	if sc.checkOverlapPolicy != nil {
+		return *sc.checkOverlapPolicy
+	}
+
+	switch viper.GetString(key) {
+	case "skip", "queue":
+	default:
+		viper.Set(key, defaultCheckOverlapPolicy)
+	}
+	sc.checkOverlapPolicy = _string(viper.GetString(key))
+	return *sc.checkOverlapPolicy
+}
+
+// implement CPUCheckTimeOut method of cpuCheckUsecaseConfig interface
+func (sc *syscheckConfig) CPUCheckTimeOut() time.Duration {
+	var key = "syscheck.cpucheck.checkTimeOut"
+	if sc.cpuCheckTimeOut != nil {
+		return *sc.cpuCheckTimeOut
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultCPUCheckTimeOut.String())
+		d = defaultCPUCheckTimeOut
+	}
+
+	sc.cpuCheckTimeOut = &d
+	return *sc.cpuCheckTimeOut
+}
+
+// implement DiskCheckTimeOut method of diskCheckUsecaseConfig interface
+func (sc *syscheckConfig) DiskCheckTimeOut() time.Duration {
+	var key = "syscheck.diskcheck.checkTimeOut"
+	if sc.diskCheckTimeOut != nil {
+		return *sc.diskCheckTimeOut
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultDiskCheckTimeOut.String())
+		d = defaultDiskCheckTimeOut
+	}
+
+	sc.diskCheckTimeOut = &d
+	return *sc.diskCheckTimeOut
+}
+
+// implement MemoryCheckTimeOut method of memoryCheckUsecaseConfig interface
+func (sc *syscheckConfig) MemoryCheckTimeOut() time.Duration {
+	var key = "syscheck.memorycheck.checkTimeOut"
+	if sc.memoryCheckTimeOut != nil {
+		return *sc.memoryCheckTimeOut
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultMemoryCheckTimeOut.String())
+		d = defaultMemoryCheckTimeOut
+	}
+
+	sc.memoryCheckTimeOut = &d
+	return *sc.memoryCheckTimeOut
+}
+
 // implement IndexName method of esRepositoryComponentConfig interface
 func (sc *syscheckConfig) IndexName() string {
 	var key = "syscheck.repository.elasticsearch.index.name"
@@ -128,6 +243,97 @@ func (sc *syscheckConfig) IndexReplicaNum() int {
 	return *sc.indexReplicaNum
 }
 
+// implement IndexRolloverPeriod method of esIndexManagerConfig interface
+func (sc *syscheckConfig) IndexRolloverPeriod() time.Duration {
+	var key = "syscheck.repository.elasticsearch.index.rolloverPeriod"
+	if sc.indexRolloverPeriod != nil {
+		return *sc.indexRolloverPeriod
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || d <= 0 {
+		viper.Set(key, defaultIndexRolloverPeriod.String())
+		d = defaultIndexRolloverPeriod
+	}
+
+	sc.indexRolloverPeriod = &d
+	return *sc.indexRolloverPeriod
+}
+
+// implement IndexRetentionPeriod method of esIndexManagerConfig interface
+func (sc *syscheckConfig) IndexRetentionPeriod() time.Duration {
+	var key = "syscheck.repository.elasticsearch.index.retentionPeriod"
+	if sc.indexRetentionPeriod != nil {
+		return *sc.indexRetentionPeriod
+	}
+
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		viper.Set(key, defaultIndexRetentionPeriod.String())
+		d = defaultIndexRetentionPeriod
+	}
+
+	sc.indexRetentionPeriod = &d
+	return *sc.indexRetentionPeriod
+}
+
+// not implement any interface, just using in main function for selecting repository (elasticsearch or file)
+func (sc *syscheckConfig) RepositoryType() string {
+	var key = "syscheck.repository.type"
+	if sc.repositoryType != nil {
+		return *sc.repositoryType
+	}
+
+	switch viper.GetString(key) {
+	case "elasticsearch", "file":
+	default:
+		viper.Set(key, defaultRepositoryType)
+	}
+	sc.repositoryType = _string(viper.GetString(key))
+	return *sc.repositoryType
+}
+
+// implement HistoryFileDir method of fileRepositoryComponentConfig interface
+func (sc *syscheckConfig) HistoryFileDir() string {
+	var key = "syscheck.repository.file.dir"
+	if sc.historyFileDir == nil {
+		if _, ok := viper.Get(key).(string); !ok {
+			viper.Set(key, defaultHistoryFileDir)
+		}
+		sc.historyFileDir = _string(viper.GetString(key))
+	}
+	return *sc.historyFileDir
+}
+
+// implement HistoryFileMaxSize method of fileRepositoryComponentConfig interface
+func (sc *syscheckConfig) HistoryFileMaxSize() int64 {
+	var key = "syscheck.repository.file.maxSize"
+	if sc.historyFileMaxSize != nil {
+		return int64(*sc.historyFileMaxSize)
+	}
+
+	size, err := bytesize.Parse(viper.GetString(key))
+	if err != nil || size == 0 {
+		viper.Set(key, defaultHistoryFileMaxSize.String())
+		size = defaultHistoryFileMaxSize
+	}
+
+	sc.historyFileMaxSize = &size
+	return int64(*sc.historyFileMaxSize)
+}
+
+// implement HistoryFileMaxBackups method of fileRepositoryComponentConfig interface
+func (sc *syscheckConfig) HistoryFileMaxBackups() int {
+	var key = "syscheck.repository.file.maxBackups"
+	if sc.historyFileMaxBackups == nil {
+		if _, ok := viper.Get(key).(int); !ok {
+			viper.Set(key, defaultHistoryFileMaxBackups)
+		}
+		sc.historyFileMaxBackups = _int(viper.GetInt(key))
+	}
+	return *sc.historyFileMaxBackups
+}
+
 // implement DiskMinCapacity method of diskCheckUsecaseConfig interface
 func (sc *syscheckConfig) DiskMinCapacity() bytesize.ByteSize {
 	var key = "syscheck.diskcheck.minCapacity"
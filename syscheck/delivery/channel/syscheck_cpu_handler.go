@@ -10,6 +10,7 @@ package channel
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
@@ -19,24 +20,40 @@ import (
 type cpuCheckHandler struct {
 	// CUsecase is usecase layer interface which is injected from package outside (maybe, in main)
 	CUsecase domain.CPUCheckUseCase
+
+	// wg is used for waiting in-flight check process before process exit, injected from outside (maybe, in main)
+	wg *sync.WaitGroup
 }
 
 // NewDiskCheckHandler define diskCheckHandler ptr instance & register handling channel msg to usecase
-func NewCPUCheckHandler(c <-chan time.Time, cu domain.CPUCheckUseCase) {
+func NewCPUCheckHandler(ctx context.Context, c <-chan time.Time, wg *sync.WaitGroup, cu domain.CPUCheckUseCase) {
 	handler := &cpuCheckHandler{
 		CUsecase: cu,
+		wg:       wg,
 	}
 
-	go handler.startListening(c)
+	// count listening goroutine in wait group, so that wg.Add is never called after counter reaches zero
+	wg.Add(1)
+	go handler.startListening(ctx, c)
 	log.Println("START TO LISTEN CHANNEL MSG ABOUT SYSTEM CPU CHECK")
 }
 
 // startListening method start listening msg from golang channel & stream msg to another method
-func (ch *cpuCheckHandler) startListening(c <-chan time.Time) {
+// listening is stopped when ctx is done, but check process already started is not canceled
+func (ch *cpuCheckHandler) startListening(ctx context.Context, c <-chan time.Time) {
+	defer ch.wg.Done()
+
 	for {
 		select {
 		case t := <-c:
-			go ch.checkCPU(t)
+			ch.wg.Add(1)
+			go func() {
+				defer ch.wg.Done()
+				ch.checkCPU(t)
+			}()
+		case <-ctx.Done():
+			log.Println("STOP TO LISTEN CHANNEL MSG ABOUT SYSTEM CPU CHECK")
+			return
 		}
 	}
 }
@@ -46,7 +63,7 @@ func (ch *cpuCheckHandler) checkCPU(t time.Time) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, "time", t)
 
-	if err := ch.CUsecase.CheckCPU(ctx); err != nil {
+	if _, err := ch.CUsecase.CheckCPU(ctx); err != nil {
 		log.Printf("error occurs in CheckCPU, err: %v", err)
 	}
 }
@@ -6,6 +6,7 @@ package channel
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
@@ -15,24 +16,40 @@ import (
 type diskCheckHandler struct {
 	// DUsecase is usecase layer interface which is injected from package outside (maybe, in main)
 	DUsecase domain.DiskCheckUseCase
+
+	// wg is used for waiting in-flight check process before process exit, injected from outside (maybe, in main)
+	wg *sync.WaitGroup
 }
 
 // NewDiskCheckHandler define diskCheckHandler ptr instance & register handling channel msg to usecase
-func NewDiskCheckHandler(c <-chan time.Time, du domain.DiskCheckUseCase) {
+func NewDiskCheckHandler(ctx context.Context, c <-chan time.Time, wg *sync.WaitGroup, du domain.DiskCheckUseCase) {
 	handler := &diskCheckHandler{
 		DUsecase: du,
+		wg:       wg,
 	}
 
-	go handler.startListening(c)
+	// count listening goroutine in wait group, so that wg.Add is never called after counter reaches zero
+	wg.Add(1)
+	go handler.startListening(ctx, c)
 	log.Println("START TO LISTEN CHANNEL MSG ABOUT SYSTEM DISK CHECK")
 }
 
 // startListening method start listening msg from golang channel & stream msg to another method
-func (dh *diskCheckHandler) startListening(c <-chan time.Time) {
+// listening is stopped when ctx is done, but check process already started is not canceled
+func (dh *diskCheckHandler) startListening(ctx context.Context, c <-chan time.Time) {
+	defer dh.wg.Done()
+
 	for {
 		select {
 		case t := <-c:
-			go dh.checkDisk(t)
+			dh.wg.Add(1)
+			go func() {
+				defer dh.wg.Done()
+				dh.checkDisk(t)
+			}()
+		case <-ctx.Done():
+			log.Println("STOP TO LISTEN CHANNEL MSG ABOUT SYSTEM DISK CHECK")
+			return
 		}
 	}
 }
@@ -42,7 +59,7 @@ func (dh *diskCheckHandler) checkDisk(t time.Time) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, "time", t)
 
-	if err := dh.DUsecase.CheckDisk(ctx); err != nil {
+	if _, err := dh.DUsecase.CheckDisk(ctx); err != nil {
 		log.Printf("error occurs in CheckDisk, err: %v", err)
 	}
 }
@@ -6,6 +6,7 @@ package channel
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
@@ -15,24 +16,36 @@ import (
 type memoryCheckHandler struct {
 	// CUsecase is usecase layer interface which is injected from package outside (maybe, in main)
 	MUsecase domain.MemoryCheckUseCase
+
+	// wg is used for waiting in-flight check process before process exit, injected from outside (maybe, in main)
+	wg *sync.WaitGroup
 }
 
 // NewDiskCheckHandler define memoryCheckHandler ptr instance & register handling channel msg to usecase
-func NewMemoryCheckHandler(c <-chan time.Time, mu domain.MemoryCheckUseCase) {
+func NewMemoryCheckHandler(ctx context.Context, c <-chan time.Time, wg *sync.WaitGroup, mu domain.MemoryCheckUseCase) {
 	handler := &memoryCheckHandler{
 		MUsecase: mu,
+		wg:       wg,
 	}
 
-	go handler.startListening(c)
+	// count listening goroutine in wait group, so that wg.Add is never called after counter reaches zero
+	wg.Add(1)
+	go handler.startListening(ctx, c)
 	log.Println("START TO LISTEN CHANNEL MSG ABOUT SYSTEM MEMORY CHECK")
 }
 
 // startListening method start listening msg from golang channel & stream msg to another method
-func (mh *memoryCheckHandler) startListening(c <-chan time.Time) {
+// listening is stopped when ctx is done, but check process already started is not canceled
+func (mh *memoryCheckHandler) startListening(ctx context.Context, c <-chan time.Time) {
+	defer mh.wg.Done()
+
 	for {
 		select {
 		case t := <-c:
 			mh.checkMemory(t)
+		case <-ctx.Done():
+			log.Println("STOP TO LISTEN CHANNEL MSG ABOUT SYSTEM MEMORY CHECK")
+			return
 		}
 	}
 }
@@ -42,7 +55,7 @@ func (mh *memoryCheckHandler) checkMemory(t time.Time) {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, "time", t)
 
-	if err := mh.MUsecase.CheckMemory(ctx); err != nil {
+	if _, err := mh.MUsecase.CheckMemory(ctx); err != nil {
 		log.Printf("error occurs in CheckMemory, err: %v", err)
 	}
 }
@@ -0,0 +1,141 @@
+// in syscheck_admin_handler.go file, define delivery from http request to administrator operation of syscheck usecase
+// every path is prefixed with /admin/syscheck & requires admin token, Ex) /admin/syscheck/status, /admin/syscheck/disk/reset
+
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// adminHandler is delivered data handler about administrator operation to system check using usecase layer
+type adminHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token
+	token string
+
+	// DUsecase is usecase layer interface about disk check which is injected from package outside (maybe, in main)
+	DUsecase domain.DiskCheckUseCase
+
+	// CUsecase is usecase layer interface about cpu check which is injected from package outside (maybe, in main)
+	CUsecase domain.CPUCheckUseCase
+
+	// MUsecase is usecase layer interface about memory check which is injected from package outside (maybe, in main)
+	MUsecase domain.MemoryCheckUseCase
+}
+
+// NewAdminHandler define adminHandler ptr instance & register handling http request to usecase with admin token
+func NewAdminHandler(
+	r router,
+	token string,
+	du domain.DiskCheckUseCase,
+	cu domain.CPUCheckUseCase,
+	mu domain.MemoryCheckUseCase,
+) {
+	handler := &adminHandler{
+		token:    token,
+		DUsecase: du,
+		CUsecase: cu,
+		MUsecase: mu,
+	}
+
+	r.HandleFunc("/admin/syscheck/status", handler.getStatuses)
+	r.HandleFunc("/admin/syscheck/disk/reset", handler.resetDisk)
+	r.HandleFunc("/admin/syscheck/cpu/reset", handler.resetCPU)
+	r.HandleFunc("/admin/syscheck/memory/reset", handler.resetMemory)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SYSTEM CHECK ADMINISTRATION")
+}
+
+// getStatuses method respond current status & last history of every system check at once
+func (ah *adminHandler) getStatuses(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := map[string]statusResponse{}
+	diskResp := statusResponse{Status: ah.DUsecase.GetStatus()}
+	if history := ah.DUsecase.GetLastHistory(); history != nil {
+		diskResp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	resp["disk"] = diskResp
+
+	cpuResp := statusResponse{Status: ah.CUsecase.GetStatus()}
+	if history := ah.CUsecase.GetLastHistory(); history != nil {
+		cpuResp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	resp["cpu"] = cpuResp
+
+	memoryResp := statusResponse{Status: ah.MUsecase.GetStatus()}
+	if history := ah.MUsecase.GetLastHistory(); history != nil {
+		memoryResp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	resp["memory"] = memoryResp
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// resetDisk method reset status of disk check to healthy & respond history produced in that reset
+func (ah *adminHandler) resetDisk(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req, ok := decodeResetRequest(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := ah.DUsecase.ResetStatus(r.Context(), req.By, req.Reason)
+	if history == nil {
+		writeResetResponse(w, "", "", "", err)
+		return
+	}
+	writeResetResponse(w, history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// resetCPU method reset status of cpu check to healthy & respond history produced in that reset
+func (ah *adminHandler) resetCPU(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req, ok := decodeResetRequest(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := ah.CUsecase.ResetStatus(r.Context(), req.By, req.Reason)
+	if history == nil {
+		writeResetResponse(w, "", "", "", err)
+		return
+	}
+	writeResetResponse(w, history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// resetMemory method reset status of memory check to healthy & respond history produced in that reset
+func (ah *adminHandler) resetMemory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ah.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	req, ok := decodeResetRequest(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := ah.MUsecase.ResetStatus(r.Context(), req.By, req.Reason)
+	if history == nil {
+		writeResetResponse(w, "", "", "", err)
+		return
+	}
+	writeResetResponse(w, history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// writeResetResponse write uuid and process level of history produced in reset process as response
+func writeResetResponse(w http.ResponseWriter, uuid, level, message string, err error) {
+	resp := checkResponse{UUID: uuid, ProcessLevel: level, Message: message}
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
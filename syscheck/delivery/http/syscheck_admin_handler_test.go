@@ -0,0 +1,247 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fakeCPUUsecase is CPUCheckUseCase recording check run, histories query & reset by administrator
+type fakeCPUUsecase struct {
+	domain.CPUCheckUseCase
+	checked  int
+	found    int
+	resetBy  string
+	reason   string
+	resetErr error
+}
+
+func (u *fakeCPUUsecase) GetStatus() string                       { return "HEALTHY" }
+func (u *fakeCPUUsecase) GetLastHistory() *domain.CPUCheckHistory { return nil }
+
+func (u *fakeCPUUsecase) CheckCPU(context.Context) (*domain.CPUCheckHistory, error) {
+	u.checked++
+	history := &domain.CPUCheckHistory{}
+	history.FillPrivateComponent()
+	history.UUID = "uuid-check"
+	history.ProcessLevel.Set("HEALTHY")
+	return history, nil
+}
+
+func (u *fakeCPUUsecase) GetHistory(_ context.Context, uuid string) (*domain.CPUCheckHistory, error) {
+	u.found++
+	if uuid != "uuid-check" {
+		return nil, nil
+	}
+	history := &domain.CPUCheckHistory{}
+	history.FillPrivateComponent()
+	history.UUID = uuid
+	return history, nil
+}
+
+func (u *fakeCPUUsecase) FindHistories(context.Context, string, time.Time, time.Time) ([]*domain.CPUCheckHistory, error) {
+	u.found++
+	return nil, nil
+}
+
+func (u *fakeCPUUsecase) FindLatestHistories(context.Context, int) ([]*domain.CPUCheckHistory, error) {
+	u.found++
+	return nil, nil
+}
+
+func (u *fakeCPUUsecase) ResetStatus(_ context.Context, by, reason string) (*domain.CPUCheckHistory, error) {
+	u.resetBy, u.reason = by, reason
+	history := &domain.CPUCheckHistory{}
+	history.FillPrivateComponent()
+	history.UUID = "uuid-reset"
+	history.ProcessLevel.Set("RESET")
+	return history, u.resetErr
+}
+
+type fakeDiskUsecase struct {
+	domain.DiskCheckUseCase
+}
+
+func (fakeDiskUsecase) GetStatus() string                        { return "HEALTHY" }
+func (fakeDiskUsecase) GetLastHistory() *domain.DiskCheckHistory { return nil }
+
+type fakeMemoryUsecase struct {
+	domain.MemoryCheckUseCase
+}
+
+func (fakeMemoryUsecase) GetStatus() string                          { return "HEALTHY" }
+func (fakeMemoryUsecase) GetLastHistory() *domain.MemoryCheckHistory { return nil }
+
+// serveRequest serve request having method, path, body & token (not set if empty) with mux and return response
+func serveRequest(mux *http.ServeMux, method, path, body, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	return w
+}
+
+func TestAdminHandler(t *testing.T) {
+	const validBody = `{"by": "admin", "reason": "container is fixed"}`
+
+	for _, tc := range []struct {
+		name        string
+		serverToken string
+		method      string
+		path        string
+		token       string
+		body        string
+		code        int
+		reset       bool // if cpu check status is reset by request
+	}{
+		{
+			name:        "GET status",
+			serverToken: testToken,
+			method:      http.MethodGet,
+			path:        "/admin/syscheck/status",
+			token:       testToken,
+			code:        http.StatusOK,
+		}, {
+			name:        "GET status without token",
+			serverToken: testToken,
+			method:      http.MethodGet,
+			path:        "/admin/syscheck/status",
+			code:        http.StatusUnauthorized,
+		}, {
+			name:        "POST status",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/status",
+			token:       testToken,
+			code:        http.StatusMethodNotAllowed,
+		}, {
+			name:        "POST reset",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/cpu/reset",
+			token:       testToken,
+			body:        validBody,
+			code:        http.StatusOK,
+			reset:       true,
+		}, {
+			name:        "POST reset without token",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/cpu/reset",
+			body:        validBody,
+			code:        http.StatusUnauthorized,
+		}, {
+			name:        "POST reset with wrong token",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/cpu/reset",
+			token:       "wrong-token",
+			body:        validBody,
+			code:        http.StatusUnauthorized,
+		}, {
+			name:        "GET reset",
+			serverToken: testToken,
+			method:      http.MethodGet,
+			path:        "/admin/syscheck/cpu/reset",
+			token:       testToken,
+			code:        http.StatusMethodNotAllowed,
+		}, {
+			name:        "POST reset without by",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/cpu/reset",
+			token:       testToken,
+			body:        `{"reason": "container is fixed"}`,
+			code:        http.StatusBadRequest,
+		}, {
+			name:        "POST reset without reason",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/cpu/reset",
+			token:       testToken,
+			body:        `{"by": "admin"}`,
+			code:        http.StatusBadRequest,
+		}, {
+			name:        "POST reset with broken body",
+			serverToken: testToken,
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/cpu/reset",
+			token:       testToken,
+			body:        `{broken`,
+			code:        http.StatusBadRequest,
+		}, {
+			name:        "POST reset while admin API is disabled",
+			serverToken: "",
+			method:      http.MethodPost,
+			path:        "/admin/syscheck/cpu/reset",
+			body:        validBody,
+			code:        http.StatusUnauthorized,
+		}, {
+			name:        "GET status while admin API is disabled",
+			serverToken: "",
+			method:      http.MethodGet,
+			path:        "/admin/syscheck/status",
+			token:       testToken,
+			code:        http.StatusUnauthorized,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cu := &fakeCPUUsecase{}
+			mux := http.NewServeMux()
+			NewAdminHandler(mux, tc.serverToken, fakeDiskUsecase{}, cu, fakeMemoryUsecase{})
+
+			w := serveRequest(mux, tc.method, tc.path, tc.body, tc.token)
+			if w.Code != tc.code {
+				t.Errorf("status code should be %d, got: %d, body: %s", tc.code, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Error("unauthorized response should have WWW-Authenticate header with Bearer scheme")
+			}
+			if reset := cu.resetBy != ""; reset != tc.reset {
+				t.Errorf("if cpu check status is reset should be %t, got: %t", tc.reset, reset)
+			}
+			if tc.reset && (cu.resetBy != "admin" || cu.reason != "container is fixed") {
+				t.Errorf("reset should be delivered with by & reason in body, got: %s, %s", cu.resetBy, cu.reason)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		token  string
+		header string
+		ok     bool
+	}{
+		{name: "same bearer token", token: testToken, header: "Bearer " + testToken, ok: true},
+		{name: "no authorization header", token: testToken, header: "", ok: false},
+		{name: "wrong bearer token", token: testToken, header: "Bearer wrong-token", ok: false},
+		{name: "token without bearer scheme", token: testToken, header: testToken, ok: false},
+		{name: "basic scheme", token: testToken, header: "Basic " + testToken, ok: false},
+		{name: "empty bearer token while token is unset", token: "", header: "Bearer ", ok: false},
+		{name: "no authorization header while token is unset", token: "", header: "", ok: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/admin/syscheck/status", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+
+			if ok := authorize(w, r, tc.token); ok != tc.ok {
+				t.Fatalf("if request is authorized should be %t, got: %t", tc.ok, ok)
+			}
+			if !tc.ok && w.Code != http.StatusUnauthorized {
+				t.Errorf("status code of unauthorized request should be 401, got: %d", w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// in syscheck_cpu_handler.go file, define delivery from http request to cpu check usecase handler
+// registered path is prefixed with domain & check name, Ex) /syscheck/cpu/status, /syscheck/cpu/check, /syscheck/cpu/histories
+
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// cpuCheckHandler is delivered data handler about cpu check using usecase layer
+type cpuCheckHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token to run check on demand
+	token string
+
+	// CUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	CUsecase domain.CPUCheckUseCase
+}
+
+// NewCPUCheckHandler define cpuCheckHandler ptr instance & register handling http request to usecase
+func NewCPUCheckHandler(r router, token string, cu domain.CPUCheckUseCase) {
+	handler := &cpuCheckHandler{
+		token:    token,
+		CUsecase: cu,
+	}
+
+	r.HandleFunc("/syscheck/cpu/status", handler.getStatus)
+	r.HandleFunc("/syscheck/cpu/check", handler.runCheck)
+	r.HandleFunc("/syscheck/cpu/histories", handler.getHistories)
+	r.HandleFunc("/syscheck/cpu/histories/", handler.getHistory)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SYSTEM CPU CHECK")
+}
+
+// getStatus method respond current status of cpu check & history produced in last check process
+func (ch *cpuCheckHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := statusResponse{Status: ch.CUsecase.GetStatus()}
+	if history := ch.CUsecase.GetLastHistory(); history != nil {
+		resp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runCheck method run cpu check immediately & respond uuid and process level of history produced in that process
+// history is stored in repository in usecase layer same as check process run by channel delivery
+// check process can remediate runtime environment, so admin token is required same as admin handler
+func (ch *cpuCheckHandler) runCheck(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ch.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	history, err := ch.CUsecase.CheckCPU(newCheckContext())
+	resp := checkResponse{
+		UUID:         history.UUID,
+		ProcessLevel: history.ProcessLevel.String(),
+		Message:      history.Message,
+	}
+	if err != nil {
+		log.Printf("error occurs in CheckCPU, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistories method respond cpu check histories stored in time range (filtered by process level), or latest histories
+// Ex) /syscheck/cpu/histories?latest=20, /syscheck/cpu/histories?start=2020-12-01T00:00:00+09:00&level=UNHEALTHY
+func (ch *cpuCheckHandler) getHistories(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ch.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	q, ok := parseHistoriesQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var histories []*domain.CPUCheckHistory
+	var err error
+	if q.Latest != 0 {
+		histories, err = ch.CUsecase.FindLatestHistories(r.Context(), q.Latest)
+	} else {
+		histories, err = ch.CUsecase.FindHistories(r.Context(), q.Level, q.Start, q.End)
+	}
+	if err != nil {
+		log.Printf("error occurs in finding cpu check histories, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := historiesResponse{Histories: make([]map[string]interface{}, 0, len(histories))}
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, history.DottedMapWithPrefix(""))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistory method respond cpu check history having uuid in path, Ex) /syscheck/cpu/histories/{uuid}
+func (ch *cpuCheckHandler) getHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, ch.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	uuid := strings.TrimPrefix(r.URL.Path, "/syscheck/cpu/histories/")
+	if uuid == "" || strings.Contains(uuid, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+
+	history, err := ch.CUsecase.GetHistory(r.Context(), uuid)
+	if err != nil {
+		log.Printf("error occurs in GetHistory, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	} else if history == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, history.DottedMapWithPrefix(""))
+}
@@ -0,0 +1,127 @@
+// in syscheck_disk_handler.go file, define delivery from http request to disk check usecase handler
+// registered path is prefixed with domain & check name, Ex) /syscheck/disk/status, /syscheck/disk/check, /syscheck/disk/histories
+
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// diskCheckHandler is delivered data handler about disk check using usecase layer
+type diskCheckHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token to run check on demand
+	token string
+
+	// DUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	DUsecase domain.DiskCheckUseCase
+}
+
+// NewDiskCheckHandler define diskCheckHandler ptr instance & register handling http request to usecase
+func NewDiskCheckHandler(r router, token string, du domain.DiskCheckUseCase) {
+	handler := &diskCheckHandler{
+		token:    token,
+		DUsecase: du,
+	}
+
+	r.HandleFunc("/syscheck/disk/status", handler.getStatus)
+	r.HandleFunc("/syscheck/disk/check", handler.runCheck)
+	r.HandleFunc("/syscheck/disk/histories", handler.getHistories)
+	r.HandleFunc("/syscheck/disk/histories/", handler.getHistory)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SYSTEM DISK CHECK")
+}
+
+// getStatus method respond current status of disk check & history produced in last check process
+func (dh *diskCheckHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := statusResponse{Status: dh.DUsecase.GetStatus()}
+	if history := dh.DUsecase.GetLastHistory(); history != nil {
+		resp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runCheck method run disk check immediately & respond uuid and process level of history produced in that process
+// history is stored in repository in usecase layer same as check process run by channel delivery
+// check process can remediate runtime environment, so admin token is required same as admin handler
+func (dh *diskCheckHandler) runCheck(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, dh.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	history, err := dh.DUsecase.CheckDisk(newCheckContext())
+	resp := checkResponse{
+		UUID:         history.UUID,
+		ProcessLevel: history.ProcessLevel.String(),
+		Message:      history.Message,
+	}
+	if err != nil {
+		log.Printf("error occurs in CheckDisk, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistories method respond disk check histories stored in time range (filtered by process level), or latest histories
+// Ex) /syscheck/disk/histories?latest=20, /syscheck/disk/histories?start=2020-12-01T00:00:00+09:00&level=UNHEALTHY
+func (dh *diskCheckHandler) getHistories(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, dh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	q, ok := parseHistoriesQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var histories []*domain.DiskCheckHistory
+	var err error
+	if q.Latest != 0 {
+		histories, err = dh.DUsecase.FindLatestHistories(r.Context(), q.Latest)
+	} else {
+		histories, err = dh.DUsecase.FindHistories(r.Context(), q.Level, q.Start, q.End)
+	}
+	if err != nil {
+		log.Printf("error occurs in finding disk check histories, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := historiesResponse{Histories: make([]map[string]interface{}, 0, len(histories))}
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, history.DottedMapWithPrefix(""))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistory method respond disk check history having uuid in path, Ex) /syscheck/disk/histories/{uuid}
+func (dh *diskCheckHandler) getHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, dh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	uuid := strings.TrimPrefix(r.URL.Path, "/syscheck/disk/histories/")
+	if uuid == "" || strings.Contains(uuid, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+
+	history, err := dh.DUsecase.GetHistory(r.Context(), uuid)
+	if err != nil {
+		log.Printf("error occurs in GetHistory, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	} else if history == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, history.DottedMapWithPrefix(""))
+}
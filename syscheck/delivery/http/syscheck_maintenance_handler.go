@@ -0,0 +1,105 @@
+// in syscheck_maintenance_handler.go file, define delivery from http request to maintenance window administration
+// maintenance window covers checks of every domain, so this handler is registered only once here, not in srvcheck
+// every path requires admin token, Ex) GET, POST /admin/maintenance/windows, DELETE /admin/maintenance/windows/{id}
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// maintenanceWindowsPath is path of maintenance window admin API, window having id is handled with path + "/{id}"
+const maintenanceWindowsPath = "/admin/maintenance/windows"
+
+// maintenanceAgency is agency that manage maintenance windows added in config file or admin API
+// you can see implementation in maintenance package
+type maintenanceAgency interface {
+	// GetWindows return every maintenance window not ended yet
+	GetWindows() []domain.MaintenanceWindow
+
+	// AddWindow add maintenance window after validating it & return added window having generated id
+	AddWindow(window domain.MaintenanceWindow) (domain.MaintenanceWindow, error)
+
+	// RemoveWindow remove maintenance window having id & return if window was exist
+	RemoveWindow(id string) bool
+}
+
+// maintenanceHandler is delivered data handler about maintenance window administration using maintenance agency
+type maintenanceHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token
+	token string
+
+	// maintenanceAgency is used for adding, removing & getting maintenance windows
+	maintenanceAgency maintenanceAgency
+}
+
+// NewMaintenanceHandler define maintenanceHandler ptr instance & register handling http request to maintenance agency
+func NewMaintenanceHandler(r router, token string, ma maintenanceAgency) {
+	handler := &maintenanceHandler{
+		token:             token,
+		maintenanceAgency: ma,
+	}
+
+	r.HandleFunc(maintenanceWindowsPath, handler.windows)
+	r.HandleFunc(maintenanceWindowsPath+"/", handler.removeWindow)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT MAINTENANCE WINDOW ADMINISTRATION")
+}
+
+// windows method respond every maintenance window with GET method, and add maintenance window with POST method
+func (mh *maintenanceHandler) windows(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, mh.token) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, mh.maintenanceAgency.GetWindows())
+	case http.MethodPost:
+		var window domain.MaintenanceWindow
+		if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body, " + err.Error()})
+			return
+		}
+		if window.CreatedBy == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "created_by is required in request body"})
+			return
+		}
+		if window.CreatedBy == domain.MaintenanceConfigCreator {
+			msg := fmt.Sprintf("created_by %q is reserved for maintenance window defined in config file", window.CreatedBy)
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		added, err := mh.maintenanceAgency.AddWindow(window)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		log.Printf("maintenance window is added by %s, id: %s, reason: %s", added.CreatedBy, added.ID, added.Reason)
+		writeJSON(w, http.StatusCreated, added)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// removeWindow method remove maintenance window having id in path with DELETE method
+func (mh *maintenanceHandler) removeWindow(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, mh.token) || !allowMethod(w, r, http.MethodDelete) {
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, maintenanceWindowsPath+"/")
+	if !mh.maintenanceAgency.RemoveWindow(id) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "maintenance window not found"})
+		return
+	}
+	log.Printf("maintenance window is removed, id: %s", id)
+	w.WriteHeader(http.StatusNoContent)
+}
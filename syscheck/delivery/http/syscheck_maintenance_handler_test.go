@@ -0,0 +1,178 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+const testToken = "test-admin-token"
+
+// fakeMaintenanceAgency is maintenanceAgency keeping maintenance windows in memory
+type fakeMaintenanceAgency struct {
+	windows []domain.MaintenanceWindow
+}
+
+func (ma *fakeMaintenanceAgency) GetWindows() []domain.MaintenanceWindow {
+	return ma.windows
+}
+
+func (ma *fakeMaintenanceAgency) AddWindow(window domain.MaintenanceWindow) (domain.MaintenanceWindow, error) {
+	if len(window.Checks) == 0 && len(window.Containers) == 0 {
+		return domain.MaintenanceWindow{}, errors.New("at least one of checks or containers is required")
+	}
+	window.ID = "added-window"
+	ma.windows = append(ma.windows, window)
+	return window, nil
+}
+
+func (ma *fakeMaintenanceAgency) RemoveWindow(id string) bool {
+	for i, window := range ma.windows {
+		if window.ID == id {
+			ma.windows = append(ma.windows[:i], ma.windows[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func TestMaintenanceHandler(t *testing.T) {
+	const validWindow = `{"checks": ["syscheck"], "start_at": "2021-03-01T09:00:00Z", "end_at": "2021-03-01T10:00:00Z", `
+
+	for _, tc := range []struct {
+		name    string
+		method  string
+		path    string
+		token   string
+		body    string
+		code    int
+		windows int // number of windows in agency after request
+	}{
+		{
+			name:    "GET without token",
+			method:  http.MethodGet,
+			path:    maintenanceWindowsPath,
+			code:    http.StatusUnauthorized,
+			windows: 1,
+		}, {
+			name:    "GET with wrong token",
+			method:  http.MethodGet,
+			path:    maintenanceWindowsPath,
+			token:   "wrong-token",
+			code:    http.StatusUnauthorized,
+			windows: 1,
+		}, {
+			name:    "GET windows",
+			method:  http.MethodGet,
+			path:    maintenanceWindowsPath,
+			token:   testToken,
+			code:    http.StatusOK,
+			windows: 1,
+		}, {
+			name:    "POST window",
+			method:  http.MethodPost,
+			path:    maintenanceWindowsPath,
+			token:   testToken,
+			body:    validWindow + `"created_by": "admin"}`,
+			code:    http.StatusCreated,
+			windows: 2,
+		}, {
+			name:    "POST window without token",
+			method:  http.MethodPost,
+			path:    maintenanceWindowsPath,
+			body:    validWindow + `"created_by": "admin"}`,
+			code:    http.StatusUnauthorized,
+			windows: 1,
+		}, {
+			name:    "POST window without creator",
+			method:  http.MethodPost,
+			path:    maintenanceWindowsPath,
+			token:   testToken,
+			body:    validWindow + `"created_by": ""}`,
+			code:    http.StatusBadRequest,
+			windows: 1,
+		}, {
+			name:    "POST window with creator reserved for config file",
+			method:  http.MethodPost,
+			path:    maintenanceWindowsPath,
+			token:   testToken,
+			body:    validWindow + `"created_by": "config"}`,
+			code:    http.StatusBadRequest,
+			windows: 1,
+		}, {
+			name:    "POST invalid window",
+			method:  http.MethodPost,
+			path:    maintenanceWindowsPath,
+			token:   testToken,
+			body:    `{"created_by": "admin"}`,
+			code:    http.StatusBadRequest,
+			windows: 1,
+		}, {
+			name:    "POST broken body",
+			method:  http.MethodPost,
+			path:    maintenanceWindowsPath,
+			token:   testToken,
+			body:    `{broken`,
+			code:    http.StatusBadRequest,
+			windows: 1,
+		}, {
+			name:    "PUT windows",
+			method:  http.MethodPut,
+			path:    maintenanceWindowsPath,
+			token:   testToken,
+			code:    http.StatusMethodNotAllowed,
+			windows: 1,
+		}, {
+			name:    "DELETE window",
+			method:  http.MethodDelete,
+			path:    maintenanceWindowsPath + "/window-1",
+			token:   testToken,
+			code:    http.StatusNoContent,
+			windows: 0,
+		}, {
+			name:    "DELETE window without token",
+			method:  http.MethodDelete,
+			path:    maintenanceWindowsPath + "/window-1",
+			code:    http.StatusUnauthorized,
+			windows: 1,
+		}, {
+			name:    "DELETE window not exist",
+			method:  http.MethodDelete,
+			path:    maintenanceWindowsPath + "/not-exist",
+			token:   testToken,
+			code:    http.StatusNotFound,
+			windows: 1,
+		}, {
+			name:    "GET window with id",
+			method:  http.MethodGet,
+			path:    maintenanceWindowsPath + "/window-1",
+			token:   testToken,
+			code:    http.StatusMethodNotAllowed,
+			windows: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			agency := &fakeMaintenanceAgency{windows: []domain.MaintenanceWindow{{ID: "window-1", Checks: []string{"*"}, CreatedBy: "admin"}}}
+			mux := http.NewServeMux()
+			NewMaintenanceHandler(mux, testToken, agency)
+
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if w.Code != tc.code {
+				t.Errorf("status code should be %d, got: %d, body: %s", tc.code, w.Code, w.Body.String())
+			}
+			if len(agency.windows) != tc.windows {
+				t.Errorf("number of windows should be %d, got: %d", tc.windows, len(agency.windows))
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// in syscheck_memory_handler.go file, define delivery from http request to memory check usecase handler
+// registered path is prefixed with domain & check name, Ex) /syscheck/memory/status, /syscheck/memory/check, /syscheck/memory/histories
+
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// memoryCheckHandler is delivered data handler about memory check using usecase layer
+type memoryCheckHandler struct {
+	// token is admin API token which should be delivered in Authorization header as Bearer token to run check on demand
+	token string
+
+	// MUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	MUsecase domain.MemoryCheckUseCase
+}
+
+// NewMemoryCheckHandler define memoryCheckHandler ptr instance & register handling http request to usecase
+func NewMemoryCheckHandler(r router, token string, mu domain.MemoryCheckUseCase) {
+	handler := &memoryCheckHandler{
+		token:    token,
+		MUsecase: mu,
+	}
+
+	r.HandleFunc("/syscheck/memory/status", handler.getStatus)
+	r.HandleFunc("/syscheck/memory/check", handler.runCheck)
+	r.HandleFunc("/syscheck/memory/histories", handler.getHistories)
+	r.HandleFunc("/syscheck/memory/histories/", handler.getHistory)
+	log.Println("START TO HANDLE HTTP REQUEST ABOUT SYSTEM MEMORY CHECK")
+}
+
+// getStatus method respond current status of memory check & history produced in last check process
+func (mh *memoryCheckHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	resp := statusResponse{Status: mh.MUsecase.GetStatus()}
+	if history := mh.MUsecase.GetLastHistory(); history != nil {
+		resp.LastHistory = history.DottedMapWithPrefix("")
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// runCheck method run memory check immediately & respond uuid and process level of history produced in that process
+// history is stored in repository in usecase layer same as check process run by channel delivery
+// check process can remediate runtime environment, so admin token is required same as admin handler
+func (mh *memoryCheckHandler) runCheck(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, mh.token) || !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	history, err := mh.MUsecase.CheckMemory(newCheckContext())
+	resp := checkResponse{
+		UUID:         history.UUID,
+		ProcessLevel: history.ProcessLevel.String(),
+		Message:      history.Message,
+	}
+	if err != nil {
+		log.Printf("error occurs in CheckMemory, err: %v", err)
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistories method respond memory check histories stored in time range (filtered by process level), or latest histories
+// Ex) /syscheck/memory/histories?latest=20, /syscheck/memory/histories?start=2020-12-01T00:00:00+09:00&level=UNHEALTHY
+func (mh *memoryCheckHandler) getHistories(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, mh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	q, ok := parseHistoriesQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var histories []*domain.MemoryCheckHistory
+	var err error
+	if q.Latest != 0 {
+		histories, err = mh.MUsecase.FindLatestHistories(r.Context(), q.Latest)
+	} else {
+		histories, err = mh.MUsecase.FindHistories(r.Context(), q.Level, q.Start, q.End)
+	}
+	if err != nil {
+		log.Printf("error occurs in finding memory check histories, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := historiesResponse{Histories: make([]map[string]interface{}, 0, len(histories))}
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, history.DottedMapWithPrefix(""))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// getHistory method respond memory check history having uuid in path, Ex) /syscheck/memory/histories/{uuid}
+func (mh *memoryCheckHandler) getHistory(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r, mh.token) || !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	uuid := strings.TrimPrefix(r.URL.Path, "/syscheck/memory/histories/")
+	if uuid == "" || strings.Contains(uuid, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+
+	history, err := mh.MUsecase.GetHistory(r.Context(), uuid)
+	if err != nil {
+		log.Printf("error occurs in GetHistory, err: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	} else if history == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, history.DottedMapWithPrefix(""))
+}
@@ -0,0 +1,63 @@
+// in syscheck_cpu_handler.go file, define delivery from slack command to cpu check usecase handler
+// registered command is consisted of action & cpu target, Ex) status cpu, run cpu, reset cpu <reason>
+
+package slack
+
+import (
+	"context"
+	"log"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// cpuCheckHandler is delivered data handler about cpu check using usecase layer
+type cpuCheckHandler struct {
+	// CUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	CUsecase domain.CPUCheckUseCase
+}
+
+// NewCPUCheckHandler define cpuCheckHandler ptr instance & register handling slack command to usecase
+func NewCPUCheckHandler(r commandRouter, cu domain.CPUCheckUseCase) {
+	handler := &cpuCheckHandler{
+		CUsecase: cu,
+	}
+
+	r.HandleCommand("status", "cpu", handler.status)
+	r.HandleCommand("run", "cpu", handler.run)
+	r.HandleCommand("reset", "cpu", handler.reset)
+	log.Println("START TO HANDLE SLACK COMMAND ABOUT SYSTEM CPU CHECK")
+}
+
+// status method reply current status of cpu check & history produced in last check process
+func (ch *cpuCheckHandler) status(_ context.Context, _ string, _ []string) string {
+	if history := ch.CUsecase.GetLastHistory(); history != nil {
+		return statusReply("cpu", ch.CUsecase.GetStatus(), history.UUID, history.ProcessLevel.String(), history.Message)
+	}
+	return statusReply("cpu", ch.CUsecase.GetStatus(), "", "", "")
+}
+
+// run method run cpu check immediately & reply history produced in that process
+func (ch *cpuCheckHandler) run(ctx context.Context, _ string, _ []string) string {
+	history, err := ch.CUsecase.CheckCPU(ctx)
+	if err != nil {
+		log.Printf("error occurs in CheckCPU, err: %v", err)
+	}
+	return historyReply("cpu", "check", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// reset method reset status of cpu check to healthy with reason in args & reply history produced in that reset
+func (ch *cpuCheckHandler) reset(ctx context.Context, user string, args []string) string {
+	reason, ok := resetReason("cpu", args)
+	if !ok {
+		return reason
+	}
+
+	history, err := ch.CUsecase.ResetStatus(ctx, user, reason)
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+	}
+	if history == nil {
+		return historyReply("cpu", "reset", "", "", "", err)
+	}
+	return historyReply("cpu", "reset", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
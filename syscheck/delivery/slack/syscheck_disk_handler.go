@@ -0,0 +1,63 @@
+// in syscheck_disk_handler.go file, define delivery from slack command to disk check usecase handler
+// registered command is consisted of action & disk target, Ex) status disk, run disk, reset disk <reason>
+
+package slack
+
+import (
+	"context"
+	"log"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// diskCheckHandler is delivered data handler about disk check using usecase layer
+type diskCheckHandler struct {
+	// DUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	DUsecase domain.DiskCheckUseCase
+}
+
+// NewDiskCheckHandler define diskCheckHandler ptr instance & register handling slack command to usecase
+func NewDiskCheckHandler(r commandRouter, du domain.DiskCheckUseCase) {
+	handler := &diskCheckHandler{
+		DUsecase: du,
+	}
+
+	r.HandleCommand("status", "disk", handler.status)
+	r.HandleCommand("run", "disk", handler.run)
+	r.HandleCommand("reset", "disk", handler.reset)
+	log.Println("START TO HANDLE SLACK COMMAND ABOUT SYSTEM DISK CHECK")
+}
+
+// status method reply current status of disk check & history produced in last check process
+func (dh *diskCheckHandler) status(_ context.Context, _ string, _ []string) string {
+	if history := dh.DUsecase.GetLastHistory(); history != nil {
+		return statusReply("disk", dh.DUsecase.GetStatus(), history.UUID, history.ProcessLevel.String(), history.Message)
+	}
+	return statusReply("disk", dh.DUsecase.GetStatus(), "", "", "")
+}
+
+// run method run disk check immediately & reply history produced in that process
+func (dh *diskCheckHandler) run(ctx context.Context, _ string, _ []string) string {
+	history, err := dh.DUsecase.CheckDisk(ctx)
+	if err != nil {
+		log.Printf("error occurs in CheckDisk, err: %v", err)
+	}
+	return historyReply("disk", "check", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// reset method reset status of disk check to healthy with reason in args & reply history produced in that reset
+func (dh *diskCheckHandler) reset(ctx context.Context, user string, args []string) string {
+	reason, ok := resetReason("disk", args)
+	if !ok {
+		return reason
+	}
+
+	history, err := dh.DUsecase.ResetStatus(ctx, user, reason)
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+	}
+	if history == nil {
+		return historyReply("disk", "reset", "", "", "", err)
+	}
+	return historyReply("disk", "reset", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
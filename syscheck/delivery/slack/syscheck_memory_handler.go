@@ -0,0 +1,63 @@
+// in syscheck_memory_handler.go file, define delivery from slack command to memory check usecase handler
+// registered command is consisted of action & memory target, Ex) status memory, run memory, reset memory <reason>
+
+package slack
+
+import (
+	"context"
+	"log"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// memoryCheckHandler is delivered data handler about memory check using usecase layer
+type memoryCheckHandler struct {
+	// MUsecase is usecase layer interface which is injected from package outside (maybe, in main)
+	MUsecase domain.MemoryCheckUseCase
+}
+
+// NewMemoryCheckHandler define memoryCheckHandler ptr instance & register handling slack command to usecase
+func NewMemoryCheckHandler(r commandRouter, mu domain.MemoryCheckUseCase) {
+	handler := &memoryCheckHandler{
+		MUsecase: mu,
+	}
+
+	r.HandleCommand("status", "memory", handler.status)
+	r.HandleCommand("run", "memory", handler.run)
+	r.HandleCommand("reset", "memory", handler.reset)
+	log.Println("START TO HANDLE SLACK COMMAND ABOUT SYSTEM MEMORY CHECK")
+}
+
+// status method reply current status of memory check & history produced in last check process
+func (mh *memoryCheckHandler) status(_ context.Context, _ string, _ []string) string {
+	if history := mh.MUsecase.GetLastHistory(); history != nil {
+		return statusReply("memory", mh.MUsecase.GetStatus(), history.UUID, history.ProcessLevel.String(), history.Message)
+	}
+	return statusReply("memory", mh.MUsecase.GetStatus(), "", "", "")
+}
+
+// run method run memory check immediately & reply history produced in that process
+func (mh *memoryCheckHandler) run(ctx context.Context, _ string, _ []string) string {
+	history, err := mh.MUsecase.CheckMemory(ctx)
+	if err != nil {
+		log.Printf("error occurs in CheckMemory, err: %v", err)
+	}
+	return historyReply("memory", "check", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
+
+// reset method reset status of memory check to healthy with reason in args & reply history produced in that reset
+func (mh *memoryCheckHandler) reset(ctx context.Context, user string, args []string) string {
+	reason, ok := resetReason("memory", args)
+	if !ok {
+		return reason
+	}
+
+	history, err := mh.MUsecase.ResetStatus(ctx, user, reason)
+	if err != nil {
+		log.Printf("error occurs in ResetStatus, err: %v", err)
+	}
+	if history == nil {
+		return historyReply("memory", "reset", "", "", "", err)
+	}
+	return historyReply("memory", "reset", history.UUID, history.ProcessLevel.String(), history.Message, err)
+}
@@ -5,12 +5,8 @@
 package elasticsearch
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/pkg/errors"
 	"log"
 	"time"
@@ -29,8 +25,11 @@ type esCPUCheckHistoryRepository struct {
 	// esCli is elasticsearch client connection injected from the outside package
 	esCli *elasticsearch.Client
 
-	// bodyWriter is implementation of reqBodyWriter interface to write []byte for request body
-	bodyWriter reqBodyWriter
+	// bulkIndexer is used for indexing history document with bulk API, injected from the outside package
+	bulkIndexer bulkIndexer
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to request body
+	docEncoder docEncoder
 }
 
 // esCPUCheckHistoryRepoConfig is the config for cpu check history repository using elasticsearch
@@ -39,12 +38,22 @@ type esCPUCheckHistoryRepoConfig interface {
 	esRepositoryComponentConfig
 }
 
+// cpuCheckMappingProperties is mapping properties about fields of CPUCheckHistory, merged into index mapping
+var cpuCheckMappingProperties = map[string]interface{}{
+	"total_usage_core":           doubleField,
+	"docker_usage_core":          doubleField,
+	"temporary_free_core":        doubleField,
+	"most_cpu_consume_container": keywordField,
+	"removed_container":          keywordField,
+}
+
 // NewESCPUCheckHistoryRepository return new object that implement CPUCheckHistoryRepository interface
-func NewESCPUCheckHistoryRepository(cfg esCPUCheckHistoryRepoConfig, cli *elasticsearch.Client, w reqBodyWriter) domain.CPUCheckHistoryRepository {
+func NewESCPUCheckHistoryRepository(cfg esCPUCheckHistoryRepoConfig, cli *elasticsearch.Client, bi bulkIndexer, e docEncoder) domain.CPUCheckHistoryRepository {
 	repo := &esCPUCheckHistoryRepository{
-		myCfg:      cfg,
-		esCli:      cli,
-		bodyWriter: w,
+		myCfg:       cfg,
+		esCli:       cli,
+		bulkIndexer: bi,
+		docEncoder:  e,
 	}
 
 	if err := repo.Migrate(); err != nil {
@@ -56,39 +65,76 @@ func NewESCPUCheckHistoryRepository(cfg esCPUCheckHistoryRepoConfig, cli *elasti
 
 // Implement Migrate method of CPUCheckHistoryRepository interface
 func (esr *esCPUCheckHistoryRepository) Migrate() error {
-	return esr.esMigrator.Migrate(esr.myCfg, esr.esCli, esr.bodyWriter)
+	return esr.esMigrator.Migrate(esr.myCfg, esr.esCli, esr.docEncoder)
 }
 
 // Implement Store method of CPUCheckHistoryRepository interface
 func (esr *esCPUCheckHistoryRepository) Store(history *domain.CPUCheckHistory) (b []byte, err error) {
-	body, _ := json.Marshal(history.DottedMapWithPrefix(""))
-	if _, err = esr.bodyWriter.Write(body); err != nil {
-		err = errors.Wrap(err, "failed to write map to body writer")
+	body, err := esr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
 		return
 	}
 
-	buf := &bytes.Buffer{}
-	if _, err = esr.bodyWriter.WriteTo(buf); err != nil {
-		err = errors.Wrap(err, "failed to body writer WriteTo method")
+	// history document is buffered & indexed later with bulk API, and spooled in disk if elasticsearch is unavailable
+	if err = esr.bulkIndexer.Index(esr.myCfg.IndexName(), history.UUID, body); err != nil {
+		err = errors.Wrap(err, "failed to buffer history document in bulk indexer")
+	}
+	return
+}
+
+// Implement UpdateAlarmResult method of CPUCheckHistoryRepository interface
+func (esr *esCPUCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return updateAlarmResult(esr.esCli, esr.bulkIndexer, esr.myCfg.IndexName(), uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of CPUCheckHistoryRepository interface
+func (esr *esCPUCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.CPUCheckHistory, err error) {
+	sources, err := searchByTimeRange(ctx, esr.esCli, esr.myCfg.IndexName(), "CPUCheck", start, end)
+	if err != nil {
 		return
 	}
+	histories = cpuCheckHistories(sources)
+	return
+}
 
-	resp, err := (esapi.IndexRequest{
-		Index:        esr.myCfg.IndexName(),
-		Body:         bytes.NewReader(buf.Bytes()),
-		Timeout:      time.Second * 5,
-	}).Do(context.Background(), esr.esCli)
+// Implement GetByUUID method of CPUCheckHistoryRepository interface
+func (esr *esCPUCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.CPUCheckHistory, err error) {
+	sources, err := searchLatest(ctx, esr.esCli, esr.myCfg.IndexName(), 1, typeFilter("CPUCheck"), idFilter(uuid))
+	if err != nil || len(sources) == 0 {
+		return
+	}
+	history = cpuCheckHistories(sources)[0]
+	return
+}
 
+// Implement FindByProcessLevel method of CPUCheckHistoryRepository interface
+func (esr *esCPUCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.CPUCheckHistory, err error) {
+	sources, err := searchByProcessLevel(ctx, esr.esCli, esr.myCfg.IndexName(), "CPUCheck", level, start, end)
 	if err != nil {
-		err = errors.Wrap(err, fmt.Sprintf("failed to call IndexRequest, resp: %+v", resp))
 		return
-	} else if resp.IsError() {
-		err = errors.Errorf("IndexRequest return error code, resp: %+v", resp)
+	}
+	histories = cpuCheckHistories(sources)
+	return
+}
+
+// Implement FindLatest method of CPUCheckHistoryRepository interface
+func (esr *esCPUCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.CPUCheckHistory, err error) {
+	sources, err := searchLatest(ctx, esr.esCli, esr.myCfg.IndexName(), n, typeFilter("CPUCheck"))
+	if err != nil {
 		return
 	}
+	histories = cpuCheckHistories(sources)
+	return
+}
 
-	result := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&result)
-	b, _ = json.Marshal(result)
+// cpuCheckHistories return CPUCheckHistory slice restored from sources of history documents
+func cpuCheckHistories(sources []map[string]interface{}) (histories []*domain.CPUCheckHistory) {
+	histories = make([]*domain.CPUCheckHistory, 0, len(sources))
+	for _, source := range sources {
+		history := new(domain.CPUCheckHistory)
+		history.FillFromDottedMap(source)
+		histories = append(histories, history)
+	}
 	return
 }
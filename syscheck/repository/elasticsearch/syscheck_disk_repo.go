@@ -5,12 +5,8 @@
 package elasticsearch
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/pkg/errors"
 	"log"
 	"time"
@@ -29,8 +25,11 @@ type esDiskCheckHistoryRepository struct {
 	// esCli is elasticsearch client connection injected from the outside package
 	esCli *elasticsearch.Client
 
-	// bodyWriter is implementation of reqBodyWriter interface to write []byte for request body
-	bodyWriter reqBodyWriter
+	// bulkIndexer is used for indexing history document with bulk API, injected from the outside package
+	bulkIndexer bulkIndexer
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to request body
+	docEncoder docEncoder
 }
 
 // esDiskCheckHistoryRepoConfig is the config for disk check history repository using elasticsearch
@@ -39,12 +38,22 @@ type esDiskCheckHistoryRepoConfig interface {
 	esRepositoryComponentConfig
 }
 
+// diskCheckMappingProperties is mapping properties about fields of DiskCheckHistory, merged into index mapping
+// capacity & memory usage are mapped as number of bytes, with human readable companion formatted with unit (Ex, 3.00GB)
+var diskCheckMappingProperties = map[string]interface{}{
+	"remaining_capacity":       longField,
+	"remaining_capacity_human": keywordField,
+	"reclaimed_capacity":       longField,
+	"reclaimed_capacity_human": keywordField,
+}
+
 // NewESDiskCheckHistoryRepository return new object that implement DiskCheckHistory.Repository interface
-func NewESDiskCheckHistoryRepository(cfg esDiskCheckHistoryRepoConfig, cli *elasticsearch.Client, w reqBodyWriter) domain.DiskCheckHistoryRepository {
+func NewESDiskCheckHistoryRepository(cfg esDiskCheckHistoryRepoConfig, cli *elasticsearch.Client, bi bulkIndexer, e docEncoder) domain.DiskCheckHistoryRepository {
 	repo := &esDiskCheckHistoryRepository{
-		myCfg:      cfg,
-		esCli:      cli,
-		bodyWriter: w,
+		myCfg:       cfg,
+		esCli:       cli,
+		bulkIndexer: bi,
+		docEncoder:  e,
 	}
 
 	if err := repo.Migrate(); err != nil {
@@ -56,39 +65,76 @@ func NewESDiskCheckHistoryRepository(cfg esDiskCheckHistoryRepoConfig, cli *elas
 
 // Implement Migrate method of DiskCheckHistoryRepository interface
 func (edr *esDiskCheckHistoryRepository) Migrate() error {
-	return edr.esMigrator.Migrate(edr.myCfg, edr.esCli, edr.bodyWriter)
+	return edr.esMigrator.Migrate(edr.myCfg, edr.esCli, edr.docEncoder)
 }
 
 // Implement Store method of DiskCheckHistoryRepository interface
 func (edr *esDiskCheckHistoryRepository) Store(history *domain.DiskCheckHistory) (b []byte, err error) {
-	body, _ := json.Marshal(history.DottedMapWithPrefix(""))
-	if _, err = edr.bodyWriter.Write(body); err != nil {
-		err = errors.Wrap(err, "failed to write map to body writer")
+	body, err := edr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
 		return
 	}
 
-	buf := &bytes.Buffer{}
-	if _, err = edr.bodyWriter.WriteTo(buf); err != nil {
-		err = errors.Wrap(err, "failed to body writer WriteTo method")
+	// history document is buffered & indexed later with bulk API, and spooled in disk if elasticsearch is unavailable
+	if err = edr.bulkIndexer.Index(edr.myCfg.IndexName(), history.UUID, body); err != nil {
+		err = errors.Wrap(err, "failed to buffer history document in bulk indexer")
+	}
+	return
+}
+
+// Implement UpdateAlarmResult method of DiskCheckHistoryRepository interface
+func (edr *esDiskCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return updateAlarmResult(edr.esCli, edr.bulkIndexer, edr.myCfg.IndexName(), uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of DiskCheckHistoryRepository interface
+func (edr *esDiskCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.DiskCheckHistory, err error) {
+	sources, err := searchByTimeRange(ctx, edr.esCli, edr.myCfg.IndexName(), "DiskCheck", start, end)
+	if err != nil {
 		return
 	}
+	histories = diskCheckHistories(sources)
+	return
+}
 
-	resp, err := (esapi.IndexRequest{
-		Index:        edr.myCfg.IndexName(),
-		Body:         bytes.NewReader(buf.Bytes()),
-		Timeout:      time.Second * 5,
-	}).Do(context.Background(), edr.esCli)
+// Implement GetByUUID method of DiskCheckHistoryRepository interface
+func (edr *esDiskCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.DiskCheckHistory, err error) {
+	sources, err := searchLatest(ctx, edr.esCli, edr.myCfg.IndexName(), 1, typeFilter("DiskCheck"), idFilter(uuid))
+	if err != nil || len(sources) == 0 {
+		return
+	}
+	history = diskCheckHistories(sources)[0]
+	return
+}
 
+// Implement FindByProcessLevel method of DiskCheckHistoryRepository interface
+func (edr *esDiskCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.DiskCheckHistory, err error) {
+	sources, err := searchByProcessLevel(ctx, edr.esCli, edr.myCfg.IndexName(), "DiskCheck", level, start, end)
 	if err != nil {
-		err = errors.Wrap(err, fmt.Sprintf("failed to call IndexRequest, resp: %+v", resp))
 		return
-	} else if resp.IsError() {
-		err = errors.Errorf("IndexRequest return error code, resp: %+v", resp)
+	}
+	histories = diskCheckHistories(sources)
+	return
+}
+
+// Implement FindLatest method of DiskCheckHistoryRepository interface
+func (edr *esDiskCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.DiskCheckHistory, err error) {
+	sources, err := searchLatest(ctx, edr.esCli, edr.myCfg.IndexName(), n, typeFilter("DiskCheck"))
+	if err != nil {
 		return
 	}
+	histories = diskCheckHistories(sources)
+	return
+}
 
-	result := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&result)
-	b, _ = json.Marshal(result)
+// diskCheckHistories return DiskCheckHistory slice restored from sources of history documents
+func diskCheckHistories(sources []map[string]interface{}) (histories []*domain.DiskCheckHistory) {
+	histories = make([]*domain.DiskCheckHistory, 0, len(sources))
+	for _, source := range sources {
+		history := new(domain.DiskCheckHistory)
+		history.FillFromDottedMap(source)
+		histories = append(histories, history)
+	}
 	return
 }
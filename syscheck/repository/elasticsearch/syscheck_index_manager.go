@@ -0,0 +1,209 @@
+// Create file in v.1.0.0
+// syscheck_index_manager.go is file that define index manager rolling over & deleting syscheck history indices
+// index manager is not repository, but it manages indices which every repository in this package writes through alias
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/pkg/errors"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// indexManageCycle is cycle to roll over write index & delete index passed retention period
+const indexManageCycle = time.Hour
+
+// esIndexManager is to roll over syscheck history index & delete index passed retention period
+type esIndexManager struct {
+	// myCfg is used for get index manager config about elasticsearch
+	myCfg esIndexManagerConfig
+
+	// esCli is elasticsearch client connection injected from the outside package
+	esCli *elasticsearch.Client
+}
+
+// esIndexManagerConfig is the config for index manager of syscheck history indices
+type esIndexManagerConfig interface {
+	// get common method from embedding esRepositoryComponentConfig
+	esRepositoryComponentConfig
+
+	// IndexRolloverPeriod method returns max age of write index, new write index is created after that
+	IndexRolloverPeriod() time.Duration
+
+	// IndexRetentionPeriod method returns period to keep history, index having only older history is deleted (0 if keep all)
+	IndexRetentionPeriod() time.Duration
+}
+
+// NewESIndexManager return new object managing syscheck history indices which repositories write through alias
+func NewESIndexManager(cfg esIndexManagerConfig, cli *elasticsearch.Client) *esIndexManager {
+	return &esIndexManager{
+		myCfg: cfg,
+		esCli: cli,
+	}
+}
+
+// Run start to roll over & delete expired index right now and every index manage cycle until ctx is done
+func (eim *esIndexManager) Run(ctx context.Context, wg *sync.WaitGroup) {
+	// count managing goroutine in wait group, so that shutdown waits for rollover or delete request in progress
+	// request in progress is canceled with ctx, so that waiting in shutdown is not blocked by unavailable elasticsearch
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(indexManageCycle)
+		defer ticker.Stop()
+
+		for {
+			if err := eim.rollover(ctx); err != nil {
+				log.Printf("failed to roll over %s index, err: %v", eim.myCfg.IndexName(), err)
+			}
+			if err := eim.deleteExpired(ctx); err != nil {
+				log.Printf("failed to delete expired %s index, err: %v", eim.myCfg.IndexName(), err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// rollover create new write index with settings & mapping if write index of alias is older than rollover period
+func (eim *esIndexManager) rollover(ctx context.Context) error {
+	body := map[string]interface{}{
+		"conditions": map[string]interface{}{"max_age": fmt.Sprintf("%ds", int64(eim.myCfg.IndexRolloverPeriod()/time.Second))},
+		"settings":   indexSettings(eim.myCfg),
+		"mappings":   indexMapping(),
+	}
+	b, _ := json.Marshal(body)
+
+	resp, err := (esapi.IndicesRolloverRequest{
+		Alias:         eim.myCfg.IndexName(),
+		Body:          bytes.NewReader(b),
+		MasterTimeout: time.Second * 5,
+		Timeout:       time.Second * 5,
+	}).Do(ctx, eim.esCli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesRollover, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("IndicesRollover return error code, resp: %+v", resp)
+	}
+
+	result := struct {
+		RolledOver bool   `json:"rolled_over"`
+		OldIndex   string `json:"old_index"`
+		NewIndex   string `json:"new_index"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return errors.Wrap(err, "failed to decode IndicesRollover response body")
+	}
+	if result.RolledOver {
+		log.Printf("rolled over %s index from %s to %s", eim.myCfg.IndexName(), result.OldIndex, result.NewIndex)
+	}
+	return nil
+}
+
+// deleteExpired delete index behind alias except write index, if index was rolled over before retention period
+// index created by rollover after an index has only history older than its creation date, so it is used as end of history
+// index of previous schema version was removed from alias in migration, so it is found with versioned name and deleted
+// if index created next to it (first index of next schema version) was created before retention period
+// legacy index named alias was cloned into backup index in migration, which is left to operator to delete
+func (eim *esIndexManager) deleteExpired(ctx context.Context) error {
+	retention := eim.myCfg.IndexRetentionPeriod()
+	if retention <= 0 {
+		return nil
+	}
+
+	alias := eim.myCfg.IndexName()
+	current, err := aliasIndices(ctx, eim.esCli, alias)
+	if err != nil {
+		return err
+	}
+
+	// indices of previous schema version are not behind alias anymore, so they are found with pattern of versioned name
+	names := []string{alias + "-v*-*"}
+	for name := range current {
+		names = append(names, name)
+	}
+
+	resp, err := (esapi.IndicesGetSettingsRequest{
+		Index: names,
+		Name:  []string{"index.creation_date"},
+	}).Do(ctx, eim.esCli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesGetSettings, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("IndicesGetSettings return error code, resp: %+v", resp)
+	}
+
+	result := map[string]struct {
+		Settings struct {
+			Index struct {
+				CreationDate string `json:"creation_date"`
+			} `json:"index"`
+		} `json:"settings"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return errors.Wrap(err, "failed to decode IndicesGetSettings response body")
+	}
+
+	type createdIndex struct {
+		name      string
+		createdAt time.Time
+	}
+	var indices []createdIndex
+	for name, r := range result {
+		ms, err := strconv.ParseInt(r.Settings.Index.CreationDate, 10, 64)
+		if err != nil {
+			continue
+		}
+		indices = append(indices, createdIndex{name: name, createdAt: time.Unix(0, ms*int64(time.Millisecond))})
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].createdAt.Before(indices[j].createdAt) })
+
+	var expired []string
+	for i := 0; i+1 < len(indices); i++ {
+		name := indices[i].name
+		if write, aliased := current[name]; write || (!aliased && !isPreviousVersionIndex(alias, name)) {
+			continue
+		}
+		if time.Since(indices[i+1].createdAt) > retention {
+			expired = append(expired, name)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	resp, err = (esapi.IndicesDeleteRequest{
+		Index:         expired,
+		MasterTimeout: time.Second * 5,
+		Timeout:       time.Second * 5,
+	}).Do(ctx, eim.esCli)
+
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to call IndicesDelete, resp: %+v", resp))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.IsError() {
+		return errors.Errorf("IndicesDelete return error code, resp: %+v", resp)
+	}
+	log.Printf("deleted %s indices passed retention period, indices: %v", alias, expired)
+	return nil
+}
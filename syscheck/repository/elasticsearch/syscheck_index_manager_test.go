@@ -0,0 +1,181 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeIndexManagerConfig is esIndexManagerConfig having retention period
+type fakeIndexManagerConfig struct {
+	retention time.Duration
+}
+
+func (c fakeIndexManagerConfig) IndexName() string                   { return "sms-system-check" }
+func (c fakeIndexManagerConfig) IndexShardNum() int                  { return 1 }
+func (c fakeIndexManagerConfig) IndexReplicaNum() int                { return 0 }
+func (c fakeIndexManagerConfig) IndexRolloverPeriod() time.Duration  { return time.Hour * 24 }
+func (c fakeIndexManagerConfig) IndexRetentionPeriod() time.Duration { return c.retention }
+
+// standInIndex is index in elasticsearch stand-in, created before age & pointed by alias if aliased
+type standInIndex struct {
+	age     time.Duration
+	aliased bool
+	write   bool
+}
+
+// indexStandIn return handler answering alias & creation date of indices and recording deleted indices
+func indexStandIn(alias string, indices map[string]standInIndex, mutex *sync.Mutex, deleted *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.Trim(r.URL.Path, "/")
+
+		switch {
+		case r.Method == http.MethodGet && path == "_alias/"+alias:
+			result := map[string]interface{}{}
+			for name, index := range indices {
+				if index.aliased {
+					result[name] = map[string]interface{}{"aliases": map[string]interface{}{alias: map[string]bool{"is_write_index": index.write}}}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/_settings/index.creation_date"):
+			result := map[string]interface{}{}
+			for _, name := range matchIndices(indices, strings.Split(strings.Split(path, "/")[0], ",")) {
+				createdAt := time.Now().Add(-indices[name].age).UnixNano() / int64(time.Millisecond)
+				result[name] = map[string]interface{}{"settings": map[string]interface{}{"index": map[string]string{"creation_date": fmt.Sprint(createdAt)}}}
+			}
+			_ = json.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodDelete:
+			mutex.Lock()
+			*deleted = append(*deleted, strings.Split(path, ",")...)
+			mutex.Unlock()
+			_, _ = fmt.Fprint(w, `{"acknowledged": true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+// matchIndices return names of indices in stand-in matched with any of patterns, which can have wildcard
+func matchIndices(indices map[string]standInIndex, patterns []string) (names []string) {
+	for name := range indices {
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return
+}
+
+func TestDeleteExpired(t *testing.T) {
+	const day = time.Hour * 24
+	for _, tc := range []struct {
+		name      string
+		retention time.Duration
+		indices   map[string]standInIndex
+		deleted   []string
+	}{
+		{
+			name:      "index rolled over before retention period is deleted",
+			retention: day * 7,
+			indices: map[string]standInIndex{
+				"sms-system-check-v1-000001": {age: day * 30, aliased: true},
+				"sms-system-check-v1-000002": {age: day * 20, aliased: true},
+				"sms-system-check-v1-000003": {age: day * 5, aliased: true},
+				"sms-system-check-v1-000004": {age: day, aliased: true, write: true},
+			},
+			deleted: []string{"sms-system-check-v1-000001"},
+		}, {
+			name:      "index rolled over in retention period is kept",
+			retention: day * 7,
+			indices: map[string]standInIndex{
+				"sms-system-check-v1-000001": {age: day * 10, aliased: true},
+				"sms-system-check-v1-000002": {age: day * 6, aliased: true, write: true},
+			},
+			deleted: nil,
+		}, {
+			name:      "write index is not deleted even if next index is older than retention period",
+			retention: day * 7,
+			indices: map[string]standInIndex{
+				"sms-system-check-v1-000001": {age: day * 40, aliased: true},
+				"sms-system-check-v1-000002": {age: day * 30, aliased: true, write: true},
+				"sms-system-check-v1-000003": {age: day * 20, aliased: true},
+			},
+			deleted: []string{"sms-system-check-v1-000001"},
+		}, {
+			name:      "index not behind alias & not of previous schema version is not deleted",
+			retention: day * 7,
+			indices: map[string]standInIndex{
+				"sms-system-check":           {age: day * 70},
+				"sms-system-check-legacy":    {age: day * 60},
+				"sms-system-check-v3-000001": {age: day * 60},
+				"sms-system-check-vx-000001": {age: day * 60},
+				"sms-system-check-v1-000001": {age: day * 50, aliased: true},
+				"sms-system-check-v1-000002": {age: day * 40, aliased: true},
+				"sms-system-check-v1-000003": {age: day * 30, aliased: true},
+				"sms-system-check-v1-000004": {age: day, aliased: true, write: true},
+			},
+			deleted: []string{"sms-system-check-v1-000001", "sms-system-check-v1-000002"},
+		}, {
+			name:      "index of previous schema version is deleted by creation date of its successor",
+			retention: day * 7,
+			indices: map[string]standInIndex{
+				"sms-system-check-v1-000001": {age: day * 60},
+				"sms-system-check-v1-000002": {age: day * 50},
+				"sms-system-check-v2-000001": {age: day * 5, aliased: true, write: true},
+			},
+			deleted: []string{"sms-system-check-v1-000001"},
+		}, {
+			name:      "index of previous schema version is deleted with only write index behind alias",
+			retention: day * 7,
+			indices: map[string]standInIndex{
+				"sms-system-check-v1-000001": {age: day * 60},
+				"sms-system-check-v2-000001": {age: day * 30, aliased: true, write: true},
+			},
+			deleted: []string{"sms-system-check-v1-000001"},
+		}, {
+			name:      "only write index is not deleted",
+			retention: day * 7,
+			indices: map[string]standInIndex{
+				"sms-system-check-v1-000001": {age: day * 30, aliased: true, write: true},
+			},
+			deleted: nil,
+		}, {
+			name:      "nothing is deleted without retention period",
+			retention: 0,
+			indices: map[string]standInIndex{
+				"sms-system-check-v1-000001": {age: day * 30, aliased: true},
+				"sms-system-check-v1-000002": {age: day * 20, aliased: true, write: true},
+			},
+			deleted: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			var deleted []string
+			cfg := fakeIndexManagerConfig{retention: tc.retention}
+			cli := newTestClient(t, indexStandIn(cfg.IndexName(), tc.indices, &mutex, &deleted))
+
+			if err := NewESIndexManager(cfg, cli).deleteExpired(context.Background()); err != nil {
+				t.Fatalf("expired indices should be deleted, err: %v", err)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			sort.Strings(deleted)
+			if fmt.Sprint(deleted) != fmt.Sprint(tc.deleted) {
+				t.Errorf("deleted indices should be %v, got: %v", tc.deleted, deleted)
+			}
+		})
+	}
+}
@@ -5,12 +5,8 @@
 package elasticsearch
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/pkg/errors"
 	"log"
 	"time"
@@ -29,8 +25,11 @@ type esMemoryCheckHistoryRepository struct {
 	// esCli is elasticsearch client connection injected from the outside package
 	esCli *elasticsearch.Client
 
-	// bodyWriter is implementation of reqBodyWriter interface to write []byte for request body
-	bodyWriter reqBodyWriter
+	// bulkIndexer is used for indexing history document with bulk API, injected from the outside package
+	bulkIndexer bulkIndexer
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to request body
+	docEncoder docEncoder
 }
 
 // esMemoryCheckHistoryRepoConfig is the config for memory check history repository using elasticsearch
@@ -39,12 +38,26 @@ type esMemoryCheckHistoryRepoConfig interface {
 	esRepositoryComponentConfig
 }
 
+// memoryCheckMappingProperties is mapping properties about fields of MemoryCheckHistory, merged into index mapping
+// capacity & memory usage are mapped as number of bytes, with human readable companion formatted with unit (Ex, 3.00GB)
+var memoryCheckMappingProperties = map[string]interface{}{
+	"total_usage_memory":            longField,
+	"total_usage_memory_human":      keywordField,
+	"docker_usage_memory":           longField,
+	"docker_usage_memory_human":     keywordField,
+	"temporary_free_memory":         longField,
+	"temporary_free_memory_human":   keywordField,
+	"most_memory_consume_container": keywordField,
+	"removed_container":             keywordField,
+}
+
 // NewESMemoryCheckHistoryRepository return new object that implement MemoryCheckHistoryRepository interface
-func NewESMemoryCheckHistoryRepository(cfg esMemoryCheckHistoryRepoConfig, cli *elasticsearch.Client, w reqBodyWriter) domain.MemoryCheckHistoryRepository {
+func NewESMemoryCheckHistoryRepository(cfg esMemoryCheckHistoryRepoConfig, cli *elasticsearch.Client, bi bulkIndexer, e docEncoder) domain.MemoryCheckHistoryRepository {
 	repo := &esMemoryCheckHistoryRepository{
-		myCfg:      cfg,
-		esCli:      cli,
-		bodyWriter: w,
+		myCfg:       cfg,
+		esCli:       cli,
+		bulkIndexer: bi,
+		docEncoder:  e,
 	}
 
 	if err := repo.Migrate(); err != nil {
@@ -56,39 +69,76 @@ func NewESMemoryCheckHistoryRepository(cfg esMemoryCheckHistoryRepoConfig, cli *
 
 // Implement Migrate method of MemoryCheckHistoryRepository interface
 func (emr *esMemoryCheckHistoryRepository) Migrate() error {
-	return emr.esMigrator.Migrate(emr.myCfg, emr.esCli, emr.bodyWriter)
+	return emr.esMigrator.Migrate(emr.myCfg, emr.esCli, emr.docEncoder)
 }
 
 // Implement Store method of MemoryCheckHistoryRepository interface
 func (emr *esMemoryCheckHistoryRepository) Store(history *domain.MemoryCheckHistory) (b []byte, err error) {
-	body, _ := json.Marshal(history.DottedMapWithPrefix(""))
-	if _, err = emr.bodyWriter.Write(body); err != nil {
-		err = errors.Wrap(err, "failed to write map to body writer")
+	body, err := emr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
 		return
 	}
 
-	buf := &bytes.Buffer{}
-	if _, err = emr.bodyWriter.WriteTo(buf); err != nil {
-		err = errors.Wrap(err, "failed to body writer WriteTo method")
+	// history document is buffered & indexed later with bulk API, and spooled in disk if elasticsearch is unavailable
+	if err = emr.bulkIndexer.Index(emr.myCfg.IndexName(), history.UUID, body); err != nil {
+		err = errors.Wrap(err, "failed to buffer history document in bulk indexer")
+	}
+	return
+}
+
+// Implement UpdateAlarmResult method of MemoryCheckHistoryRepository interface
+func (emr *esMemoryCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return updateAlarmResult(emr.esCli, emr.bulkIndexer, emr.myCfg.IndexName(), uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of MemoryCheckHistoryRepository interface
+func (emr *esMemoryCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.MemoryCheckHistory, err error) {
+	sources, err := searchByTimeRange(ctx, emr.esCli, emr.myCfg.IndexName(), "MemoryCheck", start, end)
+	if err != nil {
 		return
 	}
+	histories = memoryCheckHistories(sources)
+	return
+}
 
-	resp, err := (esapi.IndexRequest{
-		Index:        emr.myCfg.IndexName(),
-		Body:         bytes.NewReader(buf.Bytes()),
-		Timeout:      time.Second * 5,
-	}).Do(context.Background(), emr.esCli)
+// Implement GetByUUID method of MemoryCheckHistoryRepository interface
+func (emr *esMemoryCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.MemoryCheckHistory, err error) {
+	sources, err := searchLatest(ctx, emr.esCli, emr.myCfg.IndexName(), 1, typeFilter("MemoryCheck"), idFilter(uuid))
+	if err != nil || len(sources) == 0 {
+		return
+	}
+	history = memoryCheckHistories(sources)[0]
+	return
+}
 
+// Implement FindByProcessLevel method of MemoryCheckHistoryRepository interface
+func (emr *esMemoryCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.MemoryCheckHistory, err error) {
+	sources, err := searchByProcessLevel(ctx, emr.esCli, emr.myCfg.IndexName(), "MemoryCheck", level, start, end)
 	if err != nil {
-		err = errors.Wrap(err, fmt.Sprintf("failed to call IndexRequest, resp: %+v", resp))
 		return
-	} else if resp.IsError() {
-		err = errors.Errorf("IndexRequest return error code, resp: %+v", resp)
+	}
+	histories = memoryCheckHistories(sources)
+	return
+}
+
+// Implement FindLatest method of MemoryCheckHistoryRepository interface
+func (emr *esMemoryCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.MemoryCheckHistory, err error) {
+	sources, err := searchLatest(ctx, emr.esCli, emr.myCfg.IndexName(), n, typeFilter("MemoryCheck"))
+	if err != nil {
 		return
 	}
+	histories = memoryCheckHistories(sources)
+	return
+}
 
-	result := map[string]interface{}{}
-	_ = json.NewDecoder(resp.Body).Decode(&result)
-	b, _ = json.Marshal(result)
+// memoryCheckHistories return MemoryCheckHistory slice restored from sources of history documents
+func memoryCheckHistories(sources []map[string]interface{}) (histories []*domain.MemoryCheckHistory) {
+	histories = make([]*domain.MemoryCheckHistory, 0, len(sources))
+	for _, source := range sources {
+		history := new(domain.MemoryCheckHistory)
+		history.FillFromDottedMap(source)
+		histories = append(histories, history)
+	}
 	return
 }
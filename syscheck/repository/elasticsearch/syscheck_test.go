@@ -0,0 +1,221 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// dottedMapper is interface implemented in every history type of syscheck domain
+type dottedMapper interface {
+	FillPrivateComponent()
+	DottedMapWithPrefix(prefix string) map[string]interface{}
+}
+
+func TestIndexMappingCoversHistoryFields(t *testing.T) {
+	histories := map[string]dottedMapper{
+		"CPUCheck":    &domain.CPUCheckHistory{},
+		"DiskCheck":   &domain.DiskCheckHistory{},
+		"MemoryCheck": &domain.MemoryCheckHistory{},
+	}
+
+	properties := indexMapping()["properties"].(map[string]interface{})
+	for name, history := range histories {
+		history.FillPrivateComponent()
+		for key := range history.DottedMapWithPrefix("") {
+			property := map[string]interface{}{"properties": properties}
+			for _, step := range strings.Split(key, ".") {
+				nested, _ := property["properties"].(map[string]interface{})
+				if property, _ = nested[step].(map[string]interface{}); property == nil {
+					break
+				}
+			}
+			if property == nil {
+				t.Errorf("field of %s is not defined in index mapping, field: %s", name, key)
+			}
+		}
+	}
+}
+
+func TestIndexMappingIsNotDynamic(t *testing.T) {
+	mapping := indexMapping()
+	if mapping["dynamic"] != false {
+		t.Errorf("dynamic of index mapping should be false, actual: %v", mapping["dynamic"])
+	}
+	if _, ok := mapping["dynamic_templates"]; ok {
+		t.Error("dynamic templates are never applied in index mapping which is not dynamic")
+	}
+}
+
+// bulkRequest is request buffered in recordingBulkIndexer
+type bulkRequest struct {
+	index, id string
+	doc       map[string]interface{}
+}
+
+// recordingBulkIndexer is bulkIndexer recording buffered update requests
+type recordingBulkIndexer struct {
+	updates []bulkRequest
+}
+
+func (bi *recordingBulkIndexer) Index(string, string, []byte) error { return nil }
+
+func (bi *recordingBulkIndexer) Update(index, id string, doc []byte) error {
+	m := map[string]interface{}{}
+	_ = json.Unmarshal(doc, &m)
+	bi.updates = append(bi.updates, bulkRequest{index: index, id: id, doc: m})
+	return nil
+}
+
+// newTestClient return elasticsearch client sending request to stand-in server handled with handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *elasticsearch.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cli, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("elasticsearch client should be created, err: %v", err)
+	}
+	return cli
+}
+
+func TestUpdateAlarmResult(t *testing.T) {
+	const alias = "sms-system-check"
+	for _, tc := range []struct {
+		name      string
+		search    http.HandlerFunc
+		text      string
+		alarmErr  error
+		wantIndex string
+		wantDoc   map[string]interface{}
+	}{
+		{
+			name: "document in rolled over index",
+			search: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"hits": {"hits": [{"_index": "sms-system-check-v1-000001", "_id": "uuid-1"}]}}`))
+			},
+			text:      "cpu check error",
+			wantIndex: "sms-system-check-v1-000001",
+			wantDoc:   map[string]interface{}{"alerted": true, "alarm_error": nil, "alarm_text": "cpu check error"},
+		}, {
+			name: "document not indexed yet",
+			search: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"hits": {"hits": []}}`))
+			},
+			alarmErr:  errors.New("alert is still queued for retry to 1 notifiers"),
+			wantIndex: alias,
+			wantDoc:   map[string]interface{}{"alerted": true, "alarm_error": "alert is still queued for retry to 1 notifiers"},
+		}, {
+			name: "elasticsearch is unavailable",
+			search: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			text:      "cpu check error",
+			wantIndex: alias,
+			wantDoc:   map[string]interface{}{"alerted": true, "alarm_error": nil, "alarm_text": "cpu check error"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path != "/"+alias+"/_search" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				tc.search(w, r)
+			})
+			bi := &recordingBulkIndexer{}
+
+			if err := updateAlarmResult(cli, bi, alias, "uuid-1", time.Now(), tc.text, tc.alarmErr); err != nil {
+				t.Fatalf("alarm result update should be buffered, err: %v", err)
+			}
+			if len(bi.updates) != 1 {
+				t.Fatalf("one update should be buffered, got: %v", bi.updates)
+			}
+
+			update := bi.updates[0]
+			if update.index != tc.wantIndex || update.id != "uuid-1" {
+				t.Errorf("update should be sent to document uuid-1 in %s, got: %s in %s", tc.wantIndex, update.id, update.index)
+			}
+			for key, value := range tc.wantDoc {
+				if update.doc[key] != value {
+					t.Errorf("%s of update should be %v, got: %v", key, value, update.doc[key])
+				}
+			}
+			if _, ok := update.doc["alarm_time"]; ok != (tc.text != "") {
+				t.Errorf("alarm time should be updated only with alarm text, got: %v", update.doc)
+			}
+		})
+	}
+}
+
+func TestMoveAliasBacksUpLegacyIndex(t *testing.T) {
+	const alias, index = "sms-system-check", "sms-system-check-v1-000001"
+
+	for _, tc := range []struct {
+		name         string
+		backupExists bool
+		want         []string // requests sent to stand-in in order
+	}{
+		{
+			name: "legacy index is cloned into backup before removed",
+			want: []string{
+				"HEAD /sms-system-check",
+				"HEAD /sms-system-check-legacy",
+				"PUT /sms-system-check/_settings",
+				"PUT /sms-system-check/_clone/sms-system-check-legacy",
+				"POST /_reindex",
+				"POST /_aliases",
+			},
+		}, {
+			name:         "backup already cloned is not cloned again",
+			backupExists: true,
+			want: []string{
+				"HEAD /sms-system-check",
+				"HEAD /sms-system-check-legacy",
+				"POST /_reindex",
+				"POST /_aliases",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []string
+			var actions struct {
+				Actions []map[string]map[string]interface{} `json:"actions"`
+			}
+			cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/sms-system-check-legacy" && !tc.backupExists:
+					w.WriteHeader(http.StatusNotFound)
+				case r.URL.Path == "/_reindex":
+					_, _ = w.Write([]byte(`{"failures": []}`))
+				case r.URL.Path == "/_aliases":
+					_ = json.NewDecoder(r.Body).Decode(&actions)
+					_, _ = w.Write([]byte(`{"acknowledged": true}`))
+				default:
+					_, _ = w.Write([]byte(`{"acknowledged": true}`))
+				}
+			})
+
+			if err := (esRepositoryMigrator{}).moveAlias(cli, alias, index, map[string]bool{}); err != nil {
+				t.Fatalf("alias should be moved, err: %v", err)
+			}
+			if strings.Join(requests, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("requests should be\n%s\ngot:\n%s", strings.Join(tc.want, "\n"), strings.Join(requests, "\n"))
+			}
+			if len(actions.Actions) != 2 || actions.Actions[0]["remove_index"]["index"] != alias || actions.Actions[1]["add"]["alias"] != alias {
+				t.Errorf("legacy index should be replaced with alias, got: %v", actions.Actions)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// syscheck_cpu_repo.go is file that define implement cpu history repository using local file system
+// this cpu repository struct use historyFile struct in ./syscheck.go file
+
+package file
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fileCPUCheckHistoryRepository is to handle CPUCheckHistory model using JSON-lines file as data store
+type fileCPUCheckHistoryRepository struct {
+	// myCfg is used for get cpu check history repository config about file
+	myCfg fileCPUCheckHistoryRepoConfig
+
+	// historyFile is used for appending & reading cpu check history document in JSON-lines file
+	historyFile *historyFile
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to document
+	docEncoder docEncoder
+}
+
+// fileCPUCheckHistoryRepoConfig is the config for cpu check history repository using file
+type fileCPUCheckHistoryRepoConfig interface {
+	// get common method from embedding fileRepositoryComponentConfig
+	fileRepositoryComponentConfig
+}
+
+// NewFileCPUCheckHistoryRepository return new object that implement CPUCheckHistoryRepository interface
+func NewFileCPUCheckHistoryRepository(cfg fileCPUCheckHistoryRepoConfig, e docEncoder) domain.CPUCheckHistoryRepository {
+	repo := &fileCPUCheckHistoryRepository{
+		myCfg:       cfg,
+		historyFile: newHistoryFile(cfg, "cpu"),
+		docEncoder:  e,
+	}
+
+	if err := repo.Migrate(); err != nil {
+		log.Fatal(errors.Wrap(err, "could not migrate repository").Error())
+	}
+
+	return repo
+}
+
+// Implement Migrate method of CPUCheckHistoryRepository interface
+func (fcr *fileCPUCheckHistoryRepository) Migrate() error {
+	return fcr.historyFile.migrate()
+}
+
+// Implement Store method of CPUCheckHistoryRepository interface
+func (fcr *fileCPUCheckHistoryRepository) Store(history *domain.CPUCheckHistory) (b []byte, err error) {
+	doc, err := fcr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
+		return
+	}
+
+	if err = fcr.historyFile.append(doc); err != nil {
+		err = errors.Wrap(err, "failed to append document in history file")
+		return
+	}
+
+	b = fcr.historyFile.storeResult(history.UUID)
+	return
+}
+
+// Implement UpdateAlarmResult method of CPUCheckHistoryRepository interface
+func (fcr *fileCPUCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return fcr.historyFile.updateAlarmResult(uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of CPUCheckHistoryRepository interface
+func (fcr *fileCPUCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.CPUCheckHistory, err error) {
+	docs, err := fcr.historyFile.findByTimeRange(start, end)
+	if err != nil {
+		return
+	}
+	histories = cpuCheckHistories(docs)
+	return
+}
+
+// Implement GetByUUID method of CPUCheckHistoryRepository interface
+func (fcr *fileCPUCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.CPUCheckHistory, err error) {
+	doc, err := fcr.historyFile.getByUUID(uuid)
+	if err != nil || doc == nil {
+		return
+	}
+	history = new(domain.CPUCheckHistory)
+	history.FillFromDottedMap(doc)
+	return
+}
+
+// Implement FindByProcessLevel method of CPUCheckHistoryRepository interface
+func (fcr *fileCPUCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.CPUCheckHistory, err error) {
+	docs, err := fcr.historyFile.findByProcessLevel(level, start, end)
+	if err != nil {
+		return
+	}
+	histories = cpuCheckHistories(docs)
+	return
+}
+
+// Implement FindLatest method of CPUCheckHistoryRepository interface
+func (fcr *fileCPUCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.CPUCheckHistory, err error) {
+	docs, err := fcr.historyFile.findLatest(n)
+	if err != nil {
+		return
+	}
+	histories = cpuCheckHistories(docs)
+	return
+}
+
+// cpuCheckHistories return CPUCheckHistory slice restored from history documents
+func cpuCheckHistories(docs []map[string]interface{}) (histories []*domain.CPUCheckHistory) {
+	histories = make([]*domain.CPUCheckHistory, 0, len(docs))
+	for _, doc := range docs {
+		history := new(domain.CPUCheckHistory)
+		history.FillFromDottedMap(doc)
+		histories = append(histories, history)
+	}
+	return
+}
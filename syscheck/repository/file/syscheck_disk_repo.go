@@ -0,0 +1,125 @@
+// syscheck_disk_repo.go is file that define implement disk history repository using local file system
+// this disk repository struct use historyFile struct in ./syscheck.go file
+
+package file
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fileDiskCheckHistoryRepository is to handle DiskCheckHistory model using JSON-lines file as data store
+type fileDiskCheckHistoryRepository struct {
+	// myCfg is used for get disk check history repository config about file
+	myCfg fileDiskCheckHistoryRepoConfig
+
+	// historyFile is used for appending & reading disk check history document in JSON-lines file
+	historyFile *historyFile
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to document
+	docEncoder docEncoder
+}
+
+// fileDiskCheckHistoryRepoConfig is the config for disk check history repository using file
+type fileDiskCheckHistoryRepoConfig interface {
+	// get common method from embedding fileRepositoryComponentConfig
+	fileRepositoryComponentConfig
+}
+
+// NewFileDiskCheckHistoryRepository return new object that implement DiskCheckHistoryRepository interface
+func NewFileDiskCheckHistoryRepository(cfg fileDiskCheckHistoryRepoConfig, e docEncoder) domain.DiskCheckHistoryRepository {
+	repo := &fileDiskCheckHistoryRepository{
+		myCfg:       cfg,
+		historyFile: newHistoryFile(cfg, "disk"),
+		docEncoder:  e,
+	}
+
+	if err := repo.Migrate(); err != nil {
+		log.Fatal(errors.Wrap(err, "could not migrate repository").Error())
+	}
+
+	return repo
+}
+
+// Implement Migrate method of DiskCheckHistoryRepository interface
+func (fdr *fileDiskCheckHistoryRepository) Migrate() error {
+	return fdr.historyFile.migrate()
+}
+
+// Implement Store method of DiskCheckHistoryRepository interface
+func (fdr *fileDiskCheckHistoryRepository) Store(history *domain.DiskCheckHistory) (b []byte, err error) {
+	doc, err := fdr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
+		return
+	}
+
+	if err = fdr.historyFile.append(doc); err != nil {
+		err = errors.Wrap(err, "failed to append document in history file")
+		return
+	}
+
+	b = fdr.historyFile.storeResult(history.UUID)
+	return
+}
+
+// Implement UpdateAlarmResult method of DiskCheckHistoryRepository interface
+func (fdr *fileDiskCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return fdr.historyFile.updateAlarmResult(uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of DiskCheckHistoryRepository interface
+func (fdr *fileDiskCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.DiskCheckHistory, err error) {
+	docs, err := fdr.historyFile.findByTimeRange(start, end)
+	if err != nil {
+		return
+	}
+	histories = diskCheckHistories(docs)
+	return
+}
+
+// Implement GetByUUID method of DiskCheckHistoryRepository interface
+func (fdr *fileDiskCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.DiskCheckHistory, err error) {
+	doc, err := fdr.historyFile.getByUUID(uuid)
+	if err != nil || doc == nil {
+		return
+	}
+	history = new(domain.DiskCheckHistory)
+	history.FillFromDottedMap(doc)
+	return
+}
+
+// Implement FindByProcessLevel method of DiskCheckHistoryRepository interface
+func (fdr *fileDiskCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.DiskCheckHistory, err error) {
+	docs, err := fdr.historyFile.findByProcessLevel(level, start, end)
+	if err != nil {
+		return
+	}
+	histories = diskCheckHistories(docs)
+	return
+}
+
+// Implement FindLatest method of DiskCheckHistoryRepository interface
+func (fdr *fileDiskCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.DiskCheckHistory, err error) {
+	docs, err := fdr.historyFile.findLatest(n)
+	if err != nil {
+		return
+	}
+	histories = diskCheckHistories(docs)
+	return
+}
+
+// diskCheckHistories return DiskCheckHistory slice restored from history documents
+func diskCheckHistories(docs []map[string]interface{}) (histories []*domain.DiskCheckHistory) {
+	histories = make([]*domain.DiskCheckHistory, 0, len(docs))
+	for _, doc := range docs {
+		history := new(domain.DiskCheckHistory)
+		history.FillFromDottedMap(doc)
+		histories = append(histories, history)
+	}
+	return
+}
@@ -0,0 +1,125 @@
+// syscheck_memory_repo.go is file that define implement memory history repository using local file system
+// this memory repository struct use historyFile struct in ./syscheck.go file
+
+package file
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"log"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fileMemoryCheckHistoryRepository is to handle MemoryCheckHistory model using JSON-lines file as data store
+type fileMemoryCheckHistoryRepository struct {
+	// myCfg is used for get memory check history repository config about file
+	myCfg fileMemoryCheckHistoryRepoConfig
+
+	// historyFile is used for appending & reading memory check history document in JSON-lines file
+	historyFile *historyFile
+
+	// docEncoder is implementation of docEncoder interface to encode dotted map of history to document
+	docEncoder docEncoder
+}
+
+// fileMemoryCheckHistoryRepoConfig is the config for memory check history repository using file
+type fileMemoryCheckHistoryRepoConfig interface {
+	// get common method from embedding fileRepositoryComponentConfig
+	fileRepositoryComponentConfig
+}
+
+// NewFileMemoryCheckHistoryRepository return new object that implement MemoryCheckHistoryRepository interface
+func NewFileMemoryCheckHistoryRepository(cfg fileMemoryCheckHistoryRepoConfig, e docEncoder) domain.MemoryCheckHistoryRepository {
+	repo := &fileMemoryCheckHistoryRepository{
+		myCfg:       cfg,
+		historyFile: newHistoryFile(cfg, "memory"),
+		docEncoder:  e,
+	}
+
+	if err := repo.Migrate(); err != nil {
+		log.Fatal(errors.Wrap(err, "could not migrate repository").Error())
+	}
+
+	return repo
+}
+
+// Implement Migrate method of MemoryCheckHistoryRepository interface
+func (fmr *fileMemoryCheckHistoryRepository) Migrate() error {
+	return fmr.historyFile.migrate()
+}
+
+// Implement Store method of MemoryCheckHistoryRepository interface
+func (fmr *fileMemoryCheckHistoryRepository) Store(history *domain.MemoryCheckHistory) (b []byte, err error) {
+	doc, err := fmr.docEncoder.Encode(history.DottedMapWithPrefix(""))
+	if err != nil {
+		err = errors.Wrap(err, "failed to encode history document")
+		return
+	}
+
+	if err = fmr.historyFile.append(doc); err != nil {
+		err = errors.Wrap(err, "failed to append document in history file")
+		return
+	}
+
+	b = fmr.historyFile.storeResult(history.UUID)
+	return
+}
+
+// Implement UpdateAlarmResult method of MemoryCheckHistoryRepository interface
+func (fmr *fileMemoryCheckHistoryRepository) UpdateAlarmResult(uuid string, t time.Time, text string, err error) error {
+	return fmr.historyFile.updateAlarmResult(uuid, t, text, err)
+}
+
+// Implement FindByTimeRange method of MemoryCheckHistoryRepository interface
+func (fmr *fileMemoryCheckHistoryRepository) FindByTimeRange(ctx context.Context, start, end time.Time) (histories []*domain.MemoryCheckHistory, err error) {
+	docs, err := fmr.historyFile.findByTimeRange(start, end)
+	if err != nil {
+		return
+	}
+	histories = memoryCheckHistories(docs)
+	return
+}
+
+// Implement GetByUUID method of MemoryCheckHistoryRepository interface
+func (fmr *fileMemoryCheckHistoryRepository) GetByUUID(ctx context.Context, uuid string) (history *domain.MemoryCheckHistory, err error) {
+	doc, err := fmr.historyFile.getByUUID(uuid)
+	if err != nil || doc == nil {
+		return
+	}
+	history = new(domain.MemoryCheckHistory)
+	history.FillFromDottedMap(doc)
+	return
+}
+
+// Implement FindByProcessLevel method of MemoryCheckHistoryRepository interface
+func (fmr *fileMemoryCheckHistoryRepository) FindByProcessLevel(ctx context.Context, level string, start, end time.Time) (histories []*domain.MemoryCheckHistory, err error) {
+	docs, err := fmr.historyFile.findByProcessLevel(level, start, end)
+	if err != nil {
+		return
+	}
+	histories = memoryCheckHistories(docs)
+	return
+}
+
+// Implement FindLatest method of MemoryCheckHistoryRepository interface
+func (fmr *fileMemoryCheckHistoryRepository) FindLatest(ctx context.Context, n int) (histories []*domain.MemoryCheckHistory, err error) {
+	docs, err := fmr.historyFile.findLatest(n)
+	if err != nil {
+		return
+	}
+	histories = memoryCheckHistories(docs)
+	return
+}
+
+// memoryCheckHistories return MemoryCheckHistory slice restored from history documents
+func memoryCheckHistories(docs []map[string]interface{}) (histories []*domain.MemoryCheckHistory) {
+	histories = make([]*domain.MemoryCheckHistory, 0, len(docs))
+	for _, doc := range docs {
+		history := new(domain.MemoryCheckHistory)
+		history.FillFromDottedMap(doc)
+		histories = append(histories, history)
+	}
+	return
+}
@@ -0,0 +1,199 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+	"github.com/DMS-SMS/v1-health-check/json"
+)
+
+// fakeFileRepoConfig is fileCPUCheckHistoryRepoConfig storing history file in dir
+type fakeFileRepoConfig struct {
+	dir        string
+	maxSize    int64
+	maxBackups int
+}
+
+func (c fakeFileRepoConfig) IndexName() string          { return "sms-system-check" }
+func (c fakeFileRepoConfig) HistoryFileDir() string     { return c.dir }
+func (c fakeFileRepoConfig) HistoryFileMaxSize() int64  { return c.maxSize }
+func (c fakeFileRepoConfig) HistoryFileMaxBackups() int { return c.maxBackups }
+
+// storeCPUHistory store cpu check history having uuid & alarm result in repository
+func storeCPUHistory(t *testing.T, repo domain.CPUCheckHistoryRepository, uuid string) {
+	history := &domain.CPUCheckHistory{}
+	history.FillPrivateComponent()
+	history.UUID = uuid
+	history.ProcessLevel.Set("ERROR")
+	history.SetAlarmResult(time.Time{}, "", errors.New("slack is unavailable"))
+	if _, err := repo.Store(history); err != nil {
+		t.Fatalf("history should be stored, err: %v", err)
+	}
+}
+
+// alarmResultOf return alarm result fields of history having uuid in repository
+func alarmResultOf(t *testing.T, repo domain.CPUCheckHistoryRepository, uuid string) map[string]interface{} {
+	history, err := repo.GetByUUID(context.Background(), uuid)
+	if err != nil || history == nil {
+		t.Fatalf("history should be found, err: %v", err)
+	}
+	m := history.DottedMapWithPrefix("")
+	return map[string]interface{}{"alerted": m["alerted"], "alarm_text": m["alarm_text"], "alarm_error": m["alarm_error"]}
+}
+
+func TestUpdateAlarmResult(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		text      string
+		alarmErr  error
+		wantText  string
+		wantError interface{}
+	}{
+		{
+			name:      "alert sent to primary notifier",
+			text:      "cpu check error",
+			wantText:  "cpu check error",
+			wantError: nil,
+		}, {
+			name:      "alert sent to another notifier while primary one is pending",
+			alarmErr:  errors.New("alert is still queued for retry to 1 notifiers"),
+			wantText:  "",
+			wantError: "alert is still queued for retry to 1 notifiers",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := fakeFileRepoConfig{dir: t.TempDir(), maxSize: 1024 * 1024, maxBackups: 2}
+			repo := NewFileCPUCheckHistoryRepository(cfg, json.DottedMapEncoder())
+			storeCPUHistory(t, repo, "uuid-1")
+			storeCPUHistory(t, repo, "uuid-2")
+
+			if err := repo.UpdateAlarmResult("uuid-1", time.Now(), tc.text, tc.alarmErr); err != nil {
+				t.Fatalf("alarm result should be updated, err: %v", err)
+			}
+
+			got := alarmResultOf(t, repo, "uuid-1")
+			if got["alerted"] != true || got["alarm_text"] != tc.wantText || got["alarm_error"] != tc.wantError {
+				t.Errorf("alarm result should be updated to text %q & error %v, got: %v", tc.wantText, tc.wantError, got)
+			}
+			if other := alarmResultOf(t, repo, "uuid-2"); other["alarm_error"] != "slack is unavailable" {
+				t.Errorf("alarm result of another history should not be updated, got: %v", other)
+			}
+		})
+	}
+}
+
+func TestUpdateAlarmResultOfHistoryNotExist(t *testing.T) {
+	cfg := fakeFileRepoConfig{dir: t.TempDir(), maxSize: 1024 * 1024, maxBackups: 2}
+	repo := NewFileCPUCheckHistoryRepository(cfg, json.DottedMapEncoder())
+	storeCPUHistory(t, repo, "uuid-1")
+
+	if err := repo.UpdateAlarmResult("not-exist", time.Now(), "text", nil); err == nil {
+		t.Error("updating alarm result of history not exist should return error")
+	}
+}
+
+// historyFileLines return number of lines in history file & rotated files having number from 0 to n (-1 if not exist)
+func historyFileLines(t *testing.T, cfg fakeFileRepoConfig, n int) (counts []int) {
+	path := filepath.Join(cfg.dir, cfg.IndexName()+"-cpu.jsonl")
+	for i := 0; i <= n; i++ {
+		p := path
+		if i != 0 {
+			p = fmt.Sprintf("%s.%d", path, i)
+		}
+		b, err := ioutil.ReadFile(p)
+		if os.IsNotExist(err) {
+			counts = append(counts, -1)
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, c := range b {
+			if c == '\n' {
+				count++
+			}
+		}
+		counts = append(counts, count)
+	}
+	return
+}
+
+func TestHistoryFileRotation(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		stored     int
+		maxBackups int
+		lines      []int // lines in history file & rotated files from .1 to .3 (-1 if not exist)
+		kept       int   // number of histories found in repository
+	}{
+		{name: "not rotated under max size", stored: 2, maxBackups: 2, lines: []int{2, -1, -1, -1}, kept: 2},
+		{name: "rotated when exceeding max size", stored: 3, maxBackups: 2, lines: []int{1, 2, -1, -1}, kept: 3},
+		{name: "rotated files are shifted", stored: 5, maxBackups: 2, lines: []int{1, 2, 2, -1}, kept: 5},
+		{name: "the oldest file over max backups is removed", stored: 7, maxBackups: 2, lines: []int{1, 2, 2, -1}, kept: 5},
+		{name: "file is removed without backups", stored: 3, maxBackups: 0, lines: []int{1, -1, -1, -1}, kept: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// max size is set to keep two history documents in a file, with margin for length of timestamp
+			cfg := fakeFileRepoConfig{dir: t.TempDir(), maxSize: 1024 * 1024, maxBackups: tc.maxBackups}
+			repo := NewFileCPUCheckHistoryRepository(cfg, json.DottedMapEncoder())
+			storeCPUHistory(t, repo, "uuid-0")
+			info, err := os.Stat(filepath.Join(cfg.dir, cfg.IndexName()+"-cpu.jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = os.Remove(filepath.Join(cfg.dir, cfg.IndexName()+"-cpu.jsonl"))
+
+			cfg.maxSize = info.Size()*2 + 16
+			repo = NewFileCPUCheckHistoryRepository(cfg, json.DottedMapEncoder())
+			for i := 0; i < tc.stored; i++ {
+				storeCPUHistory(t, repo, fmt.Sprintf("uuid-%d", i))
+			}
+
+			if lines := historyFileLines(t, cfg, 3); fmt.Sprint(lines) != fmt.Sprint(tc.lines) {
+				t.Errorf("lines of history files should be %v, got: %v", tc.lines, lines)
+			}
+			latest, err := repo.FindLatest(context.Background(), 100)
+			if err != nil {
+				t.Fatalf("latest histories should be found, err: %v", err)
+			}
+			if len(latest) != tc.kept || latest[0].UUID != fmt.Sprintf("uuid-%d", tc.stored-1) {
+				t.Errorf("%d histories should be kept with newest first, got: %d", tc.kept, len(latest))
+			}
+		})
+	}
+}
+
+func TestUpdateAlarmResultInRotatedFile(t *testing.T) {
+	cfg := fakeFileRepoConfig{dir: t.TempDir(), maxSize: 1, maxBackups: 3}
+	repo := NewFileCPUCheckHistoryRepository(cfg, json.DottedMapEncoder())
+
+	// every history is stored in its own file, as max size is smaller than one document
+	for i := 0; i < 3; i++ {
+		storeCPUHistory(t, repo, fmt.Sprintf("uuid-%d", i))
+	}
+	if lines := historyFileLines(t, cfg, 3); fmt.Sprint(lines) != fmt.Sprint([]int{1, 1, 1, -1}) {
+		t.Fatalf("every history should be stored in its own file, got: %v", lines)
+	}
+
+	// uuid-0 is in the oldest rotated file (.2)
+	if err := repo.UpdateAlarmResult("uuid-0", time.Now(), "cpu check error", nil); err != nil {
+		t.Fatalf("alarm result of history in rotated file should be updated, err: %v", err)
+	}
+	got := alarmResultOf(t, repo, "uuid-0")
+	if got["alerted"] != true || got["alarm_text"] != "cpu check error" || got["alarm_error"] != nil {
+		t.Errorf("alarm result of history in rotated file should be updated, got: %v", got)
+	}
+	if lines := historyFileLines(t, cfg, 3); fmt.Sprint(lines) != fmt.Sprint([]int{1, 1, 1, -1}) {
+		t.Errorf("updating alarm result should not move history between files, got: %v", lines)
+	}
+	if other := alarmResultOf(t, repo, "uuid-1"); other["alarm_error"] != "slack is unavailable" {
+		t.Errorf("alarm result of another history should not be updated, got: %v", other)
+	}
+}
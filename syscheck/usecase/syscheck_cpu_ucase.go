@@ -10,7 +10,9 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
 )
@@ -24,6 +26,24 @@ const (
 	cpuStatusUnhealthy                        // represent cpu check status is unhealthy
 )
 
+// String method return string represent cpu check status, which is used in delivery layer
+func (s cpuCheckStatus) String() string {
+	switch s {
+	case cpuStatusHealthy:
+		return "HEALTHY"
+	case cpuStatusWarning:
+		return "WARNING"
+	case cpuStatusRecovering:
+		return "RECOVERING"
+	case cpuStatusUnhealthy:
+		return "UNHEALTHY"
+	}
+	return "UNKNOWN"
+}
+
+// cpuCheckKey is key identifying cpu check in status store agency & maintenance window
+const cpuCheckKey = "syscheck.cpu"
+
 // cpuCheckUsecase implement CPUCheckUsecase interface in domain and used in delivery layer
 type cpuCheckUsecase struct {
 	// myCfg is used for getting cpu check usecase config
@@ -32,8 +52,20 @@ type cpuCheckUsecase struct {
 	// historyRepo is used for store cpu check history and injected from outside
 	historyRepo domain.CPUCheckHistoryRepository
 
-	// slackChat is used for agent slack API about chatting
-	slackChatAgency slackChatAgency
+	// notifierAgency is used for agent notifiers delivering alert
+	notifierAgency notifierAgency
+
+	// metricAgency is used as agency about metric collector to expose numbers computed in check process
+	metricAgency metricAgency
+
+	// statusStoreAgency is used as agency about status store to keep status of state machine across restart
+	statusStoreAgency statusStoreAgency
+
+	// maintenanceAgency is used for checking if check or container is in maintenance window
+	maintenanceAgency maintenanceAgency
+
+	// escalationAgency is used for escalating incident of cpu check staying unhealthy long
+	escalationAgency escalationAgency
 
 	// cpuSysAgency is used as agency about cpu system command
 	cpuSysAgency cpuSysAgency
@@ -44,8 +76,20 @@ type cpuCheckUsecase struct {
 	// status represent current process status of cpu health check
 	status cpuCheckStatus
 
+	// statusChangedAt represent time when status field value was changed last
+	statusChangedAt time.Time
+
+	// unhealthyCycles represent how many check cycles have run since status was changed to unhealthy
+	unhealthyCycles int
+
+	// lastHistory represent cpu check history produced in last check process
+	lastHistory *domain.CPUCheckHistory
+
 	// mutex help to prevent race condition when set status field value
 	mutex sync.Mutex
+
+	// runLocker help to prevent cpu check process from running at the same time
+	runLocker checkRunLocker
 }
 
 // cpuCheckUsecaseConfig is the config getter interface for cpu check usecase
@@ -53,6 +97,9 @@ type cpuCheckUsecaseConfig interface {
 	// get common config method from embedding systemCheckUsecaseComponentConfig
 	systemCheckUsecaseComponentConfig
 
+	// CPUCheckTimeOut method returns time.Duration represent deadline of one cpu check process
+	CPUCheckTimeOut() time.Duration
+
 	// CPUWarningUsage method returns float64 represent cpu warning usage
 	CPUWarningUsage() float64
 
@@ -66,10 +113,10 @@ type cpuCheckUsecaseConfig interface {
 // cpuSysAgency is agency that agent various command about cpu system
 type cpuSysAgency interface {
 	// GetTotalSystemCPUUsage return total cpu usage as core count in system
-	GetTotalSystemCPUUsage() (usage float64, err error)
+	GetTotalSystemCPUUsage(ctx context.Context) (usage float64, err error)
 
 	// CalculateContainersCPUUsage calculate container cpu usage & return result interface implementation
-	CalculateContainersCPUUsage() (result interface {
+	CalculateContainersCPUUsage(ctx context.Context) (result interface {
 		// TotalCPUUsage return total cpu usage in docker containers
 		TotalCPUUsage() (usage float64)
 
@@ -82,34 +129,100 @@ type cpuSysAgency interface {
 func NewCPUCheckUsecase(
 	cfg cpuCheckUsecaseConfig,
 	chr domain.CPUCheckHistoryRepository,
-	sca slackChatAgency,
+	na notifierAgency,
+	ma metricAgency,
+	ssa statusStoreAgency,
+	mta maintenanceAgency,
+	eca escalationAgency,
 	csa cpuSysAgency,
 	da dockerAgency,
 ) domain.CPUCheckUseCase {
-	return &cpuCheckUsecase{
+	usecase := &cpuCheckUsecase{
 		// initialize field with parameter received from caller
-		myCfg:           cfg,
-		historyRepo:     chr,
-		slackChatAgency: sca,
-		cpuSysAgency:    csa,
-		dockerAgency:    da,
+		myCfg:             cfg,
+		historyRepo:       chr,
+		notifierAgency:    na,
+		metricAgency:      ma,
+		statusStoreAgency: ssa,
+		maintenanceAgency: mta,
+		escalationAgency:  eca,
+		cpuSysAgency:      csa,
+		dockerAgency:      da,
 
 		// initialize field with default value
-		status: cpuStatusHealthy,
-		mutex:  sync.Mutex{},
+		status:    cpuStatusHealthy,
+		mutex:     sync.Mutex{},
+		runLocker: newCheckRunLocker(),
 	}
+
+	usecase.loadStatus()
+	return usecase
 }
 
 // CheckCPU check cpu health with checkCPU method & store check history in repository
 // Implement CheckCPU method of domain.CPUCheckUseCase interface
-func (cu *cpuCheckUsecase) CheckCPU(ctx context.Context) error {
-	history := cu.checkCPU(ctx)
+func (cu *cpuCheckUsecase) CheckCPU(ctx context.Context) (history *domain.CPUCheckHistory, err error) {
+	start := time.Now()
+	if cu.runLocker.lock(ctx, cu.myCfg.CheckOverlapPolicy()) {
+		toCtx, cancel := context.WithTimeout(ctx, cu.myCfg.CPUCheckTimeOut())
+		history = cu.checkCPU(toCtx)
+		if toCtx.Err() != nil {
+			if history.ProcessLevel.Last() != errorLevel {
+				history.ProcessLevel.Append(errorLevel)
+			}
+			switch {
+			case history.Error != nil:
+			case toCtx.Err() == context.DeadlineExceeded:
+				history.SetError(errors.Wrapf(toCtx.Err(), "cpu check process is timed out (%s)", cu.myCfg.CPUCheckTimeOut()))
+			default:
+				history.SetError(errors.Wrap(toCtx.Err(), "cpu check process is canceled"))
+			}
+		}
+		cancel()
+		cu.runLocker.unlock()
+		cu.setLastHistory(history)
+	} else {
+		history = cu.skippedHistory()
+	}
+	cu.recordMetrics(history, time.Since(start))
 
 	if b, err := cu.historyRepo.Store(history); err != nil {
-		return errors.Wrapf(err, "failed to store cpu check history, response: %s", string(b))
+		return history, errors.Wrapf(err, "failed to store cpu check history, response: %s", string(b))
 	}
 
-	return nil
+	return history, nil
+}
+
+// skippedHistory return cpu check history represent that check process is skipped as same process is already running
+func (cu *cpuCheckUsecase) skippedHistory() (history *domain.CPUCheckHistory) {
+	history = new(domain.CPUCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = uuid.New().String()
+	history.ProcessLevel.Set(skippedLevel)
+	history.Message = "cpu check is skipped as previous cpu check process is still running"
+	return
+}
+
+// newAlert return alert about cpu check history with thresholds configured in cpu check config
+func (cu *cpuCheckUsecase) newAlert(history *domain.CPUCheckHistory, level domain.AlertLevel, text string) (alert *domain.Alert) {
+	alert = history.NewAlert(level, text)
+	alert.Thresholds["cpu_warning_usage"] = cu.myCfg.CPUWarningUsage()
+	alert.Thresholds["cpu_maximum_usage"] = cu.myCfg.CPUMaximumUsage()
+	alert.Thresholds["cpu_minimum_usage_to_remove"] = cu.myCfg.CPUMinimumUsageToRemove()
+	return
+}
+
+// notify deliver alert about cpu check history to notifier agency, except when cpu check is in maintenance window
+// if alert is suppressed by maintenance window, id of that window is set in history
+// reason is stable code of why alert is produced (Ex, container_remove_failed), used for deduplicating it in notifier
+func (cu *cpuCheckUsecase) notify(history *domain.CPUCheckHistory, level domain.AlertLevel, reason, text string) (t time.Time, _text string, err error) {
+	if id := cu.maintenanceAgency.GetActiveWindow(cpuCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+	alert := cu.newAlert(history, level, text)
+	alert.Reason = reason
+	return cu.notifierAgency.Notify(alert)
 }
 
 // method with below logic about handling health check process according to current cpu check status
@@ -128,12 +241,12 @@ func (cu *cpuCheckUsecase) checkCPU(ctx context.Context) (history *domain.CPUChe
 	history.FillPrivateComponent()
 	history.UUID = _uuid
 
-	_totalUsage, err := cu.cpuSysAgency.GetTotalSystemCPUUsage()
+	_totalUsage, err := cu.cpuSysAgency.GetTotalSystemCPUUsage(ctx)
 	if err != nil {
 		history.ProcessLevel.Set(errorLevel)
 		history.SetError(errors.Wrap(err, "failed to get total system cpu usage"))
 		msg := "!cpu check error occurred! unable to get total cpu usage"
-		history.SetAlarmResult(cu.slackChatAgency.SendMessage("x", msg, _uuid))
+		history.SetAlarmResult(cu.notify(history, domain.AlertLevelError, "usage_unavailable", msg))
 		return
 	}
 	history.TotalUsageCore = _totalUsage
@@ -156,61 +269,79 @@ func (cu *cpuCheckUsecase) checkCPU(ctx context.Context) (history *domain.CPUChe
 			history.ProcessLevel.Set(recoveredLevel)
 			history.Message = "cpu check is recovered to be healthy"
 			msg := fmt.Sprintf("!cpu check recovered to health! current cpu usage - %.02f", totalUsage.V)
-			_, _, _ = cu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = cu.notify(history, domain.AlertLevelRecovered, "recovered", msg)
+			cu.escalationAgency.CloseEscalation(cpuCheckKey, cu.newAlert(history, domain.AlertLevelRecovered, msg))
 		} else {
 			history.ProcessLevel.Set(unhealthyLevel)
 			history.Message = "cpu check is unhealthy now"
+			cu.escalate(history)
 		}
 		return
 	}
 
+	// suppress remediation & alarm if cpu check is in maintenance window, history is still stored with measured value
+	if id := cu.maintenanceAgency.GetActiveWindow(cpuCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		history.ProcessLevel.Set(maintenanceLevel)
+		history.Message = "cpu check is in maintenance window, so remediation & alarm are suppressed"
+		return
+	}
+
 	if totalUsage.isMoreThan(cu.myCfg.CPUMaximumUsage()) {
 		cu.setStatus(cpuStatusRecovering)
 		history.ProcessLevel.Set(weakDetectedLevel)
 		msg := fmt.Sprintf("!cpu check weak detected! start to provision CPU (current cpu usage - %.02f)", totalUsage.V)
-		history.SetAlarmResult(cu.slackChatAgency.SendMessage("pill", msg, _uuid))
+		history.SetAlarmResult(cu.notify(history, domain.AlertLevelWeakDetected, "provisioning_started", msg))
 
-		result, err := cu.cpuSysAgency.CalculateContainersCPUUsage()
+		result, err := cu.cpuSysAgency.CalculateContainersCPUUsage(ctx)
 		if err != nil {
 			cu.setStatus(cpuStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!cpu check error occurred! failed to calculate container cpu, please check for yourself"
-			_, _, _ = cu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = cu.notify(history, domain.AlertLevelRecoveryError, "container_usage_unavailable", msg)
 			history.SetError(errors.Wrap(err, "failed to calculate containers cpu usage"))
 			return
 		}
 		history.DockerUsageCore = result.TotalCPUUsage()
 
 		id, name, _usage := result.MostConsumerExceptFor(requiredContainers)
+		if window := cu.maintenanceAgency.GetActiveWindow("", name); window != "" {
+			// most consumer is in maintenance window, so pick next consumer except for containers in maintenance window
+			history.SetMaintenanceWindow(window)
+			except := append(append([]string{}, requiredContainers...), cu.maintenanceAgency.GetActiveContainers()...)
+			id, name, _usage = result.MostConsumerExceptFor(except)
+		}
 		history.MostCPUConsumeContainer = name
 		var usage = float64Comparator{V: _usage}
 
 		if usage.isLessThan(cu.myCfg.CPUMinimumUsageToRemove()) {
 			cu.setStatus(cpuStatusUnhealthy)
 			msg := "!cpu check error occurred! cpu usage is too small to remove, please check for yourself"
-			_, _, _ = cu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = cu.notify(history, domain.AlertLevelRecoveryError, "usage_too_small_to_remove", msg)
 			history.SetError(errors.New("cpu usage is too small to remove"))
 			return
 		}
 
-		if err := cu.dockerAgency.RemoveContainer(id, types.ContainerRemoveOptions{Force: true}); err != nil {
+		if err := cu.dockerAgency.RemoveContainer(ctx, id, types.ContainerRemoveOptions{Force: true}); err != nil {
 			cu.setStatus(cpuStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!cpu check error occurred! failed to remove container, please check for yourself"
-			_, _, _ = cu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = cu.notify(history, domain.AlertLevelRecoveryError, "container_remove_failed", msg)
 			history.SetError(errors.Wrap(err, "failed to remove container"))
 			return
 		} else {
+			cu.metricAgency.AddCounter(checkRemediationsMetric, remediationLabels("cpu", "container_removed"), 1)
 			history.TemporaryFreeCore = usage.V
+			history.RemovedContainer = name
 			history.Message = "removed most cpu consumed container as cpu usage is over than maximum"
 		}
 
-		_againTotalUsage, err := cu.cpuSysAgency.GetTotalSystemCPUUsage()
+		_againTotalUsage, err := cu.cpuSysAgency.GetTotalSystemCPUUsage(ctx)
 		if err != nil {
 			cu.setStatus(cpuStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!cpu check error occurred! failed to again calculate container cpu, please check for yourself"
-			_, _, _ = cu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = cu.notify(history, domain.AlertLevelUnhealthy, "usage_unavailable_after_remediation", msg)
 			history.SetError(errors.Wrap(err, "failed to again calculate containers cpu usage"))
 			return
 		}
@@ -219,11 +350,11 @@ func (cu *cpuCheckUsecase) checkCPU(ctx context.Context) (history *domain.CPUChe
 		if againTotalUsage.isLessThan(cu.myCfg.CPUMaximumUsage()) {
 			cu.setStatus(cpuStatusHealthy)
 			msg := fmt.Sprintf("!cpu check is healthy! current cpu usage - %.02f", againTotalUsage.V)
-			_, _, _ = cu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = cu.notify(history, domain.AlertLevelRecovered, "recovered_by_remediation", msg)
 		} else {
 			cu.setStatus(cpuStatusUnhealthy)
 			msg := "!cpu check has deteriorated! please check for yourself"
-			_, _, _ = cu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = cu.notify(history, domain.AlertLevelUnhealthy, "deteriorated", msg)
 		}
 	} else if totalUsage.isMoreThan(cu.myCfg.CPUWarningUsage()) {
 		history.ProcessLevel.Set(warningLevel)
@@ -231,7 +362,7 @@ func (cu *cpuCheckUsecase) checkCPU(ctx context.Context) (history *domain.CPUChe
 		if cu.status != cpuStatusWarning {
 			cu.setStatus(cpuStatusWarning)
 			msg := fmt.Sprintf("!cpu check warning! current cpu usage - %.02f", totalUsage.V)
-			history.SetAlarmResult(cu.slackChatAgency.SendMessage("warning", msg, _uuid))
+			history.SetAlarmResult(cu.notify(history, domain.AlertLevelWarning, "warning", msg))
 		}
 	} else {
 		history.ProcessLevel.Set(healthyLevel)
@@ -241,9 +372,170 @@ func (cu *cpuCheckUsecase) checkCPU(ctx context.Context) (history *domain.CPUChe
 	return
 }
 
+// ResetStatus reset status of cpu check to healthy after administrator fixed problem & store history about that reset
+// it waits until running cpu check process is finished, so that reset status is not overwritten by that process
+// Implement ResetStatus method of domain.CPUCheckUseCase interface
+func (cu *cpuCheckUsecase) ResetStatus(ctx context.Context, by, reason string) (history *domain.CPUCheckHistory, err error) {
+	if !cu.runLocker.lock(ctx, overlapPolicyQueue) {
+		err = errors.Wrap(ctx.Err(), "context done while waiting running cpu check process")
+		return
+	}
+	defer cu.runLocker.unlock()
+
+	_uuid := uuid.New().String()
+	history = new(domain.CPUCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = _uuid
+	history.ProcessLevel.Set(resetLevel)
+	history.SetResetResult(by, reason)
+	history.Message = fmt.Sprintf("cpu check status is reset to healthy from %s", cu.GetStatus())
+
+	cu.setStatus(cpuStatusHealthy)
+	msg := fmt.Sprintf("!cpu check status reset! reset to healthy by %s (reason - %s)", by, reason)
+	alert := cu.newAlert(history, domain.AlertLevelReset, msg)
+	history.SetAlarmResult(cu.notifierAgency.Notify(alert))
+	cu.escalationAgency.CloseEscalation(cpuCheckKey, alert)
+
+	if b, err := cu.historyRepo.Store(history); err != nil {
+		return history, errors.Wrapf(err, "failed to store cpu check history, response: %s", string(b))
+	}
+
+	return history, nil
+}
+
+// escalate count cycle of cpu check staying unhealthy & escalate incident with escalation agency if rule is satisfied
+func (cu *cpuCheckUsecase) escalate(history *domain.CPUCheckHistory) {
+	cu.mutex.Lock()
+	cu.unhealthyCycles++
+	cycles, since := cu.unhealthyCycles, cu.statusChangedAt
+	cu.mutex.Unlock()
+
+	// incident in maintenance window is not escalated, cycles are still counted to escalate after window ends
+	if id := cu.maintenanceAgency.GetActiveWindow(cpuCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+
+	msg := fmt.Sprintf("!cpu check escalated! still unhealthy after %d cycles, please check for yourself", cycles)
+	history.SetAlarmResult(cu.escalationAgency.Escalate(cpuCheckKey, since, cycles, cu.newAlert(history, domain.AlertLevelEscalated, msg)))
+}
+
 // setStatus set status field value using mutex Lock & Unlock
+// status is stored in status store only if changed, after unlocking mutex so that slow store doesn't block GetStatus
 func (cu *cpuCheckUsecase) setStatus(status cpuCheckStatus) {
 	cu.mutex.Lock()
-	defer cu.mutex.Unlock()
+	changed := cu.status != status
+	if changed {
+		cu.statusChangedAt = domain.Now()
+		cu.unhealthyCycles = 0
+	}
 	cu.status = status
+	transitedAt := cu.statusChangedAt
+	cu.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+	if err := cu.statusStoreAgency.StoreStatus(ctx, cpuCheckKey, status.String(), transitedAt); err != nil {
+		log.Printf("failed to store cpu check status in status store, err: %v", err)
+	}
+}
+
+// loadStatus load status of cpu check saved in status store, so that status survives restart
+// recovering status is loaded as unhealthy, because recovering process was stopped by restart & should be checked
+func (cu *cpuCheckUsecase) loadStatus() {
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+
+	saved, transitedAt, err := cu.statusStoreAgency.LoadStatus(ctx, cpuCheckKey)
+	if err != nil {
+		log.Printf("failed to load cpu check status from status store, err: %v", err)
+		return
+	}
+
+	for status := cpuStatusHealthy; status <= cpuStatusUnhealthy; status++ {
+		if status.String() != saved {
+			continue
+		}
+		if status == cpuStatusRecovering {
+			status = cpuStatusUnhealthy
+		}
+		cu.status, cu.statusChangedAt = status, transitedAt
+		return
+	}
+}
+
+// GetStatus return current status of cpu check process as string
+// Implement GetStatus method of domain.CPUCheckUseCase interface
+func (cu *cpuCheckUsecase) GetStatus() string {
+	cu.mutex.Lock()
+	defer cu.mutex.Unlock()
+	return cu.status.String()
+}
+
+// GetLastHistory return cpu check history produced in last check process
+// Implement GetLastHistory method of domain.CPUCheckUseCase interface
+func (cu *cpuCheckUsecase) GetLastHistory() *domain.CPUCheckHistory {
+	cu.mutex.Lock()
+	defer cu.mutex.Unlock()
+	return cu.lastHistory
+}
+
+// setLastHistory set lastHistory field value using mutex Lock & Unlock
+func (cu *cpuCheckUsecase) setLastHistory(history *domain.CPUCheckHistory) {
+	cu.mutex.Lock()
+	defer cu.mutex.Unlock()
+	cu.lastHistory = history
+}
+
+// GetHistory return cpu check history having uuid in history repository (nil if not exist)
+// Implement GetHistory method of domain.CPUCheckUseCase interface
+func (cu *cpuCheckUsecase) GetHistory(ctx context.Context, uuid string) (history *domain.CPUCheckHistory, err error) {
+	if history, err = cu.historyRepo.GetByUUID(ctx, uuid); err != nil {
+		err = errors.Wrapf(err, "failed to get cpu check history, uuid: %s", uuid)
+	}
+	return
+}
+
+// FindHistories return cpu check histories stored between start & end, filtered by process level if level is not empty
+// Implement FindHistories method of domain.CPUCheckUseCase interface
+func (cu *cpuCheckUsecase) FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*domain.CPUCheckHistory, err error) {
+	if level == "" {
+		histories, err = cu.historyRepo.FindByTimeRange(ctx, start, end)
+	} else {
+		histories, err = cu.historyRepo.FindByProcessLevel(ctx, level, start, end)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to find cpu check histories")
+	}
+	return
+}
+
+// FindLatestHistories return latest n cpu check histories in history repository in order of newest first
+// Implement FindLatestHistories method of domain.CPUCheckUseCase interface
+func (cu *cpuCheckUsecase) FindLatestHistories(ctx context.Context, n int) (histories []*domain.CPUCheckHistory, err error) {
+	if histories, err = cu.historyRepo.FindLatest(ctx, n); err != nil {
+		err = errors.Wrap(err, "failed to find latest cpu check histories")
+	}
+	return
+}
+
+// recordMetrics record metrics about cpu check process & cpu usage in history using metric agency
+func (cu *cpuCheckUsecase) recordMetrics(history *domain.CPUCheckHistory, elapsed time.Duration) {
+	var statuses []string
+	for s := cpuStatusHealthy; s <= cpuStatusUnhealthy; s++ {
+		statuses = append(statuses, s.String())
+	}
+	recordCommonMetrics(cu.metricAgency, "cpu", history.ProcessLevel.String(), elapsed, cu.GetStatus(), statuses)
+
+	if level := history.ProcessLevel.String(); level == errorLevel || level == skippedLevel {
+		return
+	}
+	cu.metricAgency.SetGauge("syscheck_cpu_total_usage_cores", nil, history.TotalUsageCore)
+	if history.DockerUsageCore != 0 {
+		cu.metricAgency.SetGauge("syscheck_cpu_docker_usage_cores", nil, history.DockerUsageCore)
+	}
 }
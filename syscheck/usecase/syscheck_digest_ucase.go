@@ -0,0 +1,149 @@
+// syscheck_digest_ucase.go is file that define usecase implementation about digest of syscheck domain
+// digest usecase summarize histories read from disk, cpu, memory check history repository & post it as report
+
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// digestUsecase implement SystemCheckDigestUseCase interface in domain and used in delivery layer
+type digestUsecase struct {
+	// diskHistoryRepo is used for reading disk check history stored in period
+	diskHistoryRepo domain.DiskCheckHistoryRepository
+
+	// cpuHistoryRepo is used for reading cpu check history stored in period
+	cpuHistoryRepo domain.CPUCheckHistoryRepository
+
+	// memoryHistoryRepo is used for reading memory check history stored in period
+	memoryHistoryRepo domain.MemoryCheckHistoryRepository
+
+	// reportAgency is used as agency about report to post digest
+	reportAgency reportAgency
+}
+
+// NewDigestUsecase function return digestUsecase ptr instance after initializing
+func NewDigestUsecase(
+	dhr domain.DiskCheckHistoryRepository,
+	chr domain.CPUCheckHistoryRepository,
+	mhr domain.MemoryCheckHistoryRepository,
+	ra reportAgency,
+) domain.SystemCheckDigestUseCase {
+	return &digestUsecase{
+		diskHistoryRepo:   dhr,
+		cpuHistoryRepo:    chr,
+		memoryHistoryRepo: mhr,
+		reportAgency:      ra,
+	}
+}
+
+// Digest summarize system check histories stored between start & end, and post it as report with report agency
+// Implement Digest method of domain.SystemCheckDigestUseCase interface
+func (dgu *digestUsecase) Digest(ctx context.Context, period string, start, end time.Time) (digest *domain.SystemCheckDigest, err error) {
+	digest = &domain.SystemCheckDigest{
+		Period:      period,
+		Start:       start,
+		End:         end,
+		LevelCounts: map[string]map[string]int{},
+	}
+
+	diskHistories, err := dgu.diskHistoryRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		err = errors.Wrap(err, "failed to find disk check histories")
+		return
+	}
+	digest.LevelCounts["disk"] = map[string]int{}
+	for _, history := range diskHistories {
+		countLevels(digest.LevelCounts["disk"], history.ProcessLevel)
+		digest.ReclaimedCap += history.ReclaimedCap
+		if history.RemainingCap != 0 && (digest.MinRemainingCap == 0 || history.RemainingCap < digest.MinRemainingCap) {
+			digest.MinRemainingCap = history.RemainingCap
+		}
+	}
+
+	cpuHistories, err := dgu.cpuHistoryRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		err = errors.Wrap(err, "failed to find cpu check histories")
+		return
+	}
+	digest.LevelCounts["cpu"] = map[string]int{}
+	for _, history := range cpuHistories {
+		countLevels(digest.LevelCounts["cpu"], history.ProcessLevel)
+		if history.RemovedContainer != "" {
+			digest.RemovedContainers++
+		}
+		if history.TotalUsageCore > digest.PeakCPUUsageCore {
+			digest.PeakCPUUsageCore = history.TotalUsageCore
+		}
+	}
+
+	memoryHistories, err := dgu.memoryHistoryRepo.FindByTimeRange(ctx, start, end)
+	if err != nil {
+		err = errors.Wrap(err, "failed to find memory check histories")
+		return
+	}
+	digest.LevelCounts["memory"] = map[string]int{}
+	for _, history := range memoryHistories {
+		countLevels(digest.LevelCounts["memory"], history.ProcessLevel)
+		if history.RemovedContainer != "" {
+			digest.RemovedContainers++
+		}
+		if history.TotalUsageMemory > digest.PeakMemoryUsage {
+			digest.PeakMemoryUsage = history.TotalUsageMemory
+		}
+	}
+
+	title := fmt.Sprintf("system check %s digest (%s ~ %s)", period, start.Format(digestTimeLayout), end.Format(digestTimeLayout))
+	lines := levelCountLines(digest.LevelCounts, []string{"disk", "cpu", "memory"})
+	lines = append(lines,
+		fmt.Sprintf("containers removed - %d", digest.RemovedContainers),
+		fmt.Sprintf("disk reclaimed by docker system prune - %s", digest.ReclaimedCap),
+		fmt.Sprintf("peak cpu usage - %.02f core", digest.PeakCPUUsageCore),
+		fmt.Sprintf("peak memory usage - %s", digest.PeakMemoryUsage),
+		fmt.Sprintf("minimum remaining disk - %s", digest.MinRemainingCap),
+	)
+
+	if _, err = dgu.reportAgency.PostReport(title, lines); err != nil {
+		err = errors.Wrap(err, "failed to post system check digest")
+	}
+	return
+}
+
+// digestTimeLayout is layout of time printed in title of digest report
+const digestTimeLayout = "2006-01-02 15:04"
+
+// countLevels add count of each process level in history to counts, history having several level is counted per level
+func countLevels(counts map[string]int, levels []string) {
+	for _, level := range levels {
+		counts[level]++
+	}
+}
+
+// levelCountLines return lines of process level count per check in order of checks, level is sorted by name
+func levelCountLines(levelCounts map[string]map[string]int, checks []string) (lines []string) {
+	for _, check := range checks {
+		counts := levelCounts[check]
+		levels := make([]string, 0, len(counts))
+		for level := range counts {
+			levels = append(levels, level)
+		}
+		sort.Strings(levels)
+
+		parts := make([]string, 0, len(levels))
+		for _, level := range levels {
+			parts = append(parts, fmt.Sprintf("%s %d", level, counts[level]))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, "no history")
+		}
+		lines = append(lines, fmt.Sprintf("%s check - %s", check, strings.Join(parts, ", ")))
+	}
+	return
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"github.com/inhies/go-bytesize"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// fakeReportAgency is reportAgency recording lines of posted report
+type fakeReportAgency struct {
+	lines []string
+}
+
+func (ra *fakeReportAgency) PostReport(title string, lines []string) (time.Time, error) {
+	ra.lines = lines
+	return time.Now(), nil
+}
+
+// fake repositories return histories restored from dotted map, same as histories read from real repository
+type fakeDiskRepo struct {
+	domain.DiskCheckHistoryRepository
+	histories []*domain.DiskCheckHistory
+}
+
+func (r fakeDiskRepo) FindByTimeRange(context.Context, time.Time, time.Time) (histories []*domain.DiskCheckHistory, _ error) {
+	for _, history := range r.histories {
+		restored := new(domain.DiskCheckHistory)
+		restored.FillFromDottedMap(history.DottedMapWithPrefix(""))
+		histories = append(histories, restored)
+	}
+	return
+}
+
+type fakeCPURepo struct {
+	domain.CPUCheckHistoryRepository
+	histories []*domain.CPUCheckHistory
+}
+
+func (r fakeCPURepo) FindByTimeRange(context.Context, time.Time, time.Time) (histories []*domain.CPUCheckHistory, _ error) {
+	for _, history := range r.histories {
+		restored := new(domain.CPUCheckHistory)
+		restored.FillFromDottedMap(history.DottedMapWithPrefix(""))
+		histories = append(histories, restored)
+	}
+	return
+}
+
+type fakeMemoryRepo struct {
+	domain.MemoryCheckHistoryRepository
+	histories []*domain.MemoryCheckHistory
+}
+
+func (r fakeMemoryRepo) FindByTimeRange(context.Context, time.Time, time.Time) (histories []*domain.MemoryCheckHistory, _ error) {
+	for _, history := range r.histories {
+		restored := new(domain.MemoryCheckHistory)
+		restored.FillFromDottedMap(history.DottedMapWithPrefix(""))
+		histories = append(histories, restored)
+	}
+	return
+}
+
+func TestDigestCountsRemovedContainers(t *testing.T) {
+	removedCPU := &domain.CPUCheckHistory{TemporaryFreeCore: 0.5, RemovedContainer: "DSM_SMS_service-auth"}
+	removedCPU.FillPrivateComponent()
+	removedCPU.ProcessLevel.Set(weakDetectedLevel)
+	removedCPU.ProcessLevel.Append(recoveringLevel)
+
+	// temporary free is recorded without removing container when it is failed, so it should not be counted
+	failedCPU := &domain.CPUCheckHistory{TemporaryFreeCore: 0.5}
+	failedCPU.FillPrivateComponent()
+	failedCPU.ProcessLevel.Set(weakDetectedLevel)
+
+	removedMemory := &domain.MemoryCheckHistory{TemporaryFreeMemory: bytesize.GB, RemovedContainer: "DSM_SMS_service-club"}
+	removedMemory.FillPrivateComponent()
+	removedMemory.ProcessLevel.Set(weakDetectedLevel)
+
+	failedMemory := &domain.MemoryCheckHistory{TemporaryFreeMemory: bytesize.GB}
+	failedMemory.FillPrivateComponent()
+	failedMemory.ProcessLevel.Set(weakDetectedLevel)
+
+	ra := &fakeReportAgency{}
+	du := NewDigestUsecase(
+		fakeDiskRepo{},
+		fakeCPURepo{histories: []*domain.CPUCheckHistory{removedCPU, failedCPU}},
+		fakeMemoryRepo{histories: []*domain.MemoryCheckHistory{removedMemory, failedMemory}},
+		ra,
+	)
+
+	end := time.Now()
+	digest, err := du.Digest(context.Background(), "daily", end.AddDate(0, 0, -1), end)
+	if err != nil {
+		t.Fatalf("digest should succeed, err: %v", err)
+	}
+
+	if digest.RemovedContainers != 2 {
+		t.Errorf("containers removed in cpu & memory check should be counted, got: %d", digest.RemovedContainers)
+	}
+	if !containsLine(ra.lines, "containers removed - 2") {
+		t.Errorf("report should have count of removed containers, got: %v", ra.lines)
+	}
+}
+
+func TestDigestSummarizesHistories(t *testing.T) {
+	newDisk := func(remaining, reclaimed bytesize.ByteSize, levels ...string) *domain.DiskCheckHistory {
+		history := &domain.DiskCheckHistory{RemainingCap: remaining, ReclaimedCap: reclaimed}
+		history.FillPrivateComponent()
+		history.ProcessLevel.Set(levels[0])
+		for _, level := range levels[1:] {
+			history.ProcessLevel.Append(level)
+		}
+		return history
+	}
+	newCPU := func(usage float64, level string) *domain.CPUCheckHistory {
+		history := &domain.CPUCheckHistory{TotalUsageCore: usage}
+		history.FillPrivateComponent()
+		history.ProcessLevel.Set(level)
+		return history
+	}
+	newMemory := func(usage bytesize.ByteSize, level string) *domain.MemoryCheckHistory {
+		history := &domain.MemoryCheckHistory{TotalUsageMemory: usage}
+		history.FillPrivateComponent()
+		history.ProcessLevel.Set(level)
+		return history
+	}
+
+	ra := &fakeReportAgency{}
+	du := NewDigestUsecase(
+		fakeDiskRepo{histories: []*domain.DiskCheckHistory{
+			newDisk(bytesize.GB*10, 0, healthyLevel),
+			newDisk(bytesize.GB*2, bytesize.GB*3, weakDetectedLevel, recoveringLevel, recoveredLevel),
+			newDisk(bytesize.GB*4, bytesize.MB*512, weakDetectedLevel, recoveringLevel, unhealthyLevel),
+			newDisk(0, 0, errorLevel), // remaining capacity is not looked in check failed with error
+		}},
+		fakeCPURepo{histories: []*domain.CPUCheckHistory{
+			newCPU(0.8, healthyLevel),
+			newCPU(2.25, warningLevel),
+			newCPU(1.5, healthyLevel),
+		}},
+		fakeMemoryRepo{histories: []*domain.MemoryCheckHistory{
+			newMemory(bytesize.GB, healthyLevel),
+			newMemory(bytesize.GB*3, healthyLevel),
+		}},
+		ra,
+	)
+
+	end := time.Now()
+	digest, err := du.Digest(context.Background(), "daily", end.AddDate(0, 0, -1), end)
+	if err != nil {
+		t.Fatalf("digest should succeed, err: %v", err)
+	}
+
+	wantCounts := map[string]map[string]int{
+		"disk":   {healthyLevel: 1, weakDetectedLevel: 2, recoveringLevel: 2, recoveredLevel: 1, unhealthyLevel: 1, errorLevel: 1},
+		"cpu":    {healthyLevel: 2, warningLevel: 1},
+		"memory": {healthyLevel: 2},
+	}
+	if !reflect.DeepEqual(digest.LevelCounts, wantCounts) {
+		t.Errorf("level counts should be %v, got: %v", wantCounts, digest.LevelCounts)
+	}
+	if digest.PeakCPUUsageCore != 2.25 {
+		t.Errorf("peak cpu usage should be 2.25 core, got: %v", digest.PeakCPUUsageCore)
+	}
+	if digest.PeakMemoryUsage != bytesize.GB*3 {
+		t.Errorf("peak memory usage should be %s, got: %s", bytesize.GB*3, digest.PeakMemoryUsage)
+	}
+	if digest.MinRemainingCap != bytesize.GB*2 {
+		t.Errorf("minimum remaining disk should be %s except zero value, got: %s", bytesize.GB*2, digest.MinRemainingCap)
+	}
+	if want := bytesize.GB*3 + bytesize.MB*512; digest.ReclaimedCap != want {
+		t.Errorf("reclaimed disk should be total %s, got: %s", want, digest.ReclaimedCap)
+	}
+
+	for _, line := range []string{
+		"disk check - ERROR 1, HEALTHY 1, RECOVERED 1, RECOVERING 2, UNHEALTHY 1, WEAK_DETECTED 2",
+		"cpu check - HEALTHY 2, WARNING 1",
+		"memory check - HEALTHY 2",
+		"disk reclaimed by docker system prune - " + (bytesize.GB*3 + bytesize.MB*512).String(),
+		"peak cpu usage - 2.25 core",
+		"peak memory usage - " + (bytesize.GB * 3).String(),
+		"minimum remaining disk - " + (bytesize.GB * 2).String(),
+	} {
+		if !containsLine(ra.lines, line) {
+			t.Errorf("report should have line %q, got: %v", line, ra.lines)
+		}
+	}
+}
+
+func TestDigestWithoutHistory(t *testing.T) {
+	ra := &fakeReportAgency{}
+	du := NewDigestUsecase(fakeDiskRepo{}, fakeCPURepo{}, fakeMemoryRepo{}, ra)
+
+	end := time.Now()
+	if _, err := du.Digest(context.Background(), "weekly", end.AddDate(0, 0, -7), end); err != nil {
+		t.Fatalf("digest should succeed, err: %v", err)
+	}
+	for _, line := range []string{"disk check - no history", "cpu check - no history", "memory check - no history"} {
+		if !containsLine(ra.lines, line) {
+			t.Errorf("report should have line %q, got: %v", line, ra.lines)
+		}
+	}
+}
+
+// containsLine return if lines has line same with target
+func containsLine(lines []string, target string) bool {
+	for _, line := range lines {
+		if line == target {
+			return true
+		}
+	}
+	return false
+}
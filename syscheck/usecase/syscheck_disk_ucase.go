@@ -10,7 +10,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/inhies/go-bytesize"
 	"github.com/pkg/errors"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
 )
@@ -23,6 +25,22 @@ const (
 	diskStatusUnhealthy                         // represent disk check status is unhealthy
 )
 
+// String method return string represent disk check status, which is used in delivery layer
+func (s diskCheckStatus) String() string {
+	switch s {
+	case diskStatusHealthy:
+		return "HEALTHY"
+	case diskStatusRecovering:
+		return "RECOVERING"
+	case diskStatusUnhealthy:
+		return "UNHEALTHY"
+	}
+	return "UNKNOWN"
+}
+
+// diskCheckKey is key identifying disk check in status store agency & maintenance window
+const diskCheckKey = "syscheck.disk"
+
 // diskCheckUsecase implement DiskCheckUsecase interface in domain and used in delivery layer
 type diskCheckUsecase struct {
 	// myCfg is used for getting disk check usecase config
@@ -31,8 +49,20 @@ type diskCheckUsecase struct {
 	// historyRepo is used for store disk check history and injected from outside
 	historyRepo domain.DiskCheckHistoryRepository
 
-	// slackChat is used for agent slack API about chatting
-	slackChatAgency slackChatAgency
+	// notifierAgency is used for agent notifiers delivering alert
+	notifierAgency notifierAgency
+
+	// metricAgency is used as agency about metric collector to expose numbers computed in check process
+	metricAgency metricAgency
+
+	// statusStoreAgency is used as agency about status store to keep status of state machine across restart
+	statusStoreAgency statusStoreAgency
+
+	// maintenanceAgency is used for checking if check or container is in maintenance window
+	maintenanceAgency maintenanceAgency
+
+	// escalationAgency is used for escalating incident of disk check staying unhealthy long
+	escalationAgency escalationAgency
 
 	// diskSysAgency is used as agency about disk system command
 	diskSysAgency diskSysAgency
@@ -40,8 +70,20 @@ type diskCheckUsecase struct {
 	// status represent current process status of disk health check
 	status diskCheckStatus
 
+	// statusChangedAt represent time when status field value was changed last
+	statusChangedAt time.Time
+
+	// unhealthyCycles represent how many check cycles have run since status was changed to unhealthy
+	unhealthyCycles int
+
+	// lastHistory represent disk check history produced in last check process
+	lastHistory *domain.DiskCheckHistory
+
 	// mutex help to prevent race condition when set status field value
 	mutex sync.Mutex
+
+	// runLocker help to prevent disk check process from running at the same time
+	runLocker checkRunLocker
 }
 
 // diskCheckUsecaseConfig is the config getter interface for disk check usecase
@@ -49,6 +91,9 @@ type diskCheckUsecaseConfig interface {
 	// get common config method from embedding systemCheckUsecaseComponentConfig
 	systemCheckUsecaseComponentConfig
 
+	// DiskCheckTimeOut method returns time.Duration represent deadline of one disk check process
+	DiskCheckTimeOut() time.Duration
+
 	// DiskMinCapacity method returns byte size represent disk minimum capacity
 	DiskMinCapacity() bytesize.ByteSize
 }
@@ -56,42 +101,106 @@ type diskCheckUsecaseConfig interface {
 // diskSysAgency is agency that agent various command about disk system
 type diskSysAgency interface {
 	// GetRemainDiskCapacity return remain disk capacity expressed in bytesize package
-	GetRemainDiskCapacity() (size bytesize.ByteSize, err error)
+	GetRemainDiskCapacity(ctx context.Context) (size bytesize.ByteSize, err error)
 
 	// PruneDockerSystem prune all about docker system and return reclaimed size
-	PruneDockerSystem() (reclaimed bytesize.ByteSize, err error)
+	PruneDockerSystem(ctx context.Context) (reclaimed bytesize.ByteSize, err error)
 }
 
 // NewDiskCheckUsecase function return diskCheckUsecase ptr instance with initializing
 func NewDiskCheckUsecase(
 	cfg diskCheckUsecaseConfig,
 	dhr domain.DiskCheckHistoryRepository,
-	sca slackChatAgency,
+	na notifierAgency,
+	ma metricAgency,
+	ssa statusStoreAgency,
+	mta maintenanceAgency,
+	eca escalationAgency,
 	dsa diskSysAgency,
 ) domain.DiskCheckUseCase {
-	return &diskCheckUsecase{
+	usecase := &diskCheckUsecase{
 		// initialize field with parameter received from caller
-		myCfg:           cfg,
-		historyRepo:     dhr,
-		slackChatAgency: sca,
-		diskSysAgency:   dsa,
+		myCfg:             cfg,
+		historyRepo:       dhr,
+		notifierAgency:    na,
+		metricAgency:      ma,
+		statusStoreAgency: ssa,
+		maintenanceAgency: mta,
+		escalationAgency:  eca,
+		diskSysAgency:     dsa,
 
 		// initialize field with default value
-		status: diskStatusHealthy,
-		mutex:  sync.Mutex{},
+		status:    diskStatusHealthy,
+		mutex:     sync.Mutex{},
+		runLocker: newCheckRunLocker(),
 	}
+
+	usecase.loadStatus()
+	return usecase
 }
 
 // CheckDisk check disk health with checkDisk method & store check log in repository
 // Implement CheckDisk method of domain.DiskCheckUseCase interface
-func (du *diskCheckUsecase) CheckDisk(ctx context.Context) error {
-	history := du.checkDisk(ctx)
+func (du *diskCheckUsecase) CheckDisk(ctx context.Context) (history *domain.DiskCheckHistory, err error) {
+	start := time.Now()
+	if du.runLocker.lock(ctx, du.myCfg.CheckOverlapPolicy()) {
+		toCtx, cancel := context.WithTimeout(ctx, du.myCfg.DiskCheckTimeOut())
+		history = du.checkDisk(toCtx)
+		if toCtx.Err() != nil {
+			if history.ProcessLevel.Last() != errorLevel {
+				history.ProcessLevel.Append(errorLevel)
+			}
+			switch {
+			case history.Error != nil:
+			case toCtx.Err() == context.DeadlineExceeded:
+				history.SetError(errors.Wrapf(toCtx.Err(), "disk check process is timed out (%s)", du.myCfg.DiskCheckTimeOut()))
+			default:
+				history.SetError(errors.Wrap(toCtx.Err(), "disk check process is canceled"))
+			}
+		}
+		cancel()
+		du.runLocker.unlock()
+		du.setLastHistory(history)
+	} else {
+		history = du.skippedHistory()
+	}
+	du.recordMetrics(history, time.Since(start))
 
 	if b, err := du.historyRepo.Store(history); err != nil {
-		return errors.Wrapf(err, "failed to store disk check history, response: %s", string(b))
+		return history, errors.Wrapf(err, "failed to store disk check history, response: %s", string(b))
 	}
 
-	return nil
+	return history, nil
+}
+
+// skippedHistory return disk check history represent that check process is skipped as same process is already running
+func (du *diskCheckUsecase) skippedHistory() (history *domain.DiskCheckHistory) {
+	history = new(domain.DiskCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = uuid.New().String()
+	history.ProcessLevel.Set(skippedLevel)
+	history.Message = "disk check is skipped as previous disk check process is still running"
+	return
+}
+
+// newAlert return alert about disk check history with thresholds configured in disk check config
+func (du *diskCheckUsecase) newAlert(history *domain.DiskCheckHistory, level domain.AlertLevel, text string) (alert *domain.Alert) {
+	alert = history.NewAlert(level, text)
+	alert.Thresholds["disk_min_capacity"] = du.myCfg.DiskMinCapacity()
+	return
+}
+
+// notify deliver alert about disk check history to notifier agency, except when disk check is in maintenance window
+// if alert is suppressed by maintenance window, id of that window is set in history
+// reason is stable code of why alert is produced (Ex, container_remove_failed), used for deduplicating it in notifier
+func (du *diskCheckUsecase) notify(history *domain.DiskCheckHistory, level domain.AlertLevel, reason, text string) (t time.Time, _text string, err error) {
+	if id := du.maintenanceAgency.GetActiveWindow(diskCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+	alert := du.newAlert(history, level, text)
+	alert.Reason = reason
+	return du.notifierAgency.Notify(alert)
 }
 
 // method with below logic about handling health check process according to current disk check status
@@ -108,12 +217,12 @@ func (du *diskCheckUsecase) checkDisk(ctx context.Context) (history *domain.Disk
 	history.FillPrivateComponent()
 	history.UUID = _uuid
 
-	_remainCap, err := du.diskSysAgency.GetRemainDiskCapacity()
+	_remainCap, err := du.diskSysAgency.GetRemainDiskCapacity(ctx)
 	if err != nil {
 		history.ProcessLevel.Set(errorLevel)
 		history.SetError(errors.Wrap(err, "failed to get disk capacity"))
 		msg := "!disk check error occurred! unable to get remain disk capacity"
-		history.SetAlarmResult(du.slackChatAgency.SendMessage("x", msg, _uuid))
+		history.SetAlarmResult(du.notify(history, domain.AlertLevelError, "capacity_unavailable", msg))
 		return
 	}
 	history.RemainingCap = _remainCap
@@ -132,38 +241,50 @@ func (du *diskCheckUsecase) checkDisk(ctx context.Context) (history *domain.Disk
 			history.ProcessLevel.Set(recoveredLevel)
 			history.Message = "disk check is recovered to be healthy"
 			msg := fmt.Sprintf("!disk check recovered to health! remain capacity - %s", remainCap.V)
-			_, _, _ = du.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = du.notify(history, domain.AlertLevelRecovered, "recovered", msg)
+			du.escalationAgency.CloseEscalation(diskCheckKey, du.newAlert(history, domain.AlertLevelRecovered, msg))
 		} else {
 			history.ProcessLevel.Set(unhealthyLevel)
 			history.Message = "disk check is unhealthy now"
+			du.escalate(history)
 		}
 		return
 	}
 
+	// suppress remediation & alarm if disk check is in maintenance window, history is still stored with measured value
+	if id := du.maintenanceAgency.GetActiveWindow(diskCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		history.ProcessLevel.Set(maintenanceLevel)
+		history.Message = "disk check is in maintenance window, so remediation & alarm are suppressed"
+		return
+	}
+
 	if remainCap.isLessThan(du.myCfg.DiskMinCapacity()) {
 		du.setStatus(diskStatusRecovering)
 		history.ProcessLevel.Set(weakDetectedLevel)
 		msg := "!disk check weak detected! start to prune docker system"
-		history.SetAlarmResult(du.slackChatAgency.SendMessage("pill", msg, _uuid))
+		history.SetAlarmResult(du.notify(history, domain.AlertLevelWeakDetected, "prune_started", msg))
 
-		if r, err := du.diskSysAgency.PruneDockerSystem(); err != nil {
+		if r, err := du.diskSysAgency.PruneDockerSystem(ctx); err != nil {
 			du.setStatus(diskStatusUnhealthy)
 			history.ProcessLevel.Append(warningLevel)
 			msg := "!disk check error occurred! failed to prune docker system"
-			_, _, _ = du.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = du.notify(history, domain.AlertLevelRecoveryError, "prune_failed", msg)
 			history.SetError(errors.Wrap(err, "failed to prune docker system"))
 			return
 		} else {
+			du.metricAgency.AddCounter(checkRemediationsMetric, remediationLabels("disk", "docker_system_pruned"), 1)
+			du.metricAgency.AddCounter("syscheck_disk_reclaimed_bytes_total", nil, float64(r))
 			history.ReclaimedCap = r
 			history.Message = "pruned docker system as current disk capacity is less than the minimum"
 		}
 
-		_againRemainCap, err := du.diskSysAgency.GetRemainDiskCapacity()
+		_againRemainCap, err := du.diskSysAgency.GetRemainDiskCapacity(ctx)
 		if err != nil {
 			du.setStatus(diskStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!disk check error occurred! failed to again get disk capacity, please check for yourself"
-			_, _, _ = du.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = du.notify(history, domain.AlertLevelUnhealthy, "capacity_unavailable_after_remediation", msg)
 			history.SetError(errors.Wrap(err, "failed to again get remain disk capacity"))
 			return
 		}
@@ -172,11 +293,11 @@ func (du *diskCheckUsecase) checkDisk(ctx context.Context) (history *domain.Disk
 		if againRemainCap.isMoreThan(du.myCfg.DiskMinCapacity()) {
 			du.setStatus(diskStatusHealthy)
 			msg := fmt.Sprintf("!disk check is healthy by pruning! remain capacity - %s", againRemainCap.V)
-			_, _, _ = du.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = du.notify(history, domain.AlertLevelRecovered, "recovered_by_remediation", msg)
 		} else {
 			du.setStatus(diskStatusUnhealthy)
 			msg := "!disk check has deteriorated! please check for yourself"
-			_, _, _ = du.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = du.notify(history, domain.AlertLevelUnhealthy, "deteriorated", msg)
 		}
 	} else {
 		history.ProcessLevel.Set(healthyLevel)
@@ -186,9 +307,167 @@ func (du *diskCheckUsecase) checkDisk(ctx context.Context) (history *domain.Disk
 	return
 }
 
+// ResetStatus reset status of disk check to healthy after administrator fixed problem & store history about that reset
+// it waits until running disk check process is finished, so that reset status is not overwritten by that process
+// Implement ResetStatus method of domain.DiskCheckUseCase interface
+func (du *diskCheckUsecase) ResetStatus(ctx context.Context, by, reason string) (history *domain.DiskCheckHistory, err error) {
+	if !du.runLocker.lock(ctx, overlapPolicyQueue) {
+		err = errors.Wrap(ctx.Err(), "context done while waiting running disk check process")
+		return
+	}
+	defer du.runLocker.unlock()
+
+	_uuid := uuid.New().String()
+	history = new(domain.DiskCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = _uuid
+	history.ProcessLevel.Set(resetLevel)
+	history.SetResetResult(by, reason)
+	history.Message = fmt.Sprintf("disk check status is reset to healthy from %s", du.GetStatus())
+
+	du.setStatus(diskStatusHealthy)
+	msg := fmt.Sprintf("!disk check status reset! reset to healthy by %s (reason - %s)", by, reason)
+	alert := du.newAlert(history, domain.AlertLevelReset, msg)
+	history.SetAlarmResult(du.notifierAgency.Notify(alert))
+	du.escalationAgency.CloseEscalation(diskCheckKey, alert)
+
+	if b, err := du.historyRepo.Store(history); err != nil {
+		return history, errors.Wrapf(err, "failed to store disk check history, response: %s", string(b))
+	}
+
+	return history, nil
+}
+
+// escalate count cycle of disk check staying unhealthy & escalate incident with escalation agency if rule is satisfied
+func (du *diskCheckUsecase) escalate(history *domain.DiskCheckHistory) {
+	du.mutex.Lock()
+	du.unhealthyCycles++
+	cycles, since := du.unhealthyCycles, du.statusChangedAt
+	du.mutex.Unlock()
+
+	// incident in maintenance window is not escalated, cycles are still counted to escalate after window ends
+	if id := du.maintenanceAgency.GetActiveWindow(diskCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+
+	msg := fmt.Sprintf("!disk check escalated! still unhealthy after %d cycles, please check for yourself", cycles)
+	history.SetAlarmResult(du.escalationAgency.Escalate(diskCheckKey, since, cycles, du.newAlert(history, domain.AlertLevelEscalated, msg)))
+}
+
 // setStatus set status field value using mutex Lock & Unlock
+// status is stored in status store only if changed, after unlocking mutex so that slow store doesn't block GetStatus
 func (du *diskCheckUsecase) setStatus(status diskCheckStatus) {
 	du.mutex.Lock()
-	defer du.mutex.Unlock()
+	changed := du.status != status
+	if changed {
+		du.statusChangedAt = domain.Now()
+		du.unhealthyCycles = 0
+	}
 	du.status = status
+	transitedAt := du.statusChangedAt
+	du.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+	if err := du.statusStoreAgency.StoreStatus(ctx, diskCheckKey, status.String(), transitedAt); err != nil {
+		log.Printf("failed to store disk check status in status store, err: %v", err)
+	}
+}
+
+// loadStatus load status of disk check saved in status store, so that status survives restart
+// recovering status is loaded as unhealthy, because recovering process was stopped by restart & should be checked
+func (du *diskCheckUsecase) loadStatus() {
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+
+	saved, transitedAt, err := du.statusStoreAgency.LoadStatus(ctx, diskCheckKey)
+	if err != nil {
+		log.Printf("failed to load disk check status from status store, err: %v", err)
+		return
+	}
+
+	for status := diskStatusHealthy; status <= diskStatusUnhealthy; status++ {
+		if status.String() != saved {
+			continue
+		}
+		if status == diskStatusRecovering {
+			status = diskStatusUnhealthy
+		}
+		du.status, du.statusChangedAt = status, transitedAt
+		return
+	}
+}
+
+// GetStatus return current status of disk check process as string
+// Implement GetStatus method of domain.DiskCheckUseCase interface
+func (du *diskCheckUsecase) GetStatus() string {
+	du.mutex.Lock()
+	defer du.mutex.Unlock()
+	return du.status.String()
+}
+
+// GetLastHistory return disk check history produced in last check process
+// Implement GetLastHistory method of domain.DiskCheckUseCase interface
+func (du *diskCheckUsecase) GetLastHistory() *domain.DiskCheckHistory {
+	du.mutex.Lock()
+	defer du.mutex.Unlock()
+	return du.lastHistory
+}
+
+// setLastHistory set lastHistory field value using mutex Lock & Unlock
+func (du *diskCheckUsecase) setLastHistory(history *domain.DiskCheckHistory) {
+	du.mutex.Lock()
+	defer du.mutex.Unlock()
+	du.lastHistory = history
+}
+
+// GetHistory return disk check history having uuid in history repository (nil if not exist)
+// Implement GetHistory method of domain.DiskCheckUseCase interface
+func (du *diskCheckUsecase) GetHistory(ctx context.Context, uuid string) (history *domain.DiskCheckHistory, err error) {
+	if history, err = du.historyRepo.GetByUUID(ctx, uuid); err != nil {
+		err = errors.Wrapf(err, "failed to get disk check history, uuid: %s", uuid)
+	}
+	return
+}
+
+// FindHistories return disk check histories stored between start & end, filtered by process level if level is not empty
+// Implement FindHistories method of domain.DiskCheckUseCase interface
+func (du *diskCheckUsecase) FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*domain.DiskCheckHistory, err error) {
+	if level == "" {
+		histories, err = du.historyRepo.FindByTimeRange(ctx, start, end)
+	} else {
+		histories, err = du.historyRepo.FindByProcessLevel(ctx, level, start, end)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to find disk check histories")
+	}
+	return
+}
+
+// FindLatestHistories return latest n disk check histories in history repository in order of newest first
+// Implement FindLatestHistories method of domain.DiskCheckUseCase interface
+func (du *diskCheckUsecase) FindLatestHistories(ctx context.Context, n int) (histories []*domain.DiskCheckHistory, err error) {
+	if histories, err = du.historyRepo.FindLatest(ctx, n); err != nil {
+		err = errors.Wrap(err, "failed to find latest disk check histories")
+	}
+	return
+}
+
+// recordMetrics record metrics about disk check process & disk capacity in history using metric agency
+func (du *diskCheckUsecase) recordMetrics(history *domain.DiskCheckHistory, elapsed time.Duration) {
+	var statuses []string
+	for s := diskStatusHealthy; s <= diskStatusUnhealthy; s++ {
+		statuses = append(statuses, s.String())
+	}
+	recordCommonMetrics(du.metricAgency, "disk", history.ProcessLevel.String(), elapsed, du.GetStatus(), statuses)
+
+	if level := history.ProcessLevel.String(); level == errorLevel || level == skippedLevel {
+		return
+	}
+	du.metricAgency.SetGauge("syscheck_disk_remaining_capacity_bytes", nil, float64(history.RemainingCap))
 }
@@ -11,7 +11,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/inhies/go-bytesize"
 	"github.com/pkg/errors"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/DMS-SMS/v1-health-check/domain"
 )
@@ -25,6 +27,24 @@ const (
 	memoryStatusUnhealthy                           // represent memory check status is unhealthy
 )
 
+// String method return string represent memory check status, which is used in delivery layer
+func (s memoryCheckStatus) String() string {
+	switch s {
+	case memoryStatusHealthy:
+		return "HEALTHY"
+	case memoryStatusWarning:
+		return "WARNING"
+	case memoryStatusRecovering:
+		return "RECOVERING"
+	case memoryStatusUnhealthy:
+		return "UNHEALTHY"
+	}
+	return "UNKNOWN"
+}
+
+// memoryCheckKey is key identifying memory check in status store agency & maintenance window
+const memoryCheckKey = "syscheck.memory"
+
 // memoryCheckUsecase implement MemoryCheckUsecase interface in domain and used in delivery layer
 type memoryCheckUsecase struct {
 	// myCfg is used for getting memory check usecase config
@@ -33,8 +53,20 @@ type memoryCheckUsecase struct {
 	// historyRepo is used for store memory check history and injected from outside
 	historyRepo domain.MemoryCheckHistoryRepository
 
-	// slackChat is used for agent slack API about chatting
-	slackChatAgency slackChatAgency
+	// notifierAgency is used for agent notifiers delivering alert
+	notifierAgency notifierAgency
+
+	// metricAgency is used as agency about metric collector to expose numbers computed in check process
+	metricAgency metricAgency
+
+	// statusStoreAgency is used as agency about status store to keep status of state machine across restart
+	statusStoreAgency statusStoreAgency
+
+	// maintenanceAgency is used for checking if check or container is in maintenance window
+	maintenanceAgency maintenanceAgency
+
+	// escalationAgency is used for escalating incident of memory check staying unhealthy long
+	escalationAgency escalationAgency
 
 	// memorySysAgency is used as agency about memory system command
 	memorySysAgency memorySysAgency
@@ -45,8 +77,20 @@ type memoryCheckUsecase struct {
 	// status represent current process status of memory health check
 	status memoryCheckStatus
 
+	// statusChangedAt represent time when status field value was changed last
+	statusChangedAt time.Time
+
+	// unhealthyCycles represent how many check cycles have run since status was changed to unhealthy
+	unhealthyCycles int
+
+	// lastHistory represent memory check history produced in last check process
+	lastHistory *domain.MemoryCheckHistory
+
 	// mutex help to prevent race condition when set status field value
 	mutex sync.Mutex
+
+	// runLocker help to prevent memory check process from running at the same time
+	runLocker checkRunLocker
 }
 
 // memoryCheckUsecaseConfig is the config getter interface for memory check usecase
@@ -54,6 +98,9 @@ type memoryCheckUsecaseConfig interface {
 	// get common config method from embedding systemCheckUsecaseComponentConfig
 	systemCheckUsecaseComponentConfig
 
+	// MemoryCheckTimeOut method returns time.Duration represent deadline of one memory check process
+	MemoryCheckTimeOut() time.Duration
+
 	// MemoryWarningUsage method returns bytesize.ByteSize represent memory warning usage
 	MemoryWarningUsage() bytesize.ByteSize
 
@@ -67,10 +114,10 @@ type memoryCheckUsecaseConfig interface {
 // memorySysAgency is agency that agent various command about memory system
 type memorySysAgency interface {
 	// GetTotalSystemMemoryUsage return total memory usage as bytesize in system
-	GetTotalSystemMemoryUsage() (usage bytesize.ByteSize, err error)
+	GetTotalSystemMemoryUsage(ctx context.Context) (usage bytesize.ByteSize, err error)
 
 	// CalculateContainersMemoryUsage calculate container memory usage & return result interface implementation
-	CalculateContainersMemoryUsage() (result interface {
+	CalculateContainersMemoryUsage(ctx context.Context) (result interface {
 		// TotalMemoryUsage return total memory usage in docker containers
 		TotalMemoryUsage() (usage bytesize.ByteSize)
 
@@ -83,34 +130,100 @@ type memorySysAgency interface {
 func NewMemoryCheckUsecase(
 	cfg memoryCheckUsecaseConfig,
 	mhr domain.MemoryCheckHistoryRepository,
-	sca slackChatAgency,
+	na notifierAgency,
+	ma metricAgency,
+	ssa statusStoreAgency,
+	mta maintenanceAgency,
+	eca escalationAgency,
 	msa memorySysAgency,
 	da dockerAgency,
 ) domain.MemoryCheckUseCase {
-	return &memoryCheckUsecase{
+	usecase := &memoryCheckUsecase{
 		// initialize field with parameter received from caller
-		myCfg:           cfg,
-		historyRepo:     mhr,
-		slackChatAgency: sca,
-		memorySysAgency: msa,
-		dockerAgency:    da,
+		myCfg:             cfg,
+		historyRepo:       mhr,
+		notifierAgency:    na,
+		metricAgency:      ma,
+		statusStoreAgency: ssa,
+		maintenanceAgency: mta,
+		escalationAgency:  eca,
+		memorySysAgency:   msa,
+		dockerAgency:      da,
 
 		// initialize field with default value
-		status: memoryStatusHealthy,
-		mutex:  sync.Mutex{},
+		status:    memoryStatusHealthy,
+		mutex:     sync.Mutex{},
+		runLocker: newCheckRunLocker(),
 	}
+
+	usecase.loadStatus()
+	return usecase
 }
 
 // CheckMemory check memory health with CheckMemory method & store check history in repository
 // Implement CheckMemory method of domain.MemoryCheckUseCase interface
-func (mu *memoryCheckUsecase) CheckMemory(ctx context.Context) error {
-	history := mu.checkMemory(ctx)
+func (mu *memoryCheckUsecase) CheckMemory(ctx context.Context) (history *domain.MemoryCheckHistory, err error) {
+	start := time.Now()
+	if mu.runLocker.lock(ctx, mu.myCfg.CheckOverlapPolicy()) {
+		toCtx, cancel := context.WithTimeout(ctx, mu.myCfg.MemoryCheckTimeOut())
+		history = mu.checkMemory(toCtx)
+		if toCtx.Err() != nil {
+			if history.ProcessLevel.Last() != errorLevel {
+				history.ProcessLevel.Append(errorLevel)
+			}
+			switch {
+			case history.Error != nil:
+			case toCtx.Err() == context.DeadlineExceeded:
+				history.SetError(errors.Wrapf(toCtx.Err(), "memory check process is timed out (%s)", mu.myCfg.MemoryCheckTimeOut()))
+			default:
+				history.SetError(errors.Wrap(toCtx.Err(), "memory check process is canceled"))
+			}
+		}
+		cancel()
+		mu.runLocker.unlock()
+		mu.setLastHistory(history)
+	} else {
+		history = mu.skippedHistory()
+	}
+	mu.recordMetrics(history, time.Since(start))
 
 	if b, err := mu.historyRepo.Store(history); err != nil {
-		return errors.Wrapf(err, "failed to store memory check history, response: %s", string(b))
+		return history, errors.Wrapf(err, "failed to store memory check history, response: %s", string(b))
 	}
 
-	return nil
+	return history, nil
+}
+
+// skippedHistory return memory check history represent that check process is skipped as same process is already running
+func (mu *memoryCheckUsecase) skippedHistory() (history *domain.MemoryCheckHistory) {
+	history = new(domain.MemoryCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = uuid.New().String()
+	history.ProcessLevel.Set(skippedLevel)
+	history.Message = "memory check is skipped as previous memory check process is still running"
+	return
+}
+
+// newAlert return alert about memory check history with thresholds configured in memory check config
+func (mu *memoryCheckUsecase) newAlert(history *domain.MemoryCheckHistory, level domain.AlertLevel, text string) (alert *domain.Alert) {
+	alert = history.NewAlert(level, text)
+	alert.Thresholds["memory_warning_usage"] = mu.myCfg.MemoryWarningUsage()
+	alert.Thresholds["memory_maximum_usage"] = mu.myCfg.MemoryMaximumUsage()
+	alert.Thresholds["memory_minimum_usage_to_remove"] = mu.myCfg.MemoryMinimumUsageToRemove()
+	return
+}
+
+// notify deliver alert about memory check history to notifier agency, except when memory check is in maintenance window
+// if alert is suppressed by maintenance window, id of that window is set in history
+// reason is stable code of why alert is produced (Ex, container_remove_failed), used for deduplicating it in notifier
+func (mu *memoryCheckUsecase) notify(history *domain.MemoryCheckHistory, level domain.AlertLevel, reason, text string) (t time.Time, _text string, err error) {
+	if id := mu.maintenanceAgency.GetActiveWindow(memoryCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+	alert := mu.newAlert(history, level, text)
+	alert.Reason = reason
+	return mu.notifierAgency.Notify(alert)
 }
 
 // method with below logic about handling health check process according to current memory check status
@@ -129,12 +242,12 @@ func (mu *memoryCheckUsecase) checkMemory(ctx context.Context) (history *domain.
 	history.FillPrivateComponent()
 	history.UUID = _uuid
 
-	_totalUsage, err := mu.memorySysAgency.GetTotalSystemMemoryUsage()
+	_totalUsage, err := mu.memorySysAgency.GetTotalSystemMemoryUsage(ctx)
 	if err != nil {
 		history.ProcessLevel.Set(errorLevel)
 		history.SetError(errors.Wrap(err, "failed to get total system memory usage"))
 		msg := "!memory check error occurred! unable to get total memory usage"
-		history.SetAlarmResult(mu.slackChatAgency.SendMessage("x", msg, _uuid))
+		history.SetAlarmResult(mu.notify(history, domain.AlertLevelError, "usage_unavailable", msg))
 		return
 	}
 	history.TotalUsageMemory = _totalUsage
@@ -157,61 +270,79 @@ func (mu *memoryCheckUsecase) checkMemory(ctx context.Context) (history *domain.
 			history.ProcessLevel.Set(recoveredLevel)
 			history.Message = "memory check is recovered to be healthy"
 			msg := fmt.Sprintf("!memory check recovered to health! current memory usage - %s", totalUsage.V)
-			_, _, _ = mu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = mu.notify(history, domain.AlertLevelRecovered, "recovered", msg)
+			mu.escalationAgency.CloseEscalation(memoryCheckKey, mu.newAlert(history, domain.AlertLevelRecovered, msg))
 		} else {
 			history.ProcessLevel.Set(unhealthyLevel)
 			history.Message = "memory check is unhealthy now"
+			mu.escalate(history)
 		}
 		return
 	}
 
+	// suppress remediation & alarm if memory check is in maintenance window, history is still stored with measured value
+	if id := mu.maintenanceAgency.GetActiveWindow(memoryCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		history.ProcessLevel.Set(maintenanceLevel)
+		history.Message = "memory check is in maintenance window, so remediation & alarm are suppressed"
+		return
+	}
+
 	if totalUsage.isMoreThan(mu.myCfg.MemoryMaximumUsage()) {
 		mu.setStatus(memoryStatusRecovering)
 		history.ProcessLevel.Set(weakDetectedLevel)
 		msg := fmt.Sprintf("!memory check weak detected! start to provision memory (current memory usage - %s)", totalUsage.V)
-		history.SetAlarmResult(mu.slackChatAgency.SendMessage("pill", msg, _uuid))
+		history.SetAlarmResult(mu.notify(history, domain.AlertLevelWeakDetected, "provisioning_started", msg))
 
-		result, err := mu.memorySysAgency.CalculateContainersMemoryUsage()
+		result, err := mu.memorySysAgency.CalculateContainersMemoryUsage(ctx)
 		if err != nil {
 			mu.setStatus(memoryStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!memory check error occurred! failed to calculate container memory, please check for yourself"
-			_, _, _ = mu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = mu.notify(history, domain.AlertLevelRecoveryError, "container_usage_unavailable", msg)
 			history.SetError(errors.Wrap(err, "failed to calculate containers memory usage"))
 			return
 		}
 		history.DockerUsageMemory = result.TotalMemoryUsage()
 
 		id, name, _usage := result.MostConsumerExceptFor(requiredContainers)
+		if window := mu.maintenanceAgency.GetActiveWindow("", name); window != "" {
+			// most consumer is in maintenance window, so pick next consumer except for containers in maintenance window
+			history.SetMaintenanceWindow(window)
+			except := append(append([]string{}, requiredContainers...), mu.maintenanceAgency.GetActiveContainers()...)
+			id, name, _usage = result.MostConsumerExceptFor(except)
+		}
 		history.MostMemoryConsumeContainer = name
 		usage := bytesizeComparator{V: _usage}
 
 		if usage.isLessThan(mu.myCfg.MemoryMinimumUsageToRemove()) {
 			mu.setStatus(memoryStatusUnhealthy)
 			msg := "!memory check error occurred! memory usage is too small to remove, please check for yourself"
-			_, _, _ = mu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = mu.notify(history, domain.AlertLevelRecoveryError, "usage_too_small_to_remove", msg)
 			history.SetError(errors.New("memory usage is too small to remove"))
 			return
 		}
 
-		if err := mu.dockerAgency.RemoveContainer(id, types.ContainerRemoveOptions{Force: true}); err != nil {
+		if err := mu.dockerAgency.RemoveContainer(ctx, id, types.ContainerRemoveOptions{Force: true}); err != nil {
 			mu.setStatus(memoryStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!memory check error occurred! failed to remove container, please check for yourself"
-			_, _, _ = mu.slackChatAgency.SendMessage("anger", msg, _uuid)
+			_, _, _ = mu.notify(history, domain.AlertLevelRecoveryError, "container_remove_failed", msg)
 			history.SetError(errors.Wrap(err, "failed to remove container"))
 			return
 		} else {
+			mu.metricAgency.AddCounter(checkRemediationsMetric, remediationLabels("memory", "container_removed"), 1)
 			history.TemporaryFreeMemory = usage.V
+			history.RemovedContainer = name
 			history.Message = "removed most memory consumed container as memory usage is over than maximum"
 		}
 
-		_againTotalUsage, err := mu.memorySysAgency.GetTotalSystemMemoryUsage()
+		_againTotalUsage, err := mu.memorySysAgency.GetTotalSystemMemoryUsage(ctx)
 		if err != nil {
 			mu.setStatus(memoryStatusUnhealthy)
 			history.ProcessLevel.Append(errorLevel)
 			msg := "!memory check error occurred! failed to again calculate container memory, please check for yourself"
-			_, _, _ = mu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = mu.notify(history, domain.AlertLevelUnhealthy, "usage_unavailable_after_remediation", msg)
 			history.SetError(errors.Wrap(err, "failed to again calculate containers memory usage"))
 			return
 		}
@@ -220,11 +351,11 @@ func (mu *memoryCheckUsecase) checkMemory(ctx context.Context) (history *domain.
 		if againTotalUsage.isLessThan(mu.myCfg.MemoryMaximumUsage()) {
 			mu.setStatus(memoryStatusHealthy)
 			msg := fmt.Sprintf("!memory check is healthy! current memory usage - %s", againTotalUsage.V)
-			_, _, _ = mu.slackChatAgency.SendMessage("heart", msg, _uuid)
+			_, _, _ = mu.notify(history, domain.AlertLevelRecovered, "recovered_by_remediation", msg)
 		} else {
 			mu.setStatus(memoryStatusUnhealthy)
 			msg := "!memory check has deteriorated! please check for yourself"
-			_, _, _ = mu.slackChatAgency.SendMessage("broken_heart", msg, _uuid)
+			_, _, _ = mu.notify(history, domain.AlertLevelUnhealthy, "deteriorated", msg)
 		}
 	} else if totalUsage.isMoreThan(mu.myCfg.MemoryWarningUsage()) {
 		history.ProcessLevel.Set(warningLevel)
@@ -232,7 +363,7 @@ func (mu *memoryCheckUsecase) checkMemory(ctx context.Context) (history *domain.
 		if mu.status != memoryStatusWarning {
 			mu.setStatus(memoryStatusWarning)
 			msg := fmt.Sprintf("!memory check warning! current memory usage - %s", totalUsage.V)
-			history.SetAlarmResult(mu.slackChatAgency.SendMessage("warning", msg, _uuid))
+			history.SetAlarmResult(mu.notify(history, domain.AlertLevelWarning, "warning", msg))
 		}
 	} else {
 		history.ProcessLevel.Set(healthyLevel)
@@ -242,9 +373,170 @@ func (mu *memoryCheckUsecase) checkMemory(ctx context.Context) (history *domain.
 	return
 }
 
+// ResetStatus reset status of memory check to healthy after administrator fixed problem & store history about that reset
+// it waits until running memory check process is finished, so that reset status is not overwritten by that process
+// Implement ResetStatus method of domain.MemoryCheckUseCase interface
+func (mu *memoryCheckUsecase) ResetStatus(ctx context.Context, by, reason string) (history *domain.MemoryCheckHistory, err error) {
+	if !mu.runLocker.lock(ctx, overlapPolicyQueue) {
+		err = errors.Wrap(ctx.Err(), "context done while waiting running memory check process")
+		return
+	}
+	defer mu.runLocker.unlock()
+
+	_uuid := uuid.New().String()
+	history = new(domain.MemoryCheckHistory)
+	history.FillPrivateComponent()
+	history.UUID = _uuid
+	history.ProcessLevel.Set(resetLevel)
+	history.SetResetResult(by, reason)
+	history.Message = fmt.Sprintf("memory check status is reset to healthy from %s", mu.GetStatus())
+
+	mu.setStatus(memoryStatusHealthy)
+	msg := fmt.Sprintf("!memory check status reset! reset to healthy by %s (reason - %s)", by, reason)
+	alert := mu.newAlert(history, domain.AlertLevelReset, msg)
+	history.SetAlarmResult(mu.notifierAgency.Notify(alert))
+	mu.escalationAgency.CloseEscalation(memoryCheckKey, alert)
+
+	if b, err := mu.historyRepo.Store(history); err != nil {
+		return history, errors.Wrapf(err, "failed to store memory check history, response: %s", string(b))
+	}
+
+	return history, nil
+}
+
+// escalate count cycle of memory check staying unhealthy & escalate incident with escalation agency if rule is satisfied
+func (mu *memoryCheckUsecase) escalate(history *domain.MemoryCheckHistory) {
+	mu.mutex.Lock()
+	mu.unhealthyCycles++
+	cycles, since := mu.unhealthyCycles, mu.statusChangedAt
+	mu.mutex.Unlock()
+
+	// incident in maintenance window is not escalated, cycles are still counted to escalate after window ends
+	if id := mu.maintenanceAgency.GetActiveWindow(memoryCheckKey, ""); id != "" {
+		history.SetMaintenanceWindow(id)
+		return
+	}
+
+	msg := fmt.Sprintf("!memory check escalated! still unhealthy after %d cycles, please check for yourself", cycles)
+	history.SetAlarmResult(mu.escalationAgency.Escalate(memoryCheckKey, since, cycles, mu.newAlert(history, domain.AlertLevelEscalated, msg)))
+}
+
 // setStatus set status field value using mutex Lock & Unlock
+// status is stored in status store only if changed, after unlocking mutex so that slow store doesn't block GetStatus
 func (mu *memoryCheckUsecase) setStatus(status memoryCheckStatus) {
 	mu.mutex.Lock()
-	defer mu.mutex.Unlock()
+	changed := mu.status != status
+	if changed {
+		mu.statusChangedAt = domain.Now()
+		mu.unhealthyCycles = 0
+	}
 	mu.status = status
+	transitedAt := mu.statusChangedAt
+	mu.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+	if err := mu.statusStoreAgency.StoreStatus(ctx, memoryCheckKey, status.String(), transitedAt); err != nil {
+		log.Printf("failed to store memory check status in status store, err: %v", err)
+	}
+}
+
+// loadStatus load status of memory check saved in status store, so that status survives restart
+// recovering status is loaded as unhealthy, because recovering process was stopped by restart & should be checked
+func (mu *memoryCheckUsecase) loadStatus() {
+	ctx, cancel := context.WithTimeout(context.Background(), statusStoreTimeout)
+	defer cancel()
+
+	saved, transitedAt, err := mu.statusStoreAgency.LoadStatus(ctx, memoryCheckKey)
+	if err != nil {
+		log.Printf("failed to load memory check status from status store, err: %v", err)
+		return
+	}
+
+	for status := memoryStatusHealthy; status <= memoryStatusUnhealthy; status++ {
+		if status.String() != saved {
+			continue
+		}
+		if status == memoryStatusRecovering {
+			status = memoryStatusUnhealthy
+		}
+		mu.status, mu.statusChangedAt = status, transitedAt
+		return
+	}
+}
+
+// GetStatus return current status of memory check process as string
+// Implement GetStatus method of domain.MemoryCheckUseCase interface
+func (mu *memoryCheckUsecase) GetStatus() string {
+	mu.mutex.Lock()
+	defer mu.mutex.Unlock()
+	return mu.status.String()
+}
+
+// GetLastHistory return memory check history produced in last check process
+// Implement GetLastHistory method of domain.MemoryCheckUseCase interface
+func (mu *memoryCheckUsecase) GetLastHistory() *domain.MemoryCheckHistory {
+	mu.mutex.Lock()
+	defer mu.mutex.Unlock()
+	return mu.lastHistory
+}
+
+// setLastHistory set lastHistory field value using mutex Lock & Unlock
+func (mu *memoryCheckUsecase) setLastHistory(history *domain.MemoryCheckHistory) {
+	mu.mutex.Lock()
+	defer mu.mutex.Unlock()
+	mu.lastHistory = history
+}
+
+// GetHistory return memory check history having uuid in history repository (nil if not exist)
+// Implement GetHistory method of domain.MemoryCheckUseCase interface
+func (mu *memoryCheckUsecase) GetHistory(ctx context.Context, uuid string) (history *domain.MemoryCheckHistory, err error) {
+	if history, err = mu.historyRepo.GetByUUID(ctx, uuid); err != nil {
+		err = errors.Wrapf(err, "failed to get memory check history, uuid: %s", uuid)
+	}
+	return
+}
+
+// FindHistories return memory check histories stored between start & end, filtered by process level if level is not empty
+// Implement FindHistories method of domain.MemoryCheckUseCase interface
+func (mu *memoryCheckUsecase) FindHistories(ctx context.Context, level string, start, end time.Time) (histories []*domain.MemoryCheckHistory, err error) {
+	if level == "" {
+		histories, err = mu.historyRepo.FindByTimeRange(ctx, start, end)
+	} else {
+		histories, err = mu.historyRepo.FindByProcessLevel(ctx, level, start, end)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to find memory check histories")
+	}
+	return
+}
+
+// FindLatestHistories return latest n memory check histories in history repository in order of newest first
+// Implement FindLatestHistories method of domain.MemoryCheckUseCase interface
+func (mu *memoryCheckUsecase) FindLatestHistories(ctx context.Context, n int) (histories []*domain.MemoryCheckHistory, err error) {
+	if histories, err = mu.historyRepo.FindLatest(ctx, n); err != nil {
+		err = errors.Wrap(err, "failed to find latest memory check histories")
+	}
+	return
+}
+
+// recordMetrics record metrics about memory check process & memory usage in history using metric agency
+func (mu *memoryCheckUsecase) recordMetrics(history *domain.MemoryCheckHistory, elapsed time.Duration) {
+	var statuses []string
+	for s := memoryStatusHealthy; s <= memoryStatusUnhealthy; s++ {
+		statuses = append(statuses, s.String())
+	}
+	recordCommonMetrics(mu.metricAgency, "memory", history.ProcessLevel.String(), elapsed, mu.GetStatus(), statuses)
+
+	if level := history.ProcessLevel.String(); level == errorLevel || level == skippedLevel {
+		return
+	}
+	mu.metricAgency.SetGauge("syscheck_memory_total_usage_bytes", nil, float64(history.TotalUsageMemory))
+	if history.DockerUsageMemory != 0 {
+		mu.metricAgency.SetGauge("syscheck_memory_docker_usage_bytes", nil, float64(history.DockerUsageMemory))
+	}
 }
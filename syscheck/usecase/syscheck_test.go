@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockConcurrently call lock of locker with policy in n goroutines at the same time & return channel receiving results
+func lockConcurrently(ctx context.Context, locker checkRunLocker, policy string, n int) <-chan bool {
+	results := make(chan bool, n)
+	start := sync.WaitGroup{}
+	start.Add(1)
+	for i := 0; i < n; i++ {
+		go func() {
+			start.Wait()
+			results <- locker.lock(ctx, policy)
+		}()
+	}
+	start.Done()
+	return results
+}
+
+// receiveResults receive results from channel until timeout & return count of acquired & not acquired lock
+func receiveResults(results <-chan bool, timeout time.Duration) (acquired, rejected int) {
+	for {
+		select {
+		case ok := <-results:
+			if ok {
+				acquired++
+			} else {
+				rejected++
+			}
+		case <-time.After(timeout):
+			return
+		}
+	}
+}
+
+func TestCheckRunLockerOverlappingRuns(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		policy   string
+		runs     int
+		rejected int // runs returned false immediately while first run is running
+		queued   int // runs waiting first run & acquiring lock after it is finished
+	}{
+		{name: "skip policy skips every overlapping run", policy: overlapPolicySkip, runs: 3, rejected: 3, queued: 0},
+		{name: "queue policy queues one run", policy: overlapPolicyQueue, runs: 1, rejected: 0, queued: 1},
+		{name: "queue policy skips runs over cap", policy: overlapPolicyQueue, runs: 4, rejected: 4 - maxQueuedChecks, queued: maxQueuedChecks},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			locker := newCheckRunLocker()
+			if !locker.lock(context.Background(), tc.policy) {
+				t.Fatal("first run should acquire lock")
+			}
+
+			results := lockConcurrently(context.Background(), locker, tc.policy, tc.runs)
+			acquired, rejected := receiveResults(results, time.Millisecond*100)
+			if acquired != 0 || rejected != tc.rejected {
+				t.Fatalf("while first run is running, %d runs should be rejected & none acquire lock, got: rejected %d, acquired %d",
+					tc.rejected, rejected, acquired)
+			}
+
+			// release lock of every run acquiring it, so that every queued run can run in turn
+			queued := 0
+			locker.unlock()
+			for queued < tc.runs-tc.rejected {
+				select {
+				case ok := <-results:
+					if !ok {
+						t.Fatal("queued run should acquire lock after running one is finished")
+					}
+					queued++
+					locker.unlock()
+				case <-time.After(time.Second):
+					t.Fatalf("queued run should acquire lock after running one is finished, got: %d queued runs", queued)
+				}
+			}
+			if queued != tc.queued {
+				t.Errorf("%d runs should be queued, got: %d", tc.queued, queued)
+			}
+		})
+	}
+}
+
+func TestCheckRunLockerReleasesWaiterOnContextDone(t *testing.T) {
+	locker := newCheckRunLocker()
+	if !locker.lock(context.Background(), overlapPolicyQueue) {
+		t.Fatal("first run should acquire lock")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := lockConcurrently(ctx, locker, overlapPolicyQueue, 1)
+	if acquired, rejected := receiveResults(results, time.Millisecond*100); acquired+rejected != 0 {
+		t.Fatal("queued run should wait while first run is running")
+	}
+
+	cancel()
+	select {
+	case ok := <-results:
+		if ok {
+			t.Fatal("queued run should not acquire lock when context is canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued run should stop waiting when context is canceled")
+	}
+
+	// slot of canceled waiter should be released, so that another run can be queued again
+	results = lockConcurrently(context.Background(), locker, overlapPolicyQueue, 1)
+	if acquired, rejected := receiveResults(results, time.Millisecond*100); acquired+rejected != 0 {
+		t.Fatal("run should be queued again after canceled waiter released its slot")
+	}
+	locker.unlock()
+	if ok := <-results; !ok {
+		t.Error("queued run should acquire lock after running one is finished")
+	}
+	locker.unlock()
+}
@@ -15,13 +15,18 @@ import (
 )
 
 // GetTotalSystemCPUUsage return total cpu usage as core count in system
-func (sa *sysAgent) GetTotalSystemCPUUsage() (usage float64, err error) {
+func (sa *sysAgent) GetTotalSystemCPUUsage(ctx context.Context) (usage float64, err error) {
 	before, err := cpu.Get()
 	if err != nil {
 		err = errors.Wrap(err, "failed to get before cpu usage")
 		return
 	}
-	time.Sleep(time.Duration(1) * time.Second)
+	select {
+	case <-time.After(time.Duration(1) * time.Second):
+	case <-ctx.Done():
+		err = errors.Wrap(ctx.Err(), "context done while measuring cpu usage")
+		return
+	}
 	after, err := cpu.Get()
 	if err != nil {
 		err = errors.Wrap(err, "failed to get after cpu usage")
@@ -35,12 +40,11 @@ func (sa *sysAgent) GetTotalSystemCPUUsage() (usage float64, err error) {
 }
 
 // CalculateContainersCPUUsage calculate cpu usage & return calculateContainersCPUUsageResult
-func (sa *sysAgent) CalculateContainersCPUUsage() (interface {
+func (sa *sysAgent) CalculateContainersCPUUsage(ctx context.Context) (interface {
 	TotalCPUUsage() (usage float64)
 	MostConsumerExceptFor([]string) (id, name string, usage float64)
 }, error) {
 	var (
-		ctx    = context.Background()
 		result = calculateContainersCPUUsageResult{}
 	)
 
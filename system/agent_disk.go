@@ -15,9 +15,14 @@ import (
 )
 
 // GetRemainDiskCapacity return remain disk capacity expressed in bytesize package
-func (sa *sysAgent) GetRemainDiskCapacity() (size bytesize.ByteSize, err error) {
+func (sa *sysAgent) GetRemainDiskCapacity(ctx context.Context) (size bytesize.ByteSize, err error) {
 	var stat unix.Statfs_t
 
+	if err = ctx.Err(); err != nil {
+		err = errors.Wrap(err, "context done before getting disk capacity")
+		return
+	}
+
 	wd, err := os.Getwd()
 	if err != nil {
 		err = errors.Wrap(err, "failed to call os.Getwd")
@@ -35,9 +40,8 @@ func (sa *sysAgent) GetRemainDiskCapacity() (size bytesize.ByteSize, err error)
 }
 
 // PruneDockerSystem prune docker system(build cache, containers, images, networks) and return reclaimed space size
-func (sa *sysAgent) PruneDockerSystem() (reclaimed bytesize.ByteSize, err error) {
+func (sa *sysAgent) PruneDockerSystem(ctx context.Context) (reclaimed bytesize.ByteSize, err error) {
 	var (
-		ctx = context.Background()
 		args = filters.Args{}
 	)
 
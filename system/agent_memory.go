@@ -14,7 +14,12 @@ import (
 )
 
 // GetTotalSystemMemoryUsage return total memory usage as bytesize in system
-func (sa *sysAgent) GetTotalSystemMemoryUsage() (usage bytesize.ByteSize, err error) {
+func (sa *sysAgent) GetTotalSystemMemoryUsage(ctx context.Context) (usage bytesize.ByteSize, err error) {
+	if err = ctx.Err(); err != nil {
+		err = errors.Wrap(err, "context done before getting memory stats")
+		return
+	}
+
 	stats, err := memory.Get()
 	if err != nil {
 		err = errors.Wrap(err, "failed to get memory stats")
@@ -26,12 +31,11 @@ func (sa *sysAgent) GetTotalSystemMemoryUsage() (usage bytesize.ByteSize, err er
 }
 
 // CalculateContainersCPUUsage calculate memory usage & return calculateContainersMemoryUsageResult
-func (sa *sysAgent) CalculateContainersMemoryUsage() (interface {
+func (sa *sysAgent) CalculateContainersMemoryUsage(ctx context.Context) (interface {
 	TotalMemoryUsage() (usage bytesize.ByteSize)
 	MostConsumerExceptFor(names []string) (id, name string, usage bytesize.ByteSize)
 }, error) {
 	var (
-		ctx    = context.Background()
 		result = calculateContainersMemoryUsageResult{}
 	)
 
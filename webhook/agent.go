@@ -0,0 +1,32 @@
+// webhook package define struct which is implement various interface about webhook agency using in each of domain
+// there are kind of webhook agency function such as post alert as json to webhook url, etc ...
+
+// in agent.go file, define struct type of webhook agent & initializer that are not method.
+// Also if exist, custom type or variable used in common in each of method will declared in this file.
+
+package webhook
+
+import (
+	"net/http"
+	"time"
+)
+
+// defaultTimeout is timeout of http client used in webhook agent to prevent check process blocked by webhook receiver
+const defaultTimeout = time.Second * 5
+
+// webhookAgent agent various request about webhook(post alert, etc ...) as implementation
+type webhookAgent struct {
+	// url is address of webhook receiver to send request
+	url string
+
+	// httpCli is http client used for sending request to webhook receiver
+	httpCli *http.Client
+}
+
+// NewAgent return new initialized instance of webhookAgent pointer type with url of webhook receiver
+func NewAgent(url string) *webhookAgent {
+	return &webhookAgent{
+		url:     url,
+		httpCli: &http.Client{Timeout: defaultTimeout},
+	}
+}
@@ -0,0 +1,66 @@
+// agent_notify.go file define method of webhookAgent about posting alert to webhook receiver
+// implement notifier interface about alert defined in each of domain
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+// alertPayload is json body posted to webhook receiver, converted from domain.Alert
+type alertPayload struct {
+	Domain       string                 `json:"domain"`
+	Type         string                 `json:"type"`
+	Level        domain.AlertLevel      `json:"level"`
+	UUID         string                 `json:"uuid"`
+	Reason       string                 `json:"reason,omitempty"`
+	Text         string                 `json:"text"`
+	Measurements map[string]interface{} `json:"measurements"`
+	Thresholds   map[string]interface{} `json:"thresholds"`
+	Mentions     []string               `json:"mentions,omitempty"`
+	Time         time.Time              `json:"time"`
+}
+
+// Notify post alert as json to webhook receiver and return posted time & text & error
+func (wa *webhookAgent) Notify(alert *domain.Alert) (t time.Time, text string, err error) {
+	body, err := json.Marshal(alertPayload{
+		Domain:       alert.Domain,
+		Type:         alert.Type,
+		Level:        alert.Level,
+		UUID:         alert.UUID,
+		Reason:       alert.Reason,
+		Text:         alert.Text,
+		Measurements: alert.Measurements,
+		Thresholds:   alert.Thresholds,
+		Mentions:     alert.Mentions,
+		Time:         alert.Timestamp,
+	})
+	if err != nil {
+		err = errors.Wrap(err, "failed to marshal alert to json")
+		return
+	}
+
+	resp, err := wa.httpCli.Post(wa.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		err = errors.Wrap(err, "failed to post alert to webhook")
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		err = errors.Errorf("webhook responded with unexpected status code %d", resp.StatusCode)
+		return
+	}
+
+	t, text = domain.Now(), alert.Text
+	return
+}
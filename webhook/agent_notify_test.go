@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DMS-SMS/v1-health-check/domain"
+)
+
+func newTestAlert() *domain.Alert {
+	return &domain.Alert{
+		Domain:       "syscheck",
+		Type:         "CPUCheck",
+		Level:        domain.AlertLevelUnhealthy,
+		UUID:         "uuid-1",
+		Reason:       "deteriorated",
+		Text:         "!cpu check has deteriorated! please check for yourself",
+		Measurements: map[string]interface{}{"total_usage_core": 2.5},
+		Thresholds:   map[string]interface{}{"maximum_usage_core": 2.0},
+		Mentions:     []string{"S01ABCDEF"},
+		Timestamp:    time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNotifyPostsAlertPayload(t *testing.T) {
+	var method, contentType string
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, contentType = r.Method, r.Header.Get("Content-Type")
+		b, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	alert := newTestAlert()
+	_, text, err := NewAgent(server.URL).Notify(alert)
+	if err != nil {
+		t.Fatalf("alert should be posted, err: %v", err)
+	}
+	if text != alert.Text {
+		t.Errorf("text of alert should be returned, got: %s", text)
+	}
+	if method != http.MethodPost || contentType != "application/json" {
+		t.Errorf("alert should be posted as json, got: %s %s", method, contentType)
+	}
+
+	want := map[string]interface{}{
+		"domain":       "syscheck",
+		"type":         "CPUCheck",
+		"level":        "UNHEALTHY",
+		"uuid":         "uuid-1",
+		"reason":       "deteriorated",
+		"text":         alert.Text,
+		"measurements": map[string]interface{}{"total_usage_core": 2.5},
+		"thresholds":   map[string]interface{}{"maximum_usage_core": 2.0},
+		"mentions":     []interface{}{"S01ABCDEF"},
+		"time":         "2021-03-01T09:00:00Z",
+	}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload should be %v, got: %v", want, payload)
+	}
+}
+
+func TestNotifyStatusCode(t *testing.T) {
+	for _, tc := range []struct {
+		code    int
+		wantErr bool
+	}{
+		{code: http.StatusOK},
+		{code: http.StatusAccepted},
+		{code: http.StatusMovedPermanently, wantErr: true},
+		{code: http.StatusBadRequest, wantErr: true},
+		{code: http.StatusInternalServerError, wantErr: true},
+	} {
+		t.Run(http.StatusText(tc.code), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.code >= 300 && tc.code < 400 {
+					w.Header().Set("Location", "/moved")
+				}
+				w.WriteHeader(tc.code)
+			}))
+			defer server.Close()
+
+			agent := NewAgent(server.URL)
+			agent.httpCli.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+			at, text, err := agent.Notify(newTestAlert())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("if error is returned should be %t, got: %v", tc.wantErr, err)
+			}
+			if tc.wantErr && (!at.IsZero() || text != "") {
+				t.Errorf("failed alert should return zero time & empty text, got: %s, %q", at, text)
+			}
+		})
+	}
+}
+
+func TestNotifyTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	agent := NewAgent(server.URL)
+	if agent.httpCli.Timeout != defaultTimeout {
+		t.Errorf("http client should have default timeout, got: %s", agent.httpCli.Timeout)
+	}
+	agent.httpCli.Timeout = time.Millisecond * 100
+
+	start := time.Now()
+	if _, _, err := agent.Notify(newTestAlert()); err == nil {
+		t.Fatal("alert to webhook not responding in timeout should return error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("notify should return after timeout, got: %s", elapsed)
+	}
+}